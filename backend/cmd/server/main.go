@@ -5,6 +5,8 @@ import (
 	"cmTranscribe/internal/infra/container"
 	"cmTranscribe/internal/interface/api"
 	"cmTranscribe/internal/routes"
+	"cmTranscribe/internal/shared/logger"
+	"cmTranscribe/internal/shared/middleware"
 	"context"
 	"errors"
 	"log"
@@ -30,30 +32,61 @@ func main() {
 		log.Fatalf("Failed to initialize app container: %v", err)
 	}
 
+	// カスタムボキャブラリの状態同期をバックグラウンドで開始
+	go appContainer.VocabularyStateSyncer.Run(ctx)
+
+	// 再起動前に送信を待っていた文字起こしジョブを待ち行列に戻し、送信をバックグラウンドで開始
+	if err := appContainer.SubmissionGovernor.Recover(ctx); err != nil {
+		log.Fatalf("Failed to recover transcription jobs waiting for submission: %v", err)
+	}
+	go appContainer.SubmissionGovernor.Run(ctx)
+
+	// 更新の無くなった再開可能なアップロードの破棄をバックグラウンドで開始
+	go appContainer.ResumableUploadService.Run(ctx)
+
 	// APIハンドラの設定（プレゼンテーション層）
 	transcriptionHandler := api.NewTranscriptionJobHandler(appContainer.TranscriptionJobService)
 	customVocabularyHandler := api.NewCustomVocabularyHandler(appContainer.CustomVocabularyService)
 	s3UploadHandler := api.NewS3UploadHandler(appContainer.S3UploadService)
+	suggestionHandler := api.NewVocabularySuggestionHandler(appContainer.SuggestionService)
+	vocabularyFilterHandler := api.NewVocabularyFilterHandler(appContainer.VocabularyFilterService)
+	reportHandler := api.NewVocabularyReportHandler(appContainer.ReportService)
+	resumableUploadHandler := api.NewResumableUploadHandler(appContainer.ResumableUploadService)
+	mediaLibraryHandler := api.NewMediaLibraryHandler(appContainer.MediaLibraryService)
+	var storageObjectHandler *api.StorageObjectHandler
+	if appContainer.StorageObjectService != nil {
+		storageObjectHandler = api.NewStorageObjectHandler(appContainer.StorageObjectService)
+	}
 
 	// ルーターの作成とルーティングの登録
 	r := routes.NewRouter(
 		transcriptionHandler,
 		customVocabularyHandler,
 		s3UploadHandler,
+		suggestionHandler,
+		vocabularyFilterHandler,
+		reportHandler,
+		resumableUploadHandler,
+		mediaLibraryHandler,
+		storageObjectHandler,
+		appContainer.Authenticator,
+		appContainer.TenantRepo,
+		appContainer.RateLimiter,
+		config.AppConfig.MetricsPath,
 	)
 
 	// ルートの登録
 	router := r.RegisterRoutes() // ルーティングを取得
 
-	// HTTPサーバーの作成
+	// HTTPサーバーの作成 (すべてのリクエストにリクエストIDを付与してアクセスログを出力する)
 	srv := &http.Server{
 		Addr:    ":" + config.AppConfig.Port,
-		Handler: router, // ルーターを設定
+		Handler: middleware.RequestIDMiddleware(router), // ルーターを設定
 	}
 
 	// サーバーの起動を別の goroutine で行う
 	go func() {
-		log.Printf("Starting server on :%s...\n", config.AppConfig.Port)
+		logger.Default().Info("Starting server", "port", config.AppConfig.Port)
 		if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
 			log.Fatalf("Failed to start server: %v", err)
 		}
@@ -61,7 +94,7 @@ func main() {
 
 	// シグナルを受け取ったらシャットダウン処理を開始
 	<-sigChan
-	log.Println("Shutting down server...")
+	logger.Default().Info("Shutting down server")
 
 	// Graceful shutdown: 5秒のタイムアウトを設定
 	ctxShutDown, cancel := context.WithTimeout(context.Background(), 5*time.Second)
@@ -71,5 +104,5 @@ func main() {
 		log.Fatalf("Server forced to shutdown: %v", err)
 	}
 
-	log.Println("Server exiting")
+	logger.Default().Info("Server exiting")
 }
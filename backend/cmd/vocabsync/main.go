@@ -0,0 +1,175 @@
+// vocabsync はディレクトリに置いたボキャブラリ定義ファイルをAmazon Transcribeに同期するコマンドです。
+//
+// 定義ファイルはフロントマターで名前と言語コードを指定したTSVファイルです。
+//
+//	---
+//	name: MyVocabulary01
+//	language: ja-JP
+//	---
+//	Phrase	IPA	SoundsLike	DisplayAs
+//	Amazon-Transcribe			Amazon Transcribe
+//
+// 使い方:
+//
+//	go run ./cmd/vocabsync -dir ./vocabularies [-tenant id] [-managed-prefix prefix] [-prune] [-yes] [-author name]
+//
+// -prune で削除するのは -managed-prefix で始まる名前のボキャブラリだけです。
+// -tenant を指定した場合は、そのテナントのボキャブラリだけを対象にします
+package main
+
+import (
+	"bufio"
+	"cmTranscribe/internal/domain/model"
+	"cmTranscribe/internal/infra/container"
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// definitionExtensions 定義ファイルとして読み込む拡張子
+var definitionExtensions = map[string]bool{".tsv": true, ".txt": true}
+
+func main() {
+	dir := flag.String("dir", "", "ボキャブラリ定義ファイルを置いたディレクトリ")
+	tenantID := flag.String("tenant", "", "同期するテナントのID (TENANTS_FILE を使う場合)")
+	managedPrefix := flag.String("managed-prefix", "", "vocabsyncで管理するボキャブラリ名の接頭辞 (-prune には必須)")
+	prune := flag.Bool("prune", false, "管理する接頭辞で始まる名前のうち、定義ファイルの無いボキャブラリを削除する")
+	yes := flag.Bool("yes", false, "確認せずに計画を適用する")
+	author := flag.String("author", "vocabsync", "バージョン履歴に記録する変更者")
+	flag.Parse()
+
+	if *dir == "" || (*prune && *managedPrefix == "") {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	ctx := context.Background()
+
+	// 定義ファイルの読み込み
+	definitions, err := loadDefinitions(*dir)
+	if err != nil {
+		log.Fatalf("Failed to load vocabulary definitions: %v", err)
+	}
+
+	// DIコンテナの初期化
+	appContainer, err := container.NewAppContainer(ctx)
+	if err != nil {
+		log.Fatalf("Failed to initialize app container: %v", err)
+	}
+
+	// テナントの指定
+	if *tenantID != "" {
+		if appContainer.TenantRepo == nil {
+			log.Fatalf("-tenant requires TENANTS_FILE to be set")
+		}
+		tenant, err := appContainer.TenantRepo.FindByID(*tenantID)
+		if err != nil {
+			log.Fatalf("Failed to find tenant %s: %v", *tenantID, err)
+		}
+		ctx = model.ContextWithTenant(ctx, tenant)
+	}
+
+	// 計画の作成と表示
+	plan, err := appContainer.SyncService.Plan(ctx, definitions, *prune, *managedPrefix)
+	if err != nil {
+		log.Fatalf("Failed to plan vocabulary sync: %v", err)
+	}
+	printPlan(plan)
+	if plan.IsEmpty() {
+		return
+	}
+
+	// 確認後に適用
+	if !*yes && !confirm("Apply this plan? [y/N]: ") {
+		fmt.Println("Canceled.")
+		return
+	}
+	if err := appContainer.SyncService.Apply(ctx, plan, *author); err != nil {
+		log.Fatalf("Failed to apply vocabulary sync: %v", err)
+	}
+	fmt.Println("Apply complete.")
+}
+
+// loadDefinitions ディレクトリ直下の定義ファイルをファイル名順に読み込みます
+func loadDefinitions(dir string) ([]*model.VocabularyDefinition, error) {
+	files, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var definitions []*model.VocabularyDefinition
+	for _, file := range files {
+		if file.IsDir() || !definitionExtensions[strings.ToLower(filepath.Ext(file.Name()))] {
+			continue
+		}
+		path := filepath.Join(dir, file.Name())
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		definition, err := model.ParseVocabularyDefinition(path, content)
+		if err != nil {
+			return nil, err
+		}
+		definitions = append(definitions, definition)
+	}
+	return definitions, nil
+}
+
+// printPlan 計画を表示します
+func printPlan(plan *model.VocabularySyncPlan) {
+	if plan.IsEmpty() {
+		fmt.Printf("No changes. %d vocabularies are up to date.\n", len(plan.Unchanged))
+		return
+	}
+
+	for _, action := range plan.Actions {
+		switch action.Action {
+		case model.SyncActionCreate:
+			fmt.Printf("+ create %s (%s, %d entries) from %s\n", action.Name, action.LanguageCode, len(action.Definition.Entries), action.Definition.Source)
+		case model.SyncActionUpdate:
+			fmt.Printf("~ update %s (%s) from %s\n", action.Name, action.LanguageCode, action.Definition.Source)
+			printDiff(action.Diff)
+		case model.SyncActionDelete:
+			fmt.Printf("- delete %s (%s)\n", action.Name, action.LanguageCode)
+		}
+	}
+	fmt.Printf("\nPlan: %d to create, %d to update, %d to delete, %d unchanged.\n",
+		plan.Count(model.SyncActionCreate), plan.Count(model.SyncActionUpdate), plan.Count(model.SyncActionDelete), len(plan.Unchanged))
+}
+
+// printDiff 更新される語彙の差分を表示します
+func printDiff(diffs []model.VocabularyEntryDiff) {
+	sort.SliceStable(diffs, func(i, j int) bool { return diffs[i].Change < diffs[j].Change })
+	for _, diff := range diffs {
+		switch diff.Change {
+		case model.EntryChangeAdded:
+			fmt.Printf("    + %s\n", formatEntry(diff.After))
+		case model.EntryChangeRemoved:
+			fmt.Printf("    - %s\n", formatEntry(diff.Before))
+		case model.EntryChangeModified:
+			fmt.Printf("    ~ %s => %s\n", formatEntry(diff.Before), formatEntry(diff.After))
+		}
+	}
+}
+
+// formatEntry 語彙1件をTSVの1行と同じ並びで表示用に整形します
+func formatEntry(entry *model.VocabularyEntry) string {
+	return strings.Join([]string{entry.Phrase, entry.IPA, entry.SoundsLike, entry.DisplayAs}, " | ")
+}
+
+// confirm 標準入力からyesの回答を受け取ったかどうかを返します
+func confirm(prompt string) bool {
+	fmt.Print(prompt)
+	answer, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil {
+		return false
+	}
+	answer = strings.ToLower(strings.TrimSpace(answer))
+	return answer == "y" || answer == "yes"
+}
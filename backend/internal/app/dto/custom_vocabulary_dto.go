@@ -4,16 +4,38 @@ import "time"
 
 // CreateVocabularyDto カスタムボキャブラリ作成時に使用するリクエストデータ
 type CreateVocabularyDto struct {
-	VocabularyName string       `json:"name"`          // ボキャブラリーの名前
-	LanguageCode   string       `json:"language_code"` // 言語コード
-	Vocabularies   []Vocabulary `json:"vocabularies"`  // ボキャブラリーの語彙リスト
+	VocabularyName     string            `json:"name"`                 // ボキャブラリーの名前
+	LanguageCode       string            `json:"language_code"`        // 言語コード
+	Vocabularies       []Vocabulary      `json:"vocabularies"`         // ボキャブラリーの語彙リスト
+	Author             string            `json:"author"`               // 変更者 (バージョン履歴に記録)
+	GenerateSoundsLike bool              `json:"generate_sounds_like"` // SoundsLike, IPAが空の語彙の読みを自動生成する (ja-JPのみ)
+	ReadingOverrides   map[string]string `json:"reading_overrides"`    // 読みを自動生成する際の語ごとの上書き
 }
 
 // UpdateVocabularyDto カスタムボキャブラリ更新時に使用するリクエストデータ
 type UpdateVocabularyDto struct {
-	VocabularyName string       `json:"name"`          // ボキャブラリーの名前
-	LanguageCode   string       `json:"language_code"` // 言語コード
-	Vocabularies   []Vocabulary `json:"vocabularies"`  // 追加するボキャブラリーの語彙のリスト
+	VocabularyName     string            `json:"name"`                 // ボキャブラリーの名前
+	LanguageCode       string            `json:"language_code"`        // 言語コード
+	Vocabularies       []Vocabulary      `json:"vocabularies"`         // 置き換え後のボキャブラリーの語彙のリスト（全件）
+	Author             string            `json:"author"`               // 変更者 (バージョン履歴に記録)
+	GenerateSoundsLike bool              `json:"generate_sounds_like"` // SoundsLike, IPAが空の語彙の読みを自動生成する (ja-JPのみ)
+	ReadingOverrides   map[string]string `json:"reading_overrides"`    // 読みを自動生成する際の語ごとの上書き
+}
+
+// PatchVocabularyDto カスタムボキャブラリの部分更新時に使用するリクエストデータ
+type PatchVocabularyDto struct {
+	VocabularyName string                `json:"name"`          // ボキャブラリーの名前
+	LanguageCode   string                `json:"language_code"` // 言語コード (省略時は現在の言語コード)
+	Operations     []VocabularyOperation `json:"operations"`    // Phraseをキーとした追加・削除・変更の操作
+	Author         string                `json:"author"`        // 変更者 (バージョン履歴に記録)
+	IfMatch        string                `json:"-"`             // If-Match ヘッダーの値。現在のバージョンのETagと一致しない場合は更新しない
+}
+
+// VocabularyOperation 語彙1件分の部分更新操作
+type VocabularyOperation struct {
+	Op         string      `json:"op"`                   // add, remove, modify
+	Phrase     string      `json:"phrase"`               // 対象のPhrase
+	Vocabulary *Vocabulary `json:"vocabulary,omitempty"` // add, modifyの場合の新しい内容
 }
 
 // Vocabulary ボキャブラリーの語彙を表す構造体
@@ -31,4 +53,48 @@ type CustomVocabularyResponse struct {
 	Vocabularies               []Vocabulary `json:"vocabularies"`
 	VocabularyState            string       `json:"vocabularyState"`
 	VocabularyLastModifiedTime time.Time    `json:"lastModifiedTime"`
+	FailureReason              string       `json:"failureReason,omitempty"` // FAILEDになった理由
+	Owner                      string       `json:"owner,omitempty"`         // 作成を依頼した呼び出し元
+	Version                    int          `json:"version"`                 // 最新のバージョン番号 (ETagに使う。履歴が無い場合は0)
+}
+
+// VocabularyVersionDto ボキャブラリのバージョン履歴1件分のレスポンスデータ
+type VocabularyVersionDto struct {
+	Version        int    `json:"version"`
+	Mode           string `json:"mode"`              // table または list
+	FileUri        string `json:"fileUri,omitempty"` // フレーズリスト形式の場合は空
+	Author         string `json:"author"`
+	EntryCount     int    `json:"entryCount"`
+	EntryDelta     int    `json:"entryDelta"`
+	RolledBackFrom int    `json:"rolledBackFrom,omitempty"` // ロールバックで作成された場合の元バージョン
+	CreatedAt      string `json:"createdAt"`
+}
+
+// VocabularyVersionsResponseDto バージョン履歴一覧のレスポンスデータ
+type VocabularyVersionsResponseDto struct {
+	VocabularyName string                 `json:"vocabularyName"`
+	Versions       []VocabularyVersionDto `json:"versions"`
+}
+
+// VocabularyEntryDiffDto 語彙1件分の差分
+type VocabularyEntryDiffDto struct {
+	Phrase string      `json:"phrase"`
+	Change string      `json:"change"` // added, removed, modified
+	Before *Vocabulary `json:"before,omitempty"`
+	After  *Vocabulary `json:"after,omitempty"`
+}
+
+// VocabularyDiffResponseDto 2つのバージョン間の差分のレスポンスデータ
+type VocabularyDiffResponseDto struct {
+	VocabularyName string                   `json:"vocabularyName"`
+	From           int                      `json:"from"`
+	To             int                      `json:"to"`
+	Changes        []VocabularyEntryDiffDto `json:"changes"`
+}
+
+// RollbackVocabularyDto ボキャブラリのロールバック時に使用するリクエストデータ
+type RollbackVocabularyDto struct {
+	VocabularyName string `json:"name"`    // ボキャブラリーの名前
+	Version        int    `json:"version"` // 戻し先のバージョン
+	Author         string `json:"author"`  // 変更者
 }
@@ -0,0 +1,45 @@
+package dto
+
+import "time"
+
+// MediaInfoDto メディアの解析結果のレスポンスデータ
+type MediaInfoDto struct {
+	Format          string  `json:"format"`
+	Codec           string  `json:"codec,omitempty"`
+	VideoCodec      string  `json:"videoCodec,omitempty"`
+	DurationSeconds float64 `json:"durationSeconds,omitempty"`
+	SampleRateHertz int     `json:"sampleRateHertz,omitempty"`
+	Channels        int     `json:"channels,omitempty"`
+}
+
+// MediaJobDto メディアファイルを参照している文字起こしジョブのレスポンスデータ
+type MediaJobDto struct {
+	JobName   string    `json:"jobName"`
+	Language  string    `json:"language"`
+	Status    string    `json:"status"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// MediaFileDto アップロード済みのメディアファイルのレスポンスデータ
+type MediaFileDto struct {
+	Key             string        `json:"key"`
+	URI             string        `json:"uri"`
+	FileName        string        `json:"fileName"`
+	Size            int64         `json:"size"`
+	ContentType     string        `json:"contentType"`
+	UploadedAt      time.Time     `json:"uploadedAt"`
+	DurationSeconds *float64      `json:"durationSeconds,omitempty"`
+	Jobs            []MediaJobDto `json:"jobs"`
+}
+
+// MediaListDto メディアファイル一覧のレスポンスデータ
+type MediaListDto struct {
+	Files                 []MediaFileDto `json:"files"`
+	NextContinuationToken string         `json:"nextContinuationToken,omitempty"`
+}
+
+// RenameMediaDto メディアファイルの名前を変更するリクエストデータ
+type RenameMediaDto struct {
+	Key     string `json:"key"`     // 変更するファイルのキー
+	NewName string `json:"newName"` // 新しいファイル名 (拡張子は元のファイルのものを引き継ぐ)
+}
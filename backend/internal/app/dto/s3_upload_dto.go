@@ -0,0 +1,40 @@
+package dto
+
+import "time"
+
+// CreateUploadURLDto 署名付きアップロードURLを発行するリクエストデータ
+type CreateUploadURLDto struct {
+	FileName    string `json:"fileName"`    // 元のファイル名 (拡張子のみ使用)
+	ContentType string `json:"contentType"` // 例: audio/mpeg
+	Size        int64  `json:"size"`        // ファイルサイズ (PUTの場合は必須)
+	Method      string `json:"method"`      // PUT または POST (省略時はPUT)
+}
+
+// UploadURLResponseDto 署名付きアップロードURLのレスポンスデータ
+type UploadURLResponseDto struct {
+	Method    string            `json:"method"`
+	URL       string            `json:"url"`
+	Key       string            `json:"key"`
+	Headers   map[string]string `json:"headers,omitempty"` // PUTの場合に送る必要のあるヘッダー
+	Fields    map[string]string `json:"fields,omitempty"`  // POSTの場合にフォームに含めるフィールド
+	ExpiresAt time.Time         `json:"expiresAt"`
+}
+
+// ConfirmUploadDto アップロードの完了を確認するリクエストデータ
+type ConfirmUploadDto struct {
+	Key string `json:"key"` // 署名付きURLの発行時に返されたキー
+}
+
+// ConfirmUploadResponseDto アップロードの完了確認のレスポンスデータ
+type ConfirmUploadResponseDto struct {
+	URI         string        `json:"uri"` // 文字起こしジョブに指定するs3:// 形式のURI
+	Size        int64         `json:"size"`
+	ContentType string        `json:"contentType"`
+	Media       *MediaInfoDto `json:"media,omitempty"`
+}
+
+// S3UploadResponseDto サーバー経由のアップロードのレスポンスデータ
+type S3UploadResponseDto struct {
+	URL   string        `json:"url"` // s3:// 形式のURI
+	Media *MediaInfoDto `json:"media,omitempty"`
+}
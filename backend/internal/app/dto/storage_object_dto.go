@@ -0,0 +1,21 @@
+package dto
+
+import (
+	"io"
+	"time"
+)
+
+// StorageObjectDto ローカルストレージから配信するオブジェクト
+type StorageObjectDto struct {
+	Body         io.ReadSeekCloser
+	Key          string
+	ContentType  string
+	Size         int64
+	LastModified time.Time
+}
+
+// StorageUploadResponseDto 署名付きURLでアップロードしたオブジェクトのレスポンスデータ
+type StorageUploadResponseDto struct {
+	Key string `json:"key"`
+	URI string `json:"uri"`
+}
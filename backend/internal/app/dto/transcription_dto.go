@@ -10,12 +10,22 @@ type TranscriptionDto struct {
 	MediaURI             string `json:"mediaUri"`                       // メディアファイルのURI
 	LanguageCode         string `json:"languageCode"`                   // 言語コード
 	CustomVocabularyName string `json:"customVocabularyName,omitempty"` // カスタムボキャブラリ名 (オプション)
+	// WaitForVocabulary trueの場合、PENDINGのカスタムボキャブラリがREADYになるまで待機します
+	WaitForVocabulary bool `json:"waitForVocabulary,omitempty"`
+	// VocabularyWaitTimeoutSeconds 待機する最大秒数 (省略時は60秒)
+	VocabularyWaitTimeoutSeconds int `json:"vocabularyWaitTimeoutSeconds,omitempty"`
+	// VocabularyFilterName 語彙フィルタ名 (オプション)
+	VocabularyFilterName string `json:"vocabularyFilterName,omitempty"`
+	// VocabularyFilterMethod 語彙フィルタの適用方法 mask, remove, tag (省略時はmask)
+	VocabularyFilterMethod string `json:"vocabularyFilterMethod,omitempty"`
 }
 
 // TranscriptionJobStatusResponseDto 文字起こしジョブのレスポンスで使用されるDTO
 type TranscriptionJobStatusResponseDto struct {
-	JobName                string `json:"jobName"`
-	TranscriptionJobStatus string `json:"jobStatus"`
+	JobName                string        `json:"jobName"`
+	TranscriptionJobStatus string        `json:"jobStatus"`
+	Media                  *MediaInfoDto `json:"media,omitempty"`         // ジョブの開始時にメディアを解析した結果
+	QueuePosition          int           `json:"queuePosition,omitempty"` // PENDING_SUBMISSION の場合の送信を待つ順番 (1から)
 }
 
 // TranscriptionJobSummaryDto GetTranscriptionJobList用のResponseDTO
@@ -26,6 +36,9 @@ type TranscriptionJobSummaryDto struct {
 	LanguageCode           string `json:"languageCode"`
 	TranscriptionJobStatus string `json:"transcriptionJobStatus"`
 	OutputLocationType     string `json:"outputLocationType"`
+	Owner                  string `json:"owner,omitempty"`         // ジョブを開始した呼び出し元
+	QueuePosition          int    `json:"queuePosition,omitempty"` // PENDING_SUBMISSION の場合の送信を待つ順番 (1から)
+	FailureReason          string `json:"failureReason,omitempty"` // SUBMISSION_FAILED になった理由
 }
 
 // Validate メソッドは、TranscriptionJobSummaryDto のバリデーションを行います
@@ -45,7 +58,8 @@ func (r *TranscriptionJobSummaryDto) Validate() error {
 
 // TranscriptionJobsResponseDto GetTranscriptionJobList用のResponseDTO
 type TranscriptionJobsResponseDto struct {
-	Jobs []TranscriptionJobSummaryDto `json:"jobs"`
+	Jobs      []TranscriptionJobSummaryDto `json:"jobs"`
+	NextToken string                       `json:"nextToken,omitempty"` // 次のページを取得するトークン (次のページが無い場合は省略)
 }
 
 // Validate メソッドは、TranscriptionJobsResponseDto のバリデーションを行います
@@ -68,7 +82,10 @@ type TranscriptionJobResponseDto struct {
 	CompletionTime         string `json:"completionTime,omitempty"`
 	LanguageCode           string `json:"languageCode"`
 	TranscriptionJobStatus string `json:"transcriptionJobStatus"`
-	TranscriptFileUri      string `json:"transcriptFileUri"` // 実際の出力ファイルのURI
+	TranscriptFileUri      string `json:"transcriptFileUri"`       // 実際の出力ファイルのURI
+	Owner                  string `json:"owner,omitempty"`         // ジョブを開始した呼び出し元
+	QueuePosition          int    `json:"queuePosition,omitempty"` // PENDING_SUBMISSION の場合の送信を待つ順番 (1から)
+	FailureReason          string `json:"failureReason,omitempty"` // SUBMISSION_FAILED になった理由
 }
 
 // Validate メソッドは、TranscriptionJobDetailResponseDto のバリデーションを行います
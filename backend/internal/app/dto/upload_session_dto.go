@@ -0,0 +1,35 @@
+package dto
+
+import "time"
+
+// CreateUploadSessionDto 再開可能なアップロードを開始するリクエストデータ
+type CreateUploadSessionDto struct {
+	FileName    string `json:"fileName"`    // 元のファイル名 (拡張子のみ使用)
+	ContentType string `json:"contentType"` // 例: audio/mpeg
+	Size        int64  `json:"size"`        // ファイルサイズ (省略可能。指定した場合は完了時に照合する)
+}
+
+// UploadPartDto 受け取り済みのパートのレスポンスデータ
+type UploadPartDto struct {
+	PartNumber int       `json:"partNumber"`
+	Size       int64     `json:"size"`
+	Checksum   string    `json:"checksum"` // SHA-256 (16進数)
+	UploadedAt time.Time `json:"uploadedAt"`
+}
+
+// UploadSessionDto 再開可能なアップロードの状態のレスポンスデータ
+type UploadSessionDto struct {
+	UploadID      string          `json:"uploadId"`
+	Key           string          `json:"key"`
+	Status        string          `json:"status"`
+	FileName      string          `json:"fileName"`
+	ContentType   string          `json:"contentType"`
+	Size          int64           `json:"size,omitempty"`
+	ReceivedBytes int64           `json:"receivedBytes"`
+	MinPartSize   int64           `json:"minPartSize"` // 最後のパート以外の最小サイズ
+	MaxPartSize   int64           `json:"maxPartSize"` // 1回に送れるパートのサイズの上限
+	Parts         []UploadPartDto `json:"parts"`
+	URI           string          `json:"uri,omitempty"` // 完了後の s3:// 形式のURI
+	CreatedAt     time.Time       `json:"createdAt"`
+	UpdatedAt     time.Time       `json:"updatedAt"`
+}
@@ -0,0 +1,29 @@
+package dto
+
+import "time"
+
+// CreateVocabularyFilterDto 語彙フィルタ作成時に使用するリクエストデータ
+type CreateVocabularyFilterDto struct {
+	FilterName   string   `json:"name"`          // フィルタの名前
+	LanguageCode string   `json:"language_code"` // 言語コード
+	Words        []string `json:"words"`         // フィルタする単語のリスト
+}
+
+// UpdateVocabularyFilterDto 語彙フィルタ更新時に使用するリクエストデータ
+type UpdateVocabularyFilterDto struct {
+	FilterName string   `json:"name"`  // フィルタの名前
+	Words      []string `json:"words"` // 置き換え後の単語のリスト（全件）
+}
+
+// VocabularyFilterResponse クライアントに返す語彙フィルタのデータ構造
+type VocabularyFilterResponse struct {
+	FilterName       string    `json:"name"`
+	LanguageCode     string    `json:"languageCode"`
+	Words            []string  `json:"words,omitempty"`
+	LastModifiedTime time.Time `json:"lastModifiedTime"`
+}
+
+// VocabularyFiltersResponseDto 語彙フィルタ一覧のレスポンスデータ
+type VocabularyFiltersResponseDto struct {
+	Filters []VocabularyFilterResponse `json:"filters"`
+}
@@ -0,0 +1,19 @@
+package dto
+
+// SuggestReadingsDto 語彙の読み（SoundsLike）の候補を取得するリクエストデータ
+type SuggestReadingsDto struct {
+	Phrases   []string          `json:"phrases"`   // 読みを生成するPhraseのリスト
+	Overrides map[string]string `json:"overrides"` // 語ごとの読みの上書き (例: {"Kubernetes": "クバネティス"})
+}
+
+// ReadingSuggestionDto Phrase1件分の読みの候補
+type ReadingSuggestionDto struct {
+	Phrase     string `json:"phrase"`
+	SoundsLike string `json:"soundsLike,omitempty"`
+	Error      string `json:"error,omitempty"` // 読みを生成できなかった理由
+}
+
+// SuggestReadingsResponseDto 読みの候補一覧のレスポンスデータ
+type SuggestReadingsResponseDto struct {
+	Readings []ReadingSuggestionDto `json:"readings"`
+}
@@ -0,0 +1,19 @@
+package dto
+
+// VocabularyEntryReportDto 語彙1件分の出現状況
+type VocabularyEntryReportDto struct {
+	Phrase            string  `json:"phrase"`
+	DisplayAs         string  `json:"displayAs,omitempty"`
+	Matches           int     `json:"matches"`           // 文字起こし結果に出現した回数
+	AverageConfidence float64 `json:"averageConfidence"` // 出現したときの平均信頼度
+	JobCount          int     `json:"jobCount"`          // 出現したジョブ数
+}
+
+// VocabularyReportResponseDto ボキャブラリの効果レポートのレスポンスデータ
+type VocabularyReportResponseDto struct {
+	VocabularyName string                     `json:"vocabularyName"`
+	LanguageCode   string                     `json:"languageCode"`
+	ScannedJobs    int                        `json:"scannedJobs"`  // ボキャブラリを使用した完了済みジョブの数
+	Entries        []VocabularyEntryReportDto `json:"entries"`      // 語彙ごとの出現状況 (ボキャブラリの並び順)
+	NeverMatched   []string                   `json:"neverMatched"` // 一度も出現しなかった語彙のPhrase
+}
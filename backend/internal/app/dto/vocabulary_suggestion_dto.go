@@ -0,0 +1,36 @@
+package dto
+
+// TranscriptCorrectionDto レビュアーによる文字起こし結果の修正を登録するリクエストデータ
+type TranscriptCorrectionDto struct {
+	JobName   string `json:"jobName"`   // 修正したジョブ名
+	Original  string `json:"original"`  // 誤って認識された文字列
+	Corrected string `json:"corrected"` // 正しい文字列
+	Reviewer  string `json:"reviewer"`  // レビュアー
+}
+
+// VocabularySuggestionDto ボキャブラリへの追加候補
+type VocabularySuggestionDto struct {
+	Phrase            string         `json:"phrase"`
+	DisplayAs         string         `json:"displayAs,omitempty"`
+	Occurrences       int            `json:"occurrences"`               // 低信頼度で出現した回数
+	AverageConfidence float64        `json:"averageConfidence"`         // 低信頼度で出現したときの平均信頼度
+	Corrections       int            `json:"corrections"`               // レビュアーが修正した回数
+	JobCount          int            `json:"jobCount"`                  // 出現したジョブ数
+	Sources           []string       `json:"sources"`                   // low_confidence, correction
+	MisrecognizedAs   map[string]int `json:"misrecognizedAs,omitempty"` // 修正前の文字列ごとの件数
+	Score             float64        `json:"score"`
+}
+
+// VocabularySuggestionsResponseDto ボキャブラリへの追加候補一覧のレスポンスデータ
+type VocabularySuggestionsResponseDto struct {
+	LanguageCode string                    `json:"languageCode"`
+	ScannedJobs  int                       `json:"scannedJobs"`
+	Suggestions  []VocabularySuggestionDto `json:"suggestions"`
+}
+
+// AcceptSuggestionsDto 採用した候補をボキャブラリに追加するリクエストデータ
+type AcceptSuggestionsDto struct {
+	VocabularyName string       `json:"name"`         // 追加先のボキャブラリーの名前
+	Vocabularies   []Vocabulary `json:"vocabularies"` // 採用した候補
+	Author         string       `json:"author"`       // 変更者
+}
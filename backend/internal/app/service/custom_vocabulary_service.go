@@ -2,15 +2,18 @@ package service
 
 import (
 	"cmTranscribe/internal/app/dto"
+	"cmTranscribe/internal/domain/domainerr"
 	"cmTranscribe/internal/domain/model"
+	"cmTranscribe/internal/domain/repository"
 	"cmTranscribe/internal/domain/service"
 	"cmTranscribe/internal/infra/config"
+	"cmTranscribe/internal/shared/logger"
 	"cmTranscribe/internal/shared/validator"
 	"context"
-	"encoding/csv"
 	"fmt"
-	"io"
 	"net/http"
+	"strings"
+	"time"
 )
 
 // CustomVocabularyService アプリケーション層のサービス
@@ -18,6 +21,11 @@ type CustomVocabularyService struct {
 	CustomVocabularyService service.CustomVocabularyService
 	FileService             service.FileService
 	S3StorageService        service.S3StorageService
+	VersionRepo             repository.VocabularyVersionRepository
+	VocabularyRepo          repository.CustomVocabularyRepository
+	StateSyncer             *VocabularyStateSyncer
+	ReadingGenerator        service.ReadingGenerator
+	locks                   nameLocks // 同じボキャブラリの更新を直列化する
 }
 
 // NewCustomVocabularyService 新しい CustomVocabularyService を作成します
@@ -25,203 +33,572 @@ func NewCustomVocabularyService(
 	customVocabularyService service.CustomVocabularyService,
 	fileService service.FileService,
 	s3StorageService service.S3StorageService,
+	versionRepo repository.VocabularyVersionRepository,
+	vocabularyRepo repository.CustomVocabularyRepository,
+	stateSyncer *VocabularyStateSyncer,
+	readingGenerator service.ReadingGenerator,
 ) *CustomVocabularyService {
 	return &CustomVocabularyService{
 		CustomVocabularyService: customVocabularyService,
 		FileService:             fileService,
 		S3StorageService:        s3StorageService,
+		VersionRepo:             versionRepo,
+		VocabularyRepo:          vocabularyRepo,
+		StateSyncer:             stateSyncer,
+		ReadingGenerator:        readingGenerator,
 	}
 }
 
-// CreateCustomVocabulary カスタムボキャブラリを作成します
+// CreateCustomVocabulary カスタムボキャブラリを作成します。名前には呼び出し元のテナントの接頭辞を付けます
 func (s *CustomVocabularyService) CreateCustomVocabulary(ctx context.Context, request dto.CreateVocabularyDto) error {
-	// DTOからドメインモデルに変換
-	entries := model.ConvertEntriesToContent(request.Vocabularies)
-	// ファイルパス作成
-	filePath := model.GenerateFilePath(request.VocabularyName)
+	if err := s.checkVocabularyQuota(ctx); err != nil {
+		return err
+	}
+	name := resourceName(ctx, request.VocabularyName)
+	vocabularies := request.Vocabularies
+	if request.GenerateSoundsLike {
+		var err error
+		if vocabularies, err = s.generateSoundsLike(ctx, request.LanguageCode, vocabularies, request.ReadingOverrides); err != nil {
+			return err
+		}
+	}
 
-	// ドメインモデルを作成
-	csvFile := model.NewCSVFile(request.VocabularyName, filePath, entries)
-	// バリデーションの実行
-	if err := validator.Validate(csvFile); err != nil {
-		// エラーハンドリングのみ行う
-		return fmt.Errorf("error processing csvFile: %v", err)
+	// 語彙の内容から登録形式を選んでドメインモデルを作成
+	entries := model.NewVocabularyEntries(vocabularies)
+	customVocabulary, err := s.buildCustomVocabulary(ctx, name, request.LanguageCode, vocabularies)
+	if err != nil {
+		return fmt.Errorf("failed to create custom vocabulary: %w", err)
 	}
 
-	// CSVファイルを作成
-	filePath, fileHandle, err := s.FileService.CreateCSV(*csvFile)
+	// ドメインサービスを使ってカスタムボキャブラリを作成
+	err = s.CustomVocabularyService.CreateCustomVocabulary(ctx, *customVocabulary)
 	if err != nil {
-		return err
+		return fmt.Errorf("failed to create custom vocabulary: %w", err)
 	}
-	defer func() {
-		if err := fileHandle.Close(); err != nil {
-			fmt.Printf("Failed to close file: %v\n", err)
+	s.trackVocabulary(ctx, customVocabulary)
+
+	// バージョン履歴に記録
+	return s.recordVersion(model.NewVocabularyVersion(name, request.LanguageCode, customVocabulary.FileUri, versionAuthor(ctx, request.Author), entries))
+}
+
+// UpdateCustomVocabulary 既存のカスタムボキャブラリを更新します。
+func (s *CustomVocabularyService) UpdateCustomVocabulary(ctx context.Context, request dto.UpdateVocabularyDto) error {
+	unlock := s.locks.Lock(resourceName(ctx, request.VocabularyName))
+	defer unlock()
+	_, err := s.updateCustomVocabulary(ctx, request)
+	return err
+}
+
+// updateCustomVocabulary カスタムボキャブラリを更新し、記録したバージョン番号を返します。呼び出し元でロックします
+func (s *CustomVocabularyService) updateCustomVocabulary(ctx context.Context, request dto.UpdateVocabularyDto) (int, error) {
+	name := resourceName(ctx, request.VocabularyName)
+	vocabularies := request.Vocabularies
+	if request.GenerateSoundsLike {
+		var err error
+		if vocabularies, err = s.generateSoundsLike(ctx, request.LanguageCode, vocabularies, request.ReadingOverrides); err != nil {
+			return 0, err
 		}
-	}()
-	defer func() {
-		if err := s.FileService.Cleanup(filePath); err != nil {
-			fmt.Printf("Failed to clean up file: %v\n", err)
+	}
+
+	// 語彙の内容から登録形式を選んでドメインモデルを作成
+	entries := model.NewVocabularyEntries(vocabularies)
+	customVocabulary, err := s.buildCustomVocabulary(ctx, name, request.LanguageCode, vocabularies)
+	if err != nil {
+		return 0, fmt.Errorf("failed to update custom vocabulary: %w", err)
+	}
+
+	// ドメインサービスを使ってカスタムボキャブラリを作成
+	err = s.CustomVocabularyService.UpdateCustomVocabulary(ctx, *customVocabulary)
+	if err != nil {
+		return 0, fmt.Errorf("failed to update custom vocabulary: %w", err)
+	}
+	s.trackVocabulary(ctx, customVocabulary)
+
+	// バージョン履歴に記録
+	version := model.NewVocabularyVersion(name, request.LanguageCode, customVocabulary.FileUri, versionAuthor(ctx, request.Author), entries)
+	if err := s.recordVersion(version); err != nil {
+		return 0, err
+	}
+	return version.Version, nil
+}
+
+// PatchCustomVocabulary 現在の語彙に追加・削除・変更の操作をマージしてカスタムボキャブラリを更新し、新しいバージョン番号を返します。
+// 同じボキャブラリの更新は直列に行い、If-Match が現在のバージョンと一致しない場合は Conflict を返します
+func (s *CustomVocabularyService) PatchCustomVocabulary(ctx context.Context, request dto.PatchVocabularyDto) (int, error) {
+	operations := model.NewVocabularyOperations(request.Operations)
+	if len(operations) == 0 {
+		return 0, domainerr.New(domainerr.Validation, "operations_required", "operations are required")
+	}
+	for i := range operations {
+		if err := validator.Validate(&operations[i]); err != nil {
+			return 0, err
 		}
-	}()
+	}
 
-	// ドメインモデルを作成
-	s3File := model.NewS3File(filePath, config.AppConfig.S3BucketName, config.AppConfig.S3PrefixVocabulary)
-	// バリデーションの実行
-	if err := validator.Validate(s3File); err != nil {
-		// エラーハンドリングのみ行う
-		return fmt.Errorf("error processing s3File: %v", err)
+	name := resourceName(ctx, request.VocabularyName)
+	unlock := s.locks.Lock(name)
+	defer unlock()
+
+	latest, err := s.latestVersion(name)
+	if err != nil {
+		return 0, err
+	}
+	if request.IfMatch != "" && !model.MatchesVocabularyETag(request.IfMatch, versionNumber(latest)) {
+		return 0, domainerr.New(domainerr.Conflict, "vocabulary_version_mismatch", "custom vocabulary %s has been modified: current version is %d", request.VocabularyName, versionNumber(latest))
 	}
 
-	// S3にファイルをアップロード
-	s3Uri, err := s.S3StorageService.UploadToS3(ctx, *s3File)
+	// 現在の語彙を取得
+	customVocab, err := s.CustomVocabularyService.GetCustomVocabularyByName(ctx, name)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get custom vocabulary: %w", err)
+	}
+	current, err := s.currentEntries(ctx, latest, customVocab)
 	if err != nil {
-		return fmt.Errorf("failed to create custom vocabulary: %v", err)
+		return 0, err
 	}
 
-	// ドメインモデルを作成
-	customVocabulary := model.NewCustomVocabulary(request.VocabularyName, request.LanguageCode, s3Uri)
-	// バリデーションの実行
-	if err := validator.Validate(customVocabulary); err != nil {
-		// エラーハンドリングのみ行う
-		return fmt.Errorf("error processing customVocabulary: %v", err)
+	// 競合を検出しながらマージ
+	merged, err := model.MergeVocabularyEntries(current, operations)
+	if err != nil {
+		return 0, err
 	}
 
-	// ドメインサービスを使ってカスタムボキャブラリを作成
-	err = s.CustomVocabularyService.CreateCustomVocabulary(ctx, *customVocabulary)
+	languageCode := request.LanguageCode
+	if languageCode == "" {
+		languageCode = customVocab.LanguageCode
+	}
+	return s.updateCustomVocabulary(ctx, dto.UpdateVocabularyDto{
+		VocabularyName: request.VocabularyName,
+		LanguageCode:   languageCode,
+		Vocabularies:   toVocabularyDtos(merged),
+		Author:         request.Author,
+	})
+}
+
+// currentEntries 最新のバージョン履歴の語彙を返します。
+// 更新の直後は Amazon Transcribe のファイルURIが古い場合があるため、履歴が無い場合だけダウンロードします
+func (s *CustomVocabularyService) currentEntries(ctx context.Context, latest *model.VocabularyVersion, customVocab *model.CustomVocabularyResponse) ([]model.VocabularyEntry, error) {
+	if latest != nil {
+		return latest.Entries, nil
+	}
+	current, err := s.downloadAndParseVocabularyFile(ctx, customVocab.FileUri)
 	if err != nil {
-		return fmt.Errorf("failed to create custom vocabulary: %v", err)
+		return nil, fmt.Errorf("failed to download and parse vocabulary file: %w", err)
 	}
-	return nil
+	return model.NewVocabularyEntries(current), nil
 }
 
-// UpdateCustomVocabulary 既存のカスタムボキャブラリを更新します。
-func (s *CustomVocabularyService) UpdateCustomVocabulary(ctx context.Context, request dto.UpdateVocabularyDto) error {
-	// DTOからドメインモデルに変換
-	entries := model.ConvertEntriesToContent(request.Vocabularies)
-	// ファイルパス作成
-	filePath := model.GenerateFilePath(request.VocabularyName)
+// latestVersion 最新のバージョン履歴を返します。履歴が無い場合は nil です
+func (s *CustomVocabularyService) latestVersion(name string) (*model.VocabularyVersion, error) {
+	versions, err := s.VersionRepo.FindByName(name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get vocabulary versions: %w", err)
+	}
+	if len(versions) == 0 {
+		return nil, nil
+	}
+	return versions[len(versions)-1], nil
+}
+
+// versionNumber バージョン番号を返します。履歴が無い場合は0です
+func versionNumber(version *model.VocabularyVersion) int {
+	if version == nil {
+		return 0
+	}
+	return version.Version
+}
+
+// SuggestReadings Phraseごとに読み（SoundsLike）の候補を生成します
+func (s *CustomVocabularyService) SuggestReadings(ctx context.Context, request dto.SuggestReadingsDto) (*dto.SuggestReadingsResponseDto, error) {
+	if len(request.Phrases) == 0 {
+		return nil, domainerr.New(domainerr.Validation, "phrases_required", "phrases are required")
+	}
+
+	response := &dto.SuggestReadingsResponseDto{Readings: make([]dto.ReadingSuggestionDto, 0, len(request.Phrases))}
+	for _, phrase := range request.Phrases {
+		reading := dto.ReadingSuggestionDto{Phrase: phrase}
+		soundsLike, err := s.ReadingGenerator.GenerateSoundsLike(phrase, request.Overrides)
+		if err != nil {
+			reading.Error = err.Error()
+		} else {
+			reading.SoundsLike = soundsLike
+		}
+		response.Readings = append(response.Readings, reading)
+	}
+	return response, nil
+}
+
+// generateSoundsLike SoundsLikeとIPAが空で英字を含む語彙に、自動生成した読みを設定します。
+// 読みを生成できなかった語彙はそのまま登録します
+func (s *CustomVocabularyService) generateSoundsLike(ctx context.Context, languageCode string, vocabularies []dto.Vocabulary, overrides map[string]string) ([]dto.Vocabulary, error) {
+	if !strings.HasPrefix(languageCode, "ja-") {
+		return nil, domainerr.New(domainerr.Validation, "unsupported_language", "generate_sounds_like is only supported for ja-JP")
+	}
+
+	result := make([]dto.Vocabulary, 0, len(vocabularies))
+	for _, vocabulary := range vocabularies {
+		if vocabulary.SoundsLike == "" && vocabulary.IPA == "" && containsLatinLetter(vocabulary.Phrase) {
+			soundsLike, err := s.ReadingGenerator.GenerateSoundsLike(vocabulary.Phrase, overrides)
+			if err != nil {
+				logger.FromContext(ctx).Warn("Skipping SoundsLike generation", "phrase", vocabulary.Phrase, "error", err)
+			} else {
+				vocabulary.SoundsLike = soundsLike
+			}
+		}
+		result = append(result, vocabulary)
+	}
+	return result, nil
+}
+
+// containsLatinLetter 英字を含むかどうかを返します
+func containsLatinLetter(phrase string) bool {
+	return strings.IndexFunc(phrase, func(r rune) bool {
+		return r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z'
+	}) >= 0
+}
+
+// buildCustomVocabulary 語彙の内容に応じて登録形式を選び、ドメインモデルを作成します。
+// IPA, SoundsLike, DisplayAsを使わない小さな語彙はフレーズリスト形式で登録し、S3へのアップロードを省略します。
+func (s *CustomVocabularyService) buildCustomVocabulary(ctx context.Context, name, languageCode string, vocabularies []dto.Vocabulary) (*model.CustomVocabulary, error) {
+	var customVocabulary *model.CustomVocabulary
+	entries := model.NewVocabularyEntries(vocabularies)
+	if model.CanUsePhraseList(entries) {
+		customVocabulary = model.NewPhraseListVocabulary(name, languageCode, model.PhrasesOf(entries))
+	} else {
+		// 語彙ファイルをS3にアップロード
+		s3Uri, err := s.uploadVocabularyFile(ctx, name, vocabularies)
+		if err != nil {
+			return nil, err
+		}
+		customVocabulary = model.NewCustomVocabulary(name, languageCode, s3Uri)
+	}
 
-	// ドメインモデルを作成
-	csvFile := model.NewCSVFile(request.VocabularyName, filePath, entries)
 	// バリデーションの実行
-	if err := validator.Validate(csvFile); err != nil {
+	if err := validator.Validate(customVocabulary); err != nil {
 		// エラーハンドリングのみ行う
-		return fmt.Errorf("error processing csvFile: %v", err)
+		return nil, fmt.Errorf("error processing customVocabulary: %w", err)
+	}
+	return customVocabulary, nil
+}
+
+// uploadVocabularyFile 語彙リストからTSVファイルを作成してS3にアップロードし、そのS3 URIを返します
+func (s *CustomVocabularyService) uploadVocabularyFile(ctx context.Context, name string, vocabularies []dto.Vocabulary) (string, error) {
+	// DTOからドメインモデルに変換
+	tsvFile := model.NewTSVFile(name, model.ConvertEntriesToContent(vocabularies))
+	return uploadTSVFile(ctx, s.FileService, s.S3StorageService, tsvFile, vocabularyPrefix(ctx))
+}
+
+// uploadTSVFile TSVファイルをディスクに書き出さずにエンコードしながらS3にアップロードし、そのS3 URIを返します。
+// キーには内容のSHA-256を含めるため、同じ名前で同時に更新しても衝突しません
+func uploadTSVFile(ctx context.Context, fileService service.FileService, s3StorageService service.S3StorageService, tsvFile *model.TSVFile, keyPrefix string) (string, error) {
+	// バリデーションの実行
+	if err := validator.Validate(tsvFile); err != nil {
+		return "", fmt.Errorf("error processing tsvFile: %w", err)
 	}
 
-	// CSVファイルを作成
-	filePath, fileHandle, err := s.FileService.CreateCSV(*csvFile)
+	digest, err := fileService.DigestTSV(*tsvFile)
 	if err != nil {
-		return err
+		return "", err
 	}
+	body := fileService.EncodeTSV(*tsvFile)
 	defer func() {
-		if err := fileHandle.Close(); err != nil {
-			fmt.Printf("Failed to close file: %v\n", err)
-		}
-	}()
-	defer func() {
-		if err := s.FileService.Cleanup(filePath); err != nil {
-			fmt.Printf("Failed to clean up file: %v\n", err)
+		if err := body.Close(); err != nil {
+			logger.FromContext(ctx).Warn("Failed to close TSV stream", "error", err)
 		}
 	}()
 
 	// ドメインモデルを作成
-	s3File := model.NewS3File(filePath, config.AppConfig.S3BucketName, config.AppConfig.S3PrefixVocabulary)
+	s3Object := model.NewS3Object(config.AppConfig.S3BucketName, tsvFile.ContentKey(keyPrefix, digest), body, model.ContentTypeTSV)
 	// バリデーションの実行
-	if err := validator.Validate(s3File); err != nil {
-		// エラーハンドリングのみ行う
-		return fmt.Errorf("error processing s3File: %v", err)
+	if err := validator.Validate(s3Object); err != nil {
+		return "", fmt.Errorf("error processing s3Object: %w", err)
 	}
 
 	// S3にファイルをアップロード
-	s3Uri, err := s.S3StorageService.UploadToS3(ctx, *s3File)
+	return s3StorageService.PutObject(ctx, *s3Object)
+}
+
+// trackVocabulary 作成・更新を依頼したボキャブラリをPENDINGとして保存し、状態の同期を開始します
+func (s *CustomVocabularyService) trackVocabulary(ctx context.Context, vocabulary *model.CustomVocabulary) {
+	record, err := s.VocabularyRepo.FindByName(vocabulary.VocabularyName)
 	if err != nil {
-		return fmt.Errorf("failed to update custom vocabulary: %v", err)
+		record = model.NewCustomVocabularyDB(vocabulary.VocabularyName, vocabulary.LanguageCode, vocabulary.FileUri, model.OwnerFromContext(ctx))
+	} else {
+		record.Resubmit(vocabulary.LanguageCode, vocabulary.FileUri)
+	}
+	if err := s.VocabularyRepo.Save(record); err != nil {
+		logger.FromContext(ctx).Error("Failed to save custom vocabulary", "vocabulary_name", vocabulary.VocabularyName, "error", err)
+		return
 	}
+	s.StateSyncer.Notify()
+}
 
-	// ドメインモデルを作成
-	customVocabulary := model.NewCustomVocabulary(request.VocabularyName, request.LanguageCode, s3Uri)
-	// バリデーションの実行
-	if err := validator.Validate(customVocabulary); err != nil {
-		// エラーハンドリングのみ行う
-		return fmt.Errorf("error processing customVocabulary: %v", err)
+// refreshVocabulary Amazon Transcribeから取得した状態をリポジトリに反映します。
+// record は状態を取得する前に読み込んだもので、その後に更新を依頼された場合は古い状態を反映しません
+func (s *CustomVocabularyService) refreshVocabulary(ctx context.Context, record *model.CustomVocabularyDB, vocabulary *model.CustomVocabularyResponse) {
+	if record != nil {
+		if _, err := s.VocabularyRepo.ApplyState(record.Name, record.UpdatedAt, vocabulary.VocabularyState, vocabulary.FailureReason); err != nil {
+			logger.FromContext(ctx).Warn("Failed to save custom vocabulary", "vocabulary_name", vocabulary.VocabularyName, "error", err)
+		}
+		return
 	}
 
-	// ドメインサービスを使ってカスタムボキャブラリを作成
-	err = s.CustomVocabularyService.UpdateCustomVocabulary(ctx, *customVocabulary)
+	// このサービス以外で作成されたボキャブラリも保存しておく
+	record = model.NewCustomVocabularyDB(vocabulary.VocabularyName, vocabulary.LanguageCode, "", "")
+	record.CreatedAt = vocabulary.VocabularyLastModifiedTime
+	record.UpdatedAt = vocabulary.VocabularyLastModifiedTime
+	record.ApplyState(vocabulary.VocabularyState, vocabulary.FailureReason)
+	if err := s.VocabularyRepo.Save(record); err != nil {
+		logger.FromContext(ctx).Error("Failed to save custom vocabulary", "vocabulary_name", vocabulary.VocabularyName, "error", err)
+	}
+}
+
+// versionAuthor バージョン履歴に記録する変更者を返します。認証が有効な場合は、なりすましを防ぐため呼び出し元を記録します
+func versionAuthor(ctx context.Context, requested string) string {
+	if owner := model.OwnerFromContext(ctx); owner != "" {
+		return owner
+	}
+	return requested
+}
+
+// checkVocabularyQuota テナントのカスタムボキャブラリの数が上限に達している場合にエラーを返します
+func (s *CustomVocabularyService) checkVocabularyQuota(ctx context.Context) error {
+	tenant := model.TenantFromContext(ctx)
+	if tenant == nil || tenant.Quota.MaxVocabularies <= 0 {
+		return nil
+	}
+	vocabularies, err := s.CustomVocabularyService.ListCustomVocabularies(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list custom vocabularies: %w", err)
+	}
+	count := 0
+	for _, vocabulary := range vocabularies {
+		if tenant.OwnsName(vocabulary.VocabularyName) {
+			count++
+		}
+	}
+	return checkTenantQuota(ctx, "custom vocabularies", count, tenant.Quota.MaxVocabularies)
+}
+
+// vocabularyOwner ボキャブラリの作成を依頼した呼び出し元を返します
+func (s *CustomVocabularyService) vocabularyOwner(name string) string {
+	record, err := s.VocabularyRepo.FindByName(name)
 	if err != nil {
-		return fmt.Errorf("failed to update custom vocabulary: %v", err)
+		return ""
+	}
+	return record.Owner
+}
+
+// recordVersion アップロードした語彙ファイルをバージョン履歴に保存します
+func (s *CustomVocabularyService) recordVersion(version *model.VocabularyVersion) error {
+	if err := validator.Validate(version); err != nil {
+		return fmt.Errorf("error processing vocabularyVersion: %w", err)
+	}
+	if err := s.VersionRepo.Save(version); err != nil {
+		return fmt.Errorf("failed to record vocabulary version: %w", err)
 	}
 	return nil
 }
 
+// GetVocabularyVersions カスタムボキャブラリのバージョン履歴を取得します
+func (s *CustomVocabularyService) GetVocabularyVersions(ctx context.Context, name string) (*dto.VocabularyVersionsResponseDto, error) {
+	versions, err := s.VersionRepo.FindByName(resourceName(ctx, name))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get vocabulary versions: %w", err)
+	}
+
+	response := &dto.VocabularyVersionsResponseDto{
+		VocabularyName: name,
+		Versions:       make([]dto.VocabularyVersionDto, 0, len(versions)),
+	}
+	for _, version := range versions {
+		response.Versions = append(response.Versions, dto.VocabularyVersionDto{
+			Version:        version.Version,
+			Mode:           version.Mode(),
+			FileUri:        version.FileUri,
+			Author:         version.Author,
+			EntryCount:     version.EntryCount,
+			EntryDelta:     version.EntryDelta,
+			RolledBackFrom: version.RolledBackFrom,
+			CreatedAt:      version.CreatedAt.Format(time.RFC3339),
+		})
+	}
+	return response, nil
+}
+
+// DiffVocabularyVersions 2つのバージョン間の語彙の差分を取得します
+func (s *CustomVocabularyService) DiffVocabularyVersions(ctx context.Context, name string, from, to int) (*dto.VocabularyDiffResponseDto, error) {
+	fromVersion, err := s.VersionRepo.FindByNameAndVersion(resourceName(ctx, name), from)
+	if err != nil {
+		return nil, err
+	}
+	toVersion, err := s.VersionRepo.FindByNameAndVersion(resourceName(ctx, name), to)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &dto.VocabularyDiffResponseDto{
+		VocabularyName: name,
+		From:           from,
+		To:             to,
+		Changes:        []dto.VocabularyEntryDiffDto{},
+	}
+	for _, diff := range model.DiffVocabularyEntries(fromVersion.Entries, toVersion.Entries) {
+		response.Changes = append(response.Changes, dto.VocabularyEntryDiffDto{
+			Phrase: diff.Phrase,
+			Change: diff.Change,
+			Before: toVocabularyDto(diff.Before),
+			After:  toVocabularyDto(diff.After),
+		})
+	}
+	return response, nil
+}
+
+// RollbackCustomVocabulary 過去のバージョンのファイルURIでカスタムボキャブラリを更新します
+func (s *CustomVocabularyService) RollbackCustomVocabulary(ctx context.Context, request dto.RollbackVocabularyDto) error {
+	unlock := s.locks.Lock(resourceName(ctx, request.VocabularyName))
+	defer unlock()
+
+	target, err := s.VersionRepo.FindByNameAndVersion(resourceName(ctx, request.VocabularyName), request.Version)
+	if err != nil {
+		return err
+	}
+
+	// 過去のファイルURIをそのまま使って更新する (フレーズリスト形式の場合は当時のフレーズを使う)
+	customVocabulary := model.NewCustomVocabulary(target.VocabularyName, target.LanguageCode, target.FileUri)
+	if target.FileUri == "" {
+		customVocabulary = model.NewPhraseListVocabulary(target.VocabularyName, target.LanguageCode, model.PhrasesOf(target.Entries))
+	}
+	if err := validator.Validate(customVocabulary); err != nil {
+		return fmt.Errorf("error processing customVocabulary: %w", err)
+	}
+	if err := s.CustomVocabularyService.UpdateCustomVocabulary(ctx, *customVocabulary); err != nil {
+		return fmt.Errorf("failed to roll back custom vocabulary: %w", err)
+	}
+	s.trackVocabulary(ctx, customVocabulary)
+
+	// ロールバックも新しいバージョンとして記録
+	version := model.NewVocabularyVersion(target.VocabularyName, target.LanguageCode, target.FileUri, versionAuthor(ctx, request.Author), target.Entries)
+	version.RolledBackFrom = target.Version
+	return s.recordVersion(version)
+}
+
+// toVocabularyDto ドメインモデルの語彙をDTOに変換します
+func toVocabularyDto(entry *model.VocabularyEntry) *dto.Vocabulary {
+	if entry == nil {
+		return nil
+	}
+	return &dto.Vocabulary{
+		Phrase:     entry.Phrase,
+		IPA:        entry.IPA,
+		SoundsLike: entry.SoundsLike,
+		DisplayAs:  entry.DisplayAs,
+	}
+}
+
+// toVocabularyDtos 語彙リストをDTOに変換します
+func toVocabularyDtos(entries []model.VocabularyEntry) []dto.Vocabulary {
+	vocabularies := make([]dto.Vocabulary, 0, len(entries))
+	for i := range entries {
+		vocabularies = append(vocabularies, *toVocabularyDto(&entries[i]))
+	}
+	return vocabularies
+}
+
 // GetCustomVocabularyByName 名前でカスタムボキャブラリを取得し、クライアントに返す形式に変換します
 func (s *CustomVocabularyService) GetCustomVocabularyByName(ctx context.Context, name string) (*dto.CustomVocabularyResponse, error) {
+	// 状態を取得する前の記録 (追跡していない場合は nil)
+	record, _ := s.VocabularyRepo.FindByName(resourceName(ctx, name))
+
 	// ドメインサービスを使ってデータを取得
-	customVocab, err := s.CustomVocabularyService.GetCustomVocabularyByName(ctx, name)
+	customVocab, err := s.CustomVocabularyService.GetCustomVocabularyByName(ctx, resourceName(ctx, name))
 	if err != nil {
-		return nil, fmt.Errorf("failed to get custom vocabulary: %v", err)
+		return nil, fmt.Errorf("failed to get custom vocabulary: %w", err)
+	}
+
+	s.refreshVocabulary(ctx, record, customVocab)
+
+	// DownloadUriから内容をダウンロード (FAILEDの場合はDownloadUriが無いことがある)
+	var vocabularies []dto.Vocabulary
+	if customVocab.FileUri != "" {
+		vocabularies, err = s.downloadAndParseVocabularyFile(ctx, customVocab.FileUri)
+		if err != nil {
+			return nil, fmt.Errorf("failed to download and parse vocabulary file: %w", err)
+		}
 	}
 
-	// DownloadUriから内容をダウンロード
-	vocabularies, err := s.downloadAndParseVocabularyFile(customVocab.FileUri)
+	latest, err := s.latestVersion(customVocab.VocabularyName)
 	if err != nil {
-		return nil, fmt.Errorf("failed to download and parse vocabulary file: %v", err)
+		return nil, err
 	}
 
 	// 結果の構築
 	response := &dto.CustomVocabularyResponse{
-		VocabularyName:             customVocab.VocabularyName,
+		VocabularyName:             displayName(ctx, customVocab.VocabularyName),
 		LanguageCode:               customVocab.LanguageCode,
 		Vocabularies:               vocabularies,
 		VocabularyState:            customVocab.VocabularyState,
 		VocabularyLastModifiedTime: customVocab.VocabularyLastModifiedTime,
+		FailureReason:              customVocab.FailureReason,
+		Owner:                      s.vocabularyOwner(customVocab.VocabularyName),
+		Version:                    versionNumber(latest),
 	}
 
 	return response, nil
 }
 
+// ListCustomVocabularies 呼び出し元のテナントのカスタムボキャブラリの一覧を取得します (語彙リストは含みません)
+func (s *CustomVocabularyService) ListCustomVocabularies(ctx context.Context) ([]dto.CustomVocabularyResponse, error) {
+	vocabularies, err := s.CustomVocabularyService.ListCustomVocabularies(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list custom vocabularies: %w", err)
+	}
+
+	response := make([]dto.CustomVocabularyResponse, 0, len(vocabularies))
+	for _, vocabulary := range vocabularies {
+		if !ownsResource(ctx, vocabulary.VocabularyName) {
+			continue
+		}
+		response = append(response, dto.CustomVocabularyResponse{
+			VocabularyName:             displayName(ctx, vocabulary.VocabularyName),
+			LanguageCode:               vocabulary.LanguageCode,
+			VocabularyState:            vocabulary.VocabularyState,
+			VocabularyLastModifiedTime: vocabulary.VocabularyLastModifiedTime,
+			Owner:                      s.vocabularyOwner(vocabulary.VocabularyName),
+		})
+	}
+	return response, nil
+}
+
+// DeleteCustomVocabulary カスタムボキャブラリを削除し、状態の追跡を終了します。バージョン履歴は残します
+func (s *CustomVocabularyService) DeleteCustomVocabulary(ctx context.Context, name string) error {
+	name = resourceName(ctx, name)
+	if err := s.CustomVocabularyService.DeleteCustomVocabulary(ctx, name); err != nil {
+		return fmt.Errorf("failed to delete custom vocabulary: %w", err)
+	}
+	if err := s.VocabularyRepo.Delete(name); err != nil {
+		logger.FromContext(ctx).Warn("Failed to stop tracking custom vocabulary", "vocabulary_name", name, "error", err)
+	}
+	return nil
+}
+
 // downloadAndParseVocabularyFile ダウンロードしてパースする
-func (s *CustomVocabularyService) downloadAndParseVocabularyFile(uri string) ([]dto.Vocabulary, error) {
+func (s *CustomVocabularyService) downloadAndParseVocabularyFile(ctx context.Context, uri string) ([]dto.Vocabulary, error) {
 	// HTTPリクエストを使用してファイルをダウンロード
 	resp, err := http.Get(uri)
 	if err != nil {
-		return nil, fmt.Errorf("failed to download file: %v", err)
+		return nil, fmt.Errorf("failed to download file: %w", err)
 	}
 	defer func() {
 		if err := resp.Body.Close(); err != nil {
-			fmt.Printf("Failed to close file: %v\n", err)
+			logger.FromContext(ctx).Warn("Failed to close response body", "error", err)
 		}
 	}()
 
 	// ダウンロードした内容をパース
-	var vocabularies []dto.Vocabulary
-	reader := csv.NewReader(resp.Body)
-	reader.Comma = '\t' // ファイルの区切り文字がタブであることを想定
-
-	// ヘッダーをスキップ
-	if _, err := reader.Read(); err != nil {
-		return nil, fmt.Errorf("failed to read header: %v", err)
-	}
-
-	// 各行をパースして構造体にマッピング
-	for {
-		record, err := reader.Read()
-		if err == io.EOF {
-			break
-		}
-		if err != nil {
-			return nil, fmt.Errorf("failed to parse CSV: %v", err)
-		}
-
-		vocabulary := dto.Vocabulary{
-			Phrase:     record[0],
-			IPA:        record[1],
-			SoundsLike: record[2],
-			DisplayAs:  record[3],
-		}
-		vocabularies = append(vocabularies, vocabulary)
+	entries, err := model.ParseVocabularyEntries(resp.Body)
+	if err != nil {
+		return nil, err
 	}
-
-	return vocabularies, nil
+	return toVocabularyDtos(entries), nil
 }
@@ -0,0 +1,219 @@
+package service
+
+import (
+	"cmTranscribe/internal/app/dto"
+	"cmTranscribe/internal/domain/domainerr"
+	"cmTranscribe/internal/domain/model"
+	"cmTranscribe/internal/domain/repository"
+	"cmTranscribe/internal/domain/service"
+	"cmTranscribe/internal/infra/config"
+	"cmTranscribe/internal/shared/validator"
+	"context"
+	"errors"
+	"fmt"
+)
+
+const (
+	defaultMediaPageSize = 50
+	maxMediaPageSize     = 200
+	mediaMetadataWorkers = 8 // 一覧で同時にメタデータを取得するファイルの数
+)
+
+// MediaLibraryService は、テナントのアップロード先のプレフィックスの下にアップロードされたメディアファイルを管理するアプリケーションサービスです
+type MediaLibraryService struct {
+	s3StorageService  service.S3StorageService
+	transcriptionRepo repository.TranscriptionJobRepository
+}
+
+// NewMediaLibraryService は MediaLibraryService のインスタンスを生成します
+func NewMediaLibraryService(s3StorageService service.S3StorageService, transcriptionRepo repository.TranscriptionJobRepository) *MediaLibraryService {
+	return &MediaLibraryService{
+		s3StorageService:  s3StorageService,
+		transcriptionRepo: transcriptionRepo,
+	}
+}
+
+// ListMedia は、アップロード済みのメディアファイルを1ページ分取得し、参照している文字起こしジョブと合わせて返します
+func (s *MediaLibraryService) ListMedia(ctx context.Context, continuationToken string, limit int) (*dto.MediaListDto, error) {
+	if limit <= 0 {
+		limit = defaultMediaPageSize
+	}
+	if limit > maxMediaPageSize {
+		limit = maxMediaPageSize
+	}
+
+	page, err := s.s3StorageService.ListObjects(ctx, config.AppConfig.S3BucketName, UploadPrefix(ctx), continuationToken, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list media: %w", err)
+	}
+
+	var objects []*model.S3ObjectInfo
+	for _, object := range page.Objects {
+		if model.IsMediaKey(object.Key) {
+			objects = append(objects, object)
+		}
+	}
+
+	// 一覧ではContent-Typeやメタデータを取得できないため、オブジェクトごとに並行して取得する
+	details := make([]*model.S3ObjectInfo, len(objects))
+	errs := make([]error, len(objects))
+	forEachConcurrently(ctx, mediaMetadataWorkers, len(objects), func(i int) {
+		details[i], errs[i] = s.s3StorageService.HeadObject(ctx, objects[i].BucketName, objects[i].Key)
+	})
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	result := &dto.MediaListDto{
+		Files:                 []dto.MediaFileDto{},
+		NextContinuationToken: page.NextContinuationToken,
+	}
+	for i, detail := range details {
+		if err := errs[i]; err != nil {
+			if errors.Is(err, domainerr.NotFound) {
+				// 一覧の取得後に削除された
+				continue
+			}
+			return nil, fmt.Errorf("failed to get media metadata: %w", err)
+		}
+		// 他の呼び出し元がアップロードしたファイルは表示しない
+		if !model.PrincipalFromContext(ctx).CanAccess(detail.Owner()) {
+			continue
+		}
+		file, err := s.newMediaFile(detail)
+		if err != nil {
+			return nil, err
+		}
+		result.Files = append(result.Files, toMediaFileDto(ctx, file))
+	}
+	return result, nil
+}
+
+// DeleteMedia は、メディアファイルを削除します。
+// 文字起こしジョブから参照されている場合は force が true のときのみ削除します
+func (s *MediaLibraryService) DeleteMedia(ctx context.Context, key string, force bool) error {
+	object, err := s.findMedia(ctx, key)
+	if err != nil {
+		return err
+	}
+
+	jobs, err := s.transcriptionRepo.FindByMediaFileURI(object.URI())
+	if err != nil {
+		return fmt.Errorf("failed to find transcription jobs: %w", err)
+	}
+	if len(jobs) > 0 && !force {
+		return domainerr.New(domainerr.Conflict, "media_in_use", "%s is referenced by %d transcription jobs", key, len(jobs))
+	}
+
+	if err := s.s3StorageService.DeleteObject(ctx, object.BucketName, key); err != nil {
+		return fmt.Errorf("failed to delete media: %w", err)
+	}
+	return nil
+}
+
+// RenameMedia は、メディアファイルの名前を変更し、参照している文字起こしジョブの記録も新しいURIに更新します
+func (s *MediaLibraryService) RenameMedia(ctx context.Context, request dto.RenameMediaDto) (*dto.MediaFileDto, error) {
+	rename := model.NewMediaRename(request.Key, request.NewName)
+	if err := validator.Validate(rename); err != nil {
+		return nil, err
+	}
+
+	bucketName := config.AppConfig.S3BucketName
+	source, err := s.findMedia(ctx, rename.SourceKey)
+	if err != nil {
+		return nil, err
+	}
+	// S3 は1回のコピーで 5GiB を超えるオブジェクトを受け付けないため、コピーする前に断る
+	if source.Size > model.MaxCopyObjectSize {
+		return nil, domainerr.New(domainerr.Unprocessable, "media_too_large_to_rename", "%s is larger than %d bytes and cannot be renamed", rename.SourceKey, int64(model.MaxCopyObjectSize))
+	}
+	// 既存のファイルを上書きしない
+	if _, err := s.s3StorageService.HeadObject(ctx, bucketName, rename.DestinationKey); err == nil {
+		return nil, domainerr.New(domainerr.Conflict, "media_already_exists", "%s already exists", rename.DestinationKey)
+	} else if !errors.Is(err, domainerr.NotFound) {
+		return nil, fmt.Errorf("failed to check media: %w", err)
+	}
+
+	// S3には名前の変更が無いため、コピーしてから元のファイルを削除する
+	if err := s.s3StorageService.CopyObject(ctx, bucketName, rename.SourceKey, rename.DestinationKey); err != nil {
+		return nil, fmt.Errorf("failed to rename media: %w", err)
+	}
+	if err := s.s3StorageService.DeleteObject(ctx, bucketName, rename.SourceKey); err != nil {
+		return nil, fmt.Errorf("failed to rename media: %w", err)
+	}
+
+	destination, err := s.s3StorageService.HeadObject(ctx, bucketName, rename.DestinationKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get renamed media: %w", err)
+	}
+	jobs, err := s.transcriptionRepo.FindByMediaFileURI(source.URI())
+	if err != nil {
+		return nil, fmt.Errorf("failed to find transcription jobs: %w", err)
+	}
+	// リポジトリはコピーを返すため、変更したジョブを保存し直す
+	for _, job := range jobs {
+		job.MediaFileURI = destination.URI()
+		if err := s.transcriptionRepo.Save(job); err != nil {
+			return nil, fmt.Errorf("failed to update transcription job %s: %w", job.JobName, err)
+		}
+	}
+
+	file, err := s.newMediaFile(destination)
+	if err != nil {
+		return nil, err
+	}
+	mediaFile := toMediaFileDto(ctx, file)
+	return &mediaFile, nil
+}
+
+// findMedia 呼び出し元のアップロード先のプレフィックスの下にある、呼び出し元がアップロードしたメディアファイルを取得します。
+// 他の呼び出し元のファイルやメディアファイル以外のオブジェクトは、存在を明かさないよう NotFound を返します
+func (s *MediaLibraryService) findMedia(ctx context.Context, key string) (*model.S3ObjectInfo, error) {
+	if err := validateUploadKey(ctx, key); err != nil {
+		return nil, err
+	}
+	notFound := domainerr.New(domainerr.NotFound, "media_not_found", "media %s does not exist", key)
+	if !model.IsMediaKey(key) {
+		return nil, notFound
+	}
+	object, err := s.s3StorageService.HeadObject(ctx, config.AppConfig.S3BucketName, key)
+	if err != nil {
+		return nil, err
+	}
+	if !model.PrincipalFromContext(ctx).CanAccess(object.Owner()) {
+		return nil, notFound
+	}
+	return object, nil
+}
+
+// newMediaFile オブジェクトを参照している文字起こしジョブと合わせてMediaFileを作成します
+func (s *MediaLibraryService) newMediaFile(object *model.S3ObjectInfo) (*model.MediaFile, error) {
+	jobs, err := s.transcriptionRepo.FindByMediaFileURI(object.URI())
+	if err != nil {
+		return nil, fmt.Errorf("failed to find transcription jobs: %w", err)
+	}
+	return model.NewMediaFile(object, jobs), nil
+}
+
+// toMediaFileDto メディアファイルをレスポンス用のDTOに変換します。ジョブ名はテナントの接頭辞を除いて返します
+func toMediaFileDto(ctx context.Context, file *model.MediaFile) dto.MediaFileDto {
+	jobs := make([]dto.MediaJobDto, 0, len(file.Jobs))
+	for _, job := range file.Jobs {
+		jobs = append(jobs, dto.MediaJobDto{
+			JobName:   displayName(ctx, job.JobName),
+			Language:  job.Language,
+			Status:    job.Status,
+			CreatedAt: job.CreatedAt,
+		})
+	}
+	return dto.MediaFileDto{
+		Key:             file.Object.Key,
+		URI:             file.Object.URI(),
+		FileName:        file.FileName,
+		Size:            file.Object.Size,
+		ContentType:     file.Object.ContentType,
+		UploadedAt:      file.Object.LastModified,
+		DurationSeconds: file.DurationSeconds,
+		Jobs:            jobs,
+	}
+}
@@ -0,0 +1,41 @@
+package service
+
+import "sync"
+
+// nameLocks 名前ごとに処理を直列化するロック。ゼロ値のまま使えます
+type nameLocks struct {
+	mu    sync.Mutex
+	locks map[string]*nameLock
+}
+
+// nameLock 1つの名前のロックと、それを待っている処理の数
+type nameLock struct {
+	mu      sync.Mutex
+	waiters int
+}
+
+// Lock 名前のロックを取得し、解放する関数を返します。誰も使わなくなったロックは削除します
+func (l *nameLocks) Lock(name string) func() {
+	l.mu.Lock()
+	if l.locks == nil {
+		l.locks = map[string]*nameLock{}
+	}
+	lock, ok := l.locks[name]
+	if !ok {
+		lock = &nameLock{}
+		l.locks[name] = lock
+	}
+	lock.waiters++
+	l.mu.Unlock()
+
+	lock.mu.Lock()
+	return func() {
+		lock.mu.Unlock()
+		l.mu.Lock()
+		defer l.mu.Unlock()
+		lock.waiters--
+		if lock.waiters == 0 {
+			delete(l.locks, name)
+		}
+	}
+}
@@ -0,0 +1,275 @@
+package service
+
+import (
+	"cmTranscribe/internal/app/dto"
+	"cmTranscribe/internal/domain/domainerr"
+	"cmTranscribe/internal/domain/model"
+	"cmTranscribe/internal/domain/repository"
+	"cmTranscribe/internal/domain/service"
+	"cmTranscribe/internal/infra/config"
+	"cmTranscribe/internal/shared/logger"
+	"cmTranscribe/internal/shared/validator"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// ResumableUploadService は、S3のマルチパートアップロードを使ってメディアファイルをパートごとに受け取るアプリケーションサービスです。
+// 接続が切れてもステータスで受け取り済みのパートを確認し、足りないパートだけを送り直せます。
+type ResumableUploadService struct {
+	sessionRepo      repository.UploadSessionRepository
+	s3StorageService service.S3StorageService
+	mediaProber      service.MediaProber
+}
+
+// NewResumableUploadService は ResumableUploadService のインスタンスを生成します
+func NewResumableUploadService(sessionRepo repository.UploadSessionRepository, s3StorageService service.S3StorageService, mediaProber service.MediaProber) *ResumableUploadService {
+	return &ResumableUploadService{
+		sessionRepo:      sessionRepo,
+		s3StorageService: s3StorageService,
+		mediaProber:      mediaProber,
+	}
+}
+
+// CreateUpload は、マルチパートアップロードを開始してアップロードIDを発行します
+func (s *ResumableUploadService) CreateUpload(ctx context.Context, request dto.CreateUploadSessionDto) (*dto.UploadSessionDto, error) {
+	owner := model.OwnerFromContext(ctx)
+	session := model.NewUploadSession(config.AppConfig.S3BucketName, UploadPrefix(ctx), request.FileName, request.ContentType, request.Size, UploadMaxBytes(ctx), owner)
+	if err := validator.Validate(session); err != nil {
+		return nil, err
+	}
+
+	s3UploadID, err := s.s3StorageService.CreateMultipartUpload(ctx, session.BucketName, session.Key, session.ContentType, model.OwnerMetadata(owner))
+	if err != nil {
+		return nil, fmt.Errorf("failed to start upload: %w", err)
+	}
+	session.S3UploadID = s3UploadID
+
+	if err := s.sessionRepo.Save(session); err != nil {
+		return nil, fmt.Errorf("failed to save upload: %w", err)
+	}
+	return toUploadSessionDto(session), nil
+}
+
+// UploadPart は、パートを1件受け取り、メモリに溜めずに S3 にアップロードします。
+// パートのサイズ (Content-Length) とチェックサムは S3 に照合させます。同じ番号のパートを送り直した場合は置き換えます
+func (s *ResumableUploadService) UploadPart(ctx context.Context, uploadID string, partNumber int, checksum string, size int64, body io.Reader) (*dto.UploadPartDto, error) {
+	if checksum == "" {
+		return nil, domainerr.New(domainerr.Validation, "checksum_required", "SHA-256 checksum of the part is required")
+	}
+	if err := model.ValidateChecksumSHA256(checksum); err != nil {
+		return nil, err
+	}
+	if size < 0 {
+		return nil, domainerr.New(domainerr.Validation, "content_length_required", "Content-Length of the part is required")
+	}
+	if maxBytes := config.AppConfig.UploadPartMaxBytes; size > maxBytes {
+		return nil, domainerr.New(domainerr.Unprocessable, "upload_part_too_large", "part %d is larger than %d bytes", partNumber, maxBytes)
+	}
+	session, err := s.findSession(ctx, uploadID)
+	if err != nil {
+		return nil, err
+	}
+	// 送る前に明らかに受け取れないパートを断る。並行して届いたパートを含めた検証はパートの保存時に行う
+	if err := session.ValidatePart(partNumber, size); err != nil {
+		return nil, err
+	}
+
+	part := &model.UploadPart{
+		Number:   partNumber,
+		Size:     size,
+		Checksum: strings.ToLower(checksum),
+	}
+	etag, err := s.s3StorageService.UploadPart(ctx, *session, *part, body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to upload part: %w", err)
+	}
+	part.ETag = etag
+	part.UploadedAt = time.Now()
+
+	if err := s.sessionRepo.SavePart(uploadID, part); err != nil {
+		return nil, err
+	}
+	return toUploadPartDto(part), nil
+}
+
+// GetUpload は、アップロードの状態と受け取り済みのパートを返します
+func (s *ResumableUploadService) GetUpload(ctx context.Context, uploadID string) (*dto.UploadSessionDto, error) {
+	session, err := s.findSession(ctx, uploadID)
+	if err != nil {
+		return nil, err
+	}
+	return toUploadSessionDto(session), nil
+}
+
+// CompleteUpload は、受け取り済みのパートを結合してオブジェクトを作成し、s3:// 形式のURIを返します
+func (s *ResumableUploadService) CompleteUpload(ctx context.Context, uploadID string) (*dto.ConfirmUploadResponseDto, error) {
+	if _, err := s.findSession(ctx, uploadID); err != nil {
+		return nil, err
+	}
+	// 結合している間に新しいパートを受け付けないよう、ステータスを切り替えてから結合する
+	session, err := s.sessionRepo.Transition(uploadID, model.UploadSessionInProgress, model.UploadSessionCompleting)
+	if err != nil {
+		return nil, err
+	}
+	if err := session.ValidateComplete(); err != nil {
+		s.resumeUpload(ctx, uploadID)
+		return nil, err
+	}
+
+	uri, err := s.s3StorageService.CompleteMultipartUpload(ctx, *session)
+	if err != nil {
+		s.resumeUpload(ctx, uploadID)
+		return nil, fmt.Errorf("failed to complete upload: %w", err)
+	}
+	session.Status = model.UploadSessionCompleted
+	session.URI = uri
+	session.UpdatedAt = time.Now()
+	if err := s.sessionRepo.Save(session); err != nil {
+		return nil, fmt.Errorf("failed to save upload: %w", err)
+	}
+
+	// 結合したファイルが文字起こしできるメディアでなければ、オブジェクトとアップロードを破棄する
+	media, err := probeS3Media(ctx, s.s3StorageService, s.mediaProber, session.BucketName, session.Key)
+	if err != nil {
+		if !errors.Is(err, domainerr.Unprocessable) {
+			// 解析できなくてもアップロード自体は完了している
+			logger.FromContext(ctx).Warn("Failed to probe uploaded media", "key", session.Key, "error", err)
+		} else {
+			if deleteErr := s.sessionRepo.Delete(uploadID); deleteErr != nil {
+				logger.FromContext(ctx).Warn("Failed to delete upload", "upload_id", uploadID, "error", deleteErr)
+			}
+			return nil, discardInvalidMedia(ctx, s.s3StorageService, session.BucketName, session.Key, err)
+		}
+	}
+
+	return &dto.ConfirmUploadResponseDto{
+		URI:         uri,
+		Size:        session.ReceivedBytes(),
+		ContentType: session.ContentType,
+		Media:       toMediaInfoDto(media),
+	}, nil
+}
+
+// AbortUpload は、完了していないアップロードを中止して受け取り済みのパートを破棄します
+func (s *ResumableUploadService) AbortUpload(ctx context.Context, uploadID string) error {
+	if _, err := s.findSession(ctx, uploadID); err != nil {
+		return err
+	}
+	// 中止している間に新しいパートを受け付けないよう、ステータスを切り替えてから中止する
+	session, err := s.sessionRepo.Transition(uploadID, model.UploadSessionInProgress, model.UploadSessionAborting)
+	if err != nil {
+		return err
+	}
+	return s.abort(ctx, session)
+}
+
+// Run ctxがキャンセルされるまで、一定間隔で更新の無くなったアップロードを破棄します
+func (s *ResumableUploadService) Run(ctx context.Context) {
+	// 破棄されるまでの時間が有効期限の1.25倍を超えないように確認する
+	ticker := time.NewTicker(config.AppConfig.UploadSessionExpiry / 4)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		s.sweepExpired(ctx)
+	}
+}
+
+// sweepExpired 有効期限を過ぎたアップロードを破棄します。
+// 完了していないアップロードは S3 のマルチパートアップロードを中止し、受け取り済みのパートを破棄します
+func (s *ResumableUploadService) sweepExpired(ctx context.Context) {
+	sessions, err := s.sessionRepo.FindExpired(time.Now(), config.AppConfig.UploadSessionExpiry)
+	if err != nil {
+		logger.FromContext(ctx).Error("Failed to find expired uploads", "error", err)
+		return
+	}
+	for _, session := range sessions {
+		switch session.Status {
+		case model.UploadSessionCompleted:
+			// オブジェクトは作成済みのため、アップロードの記録だけを削除する
+			if err := s.sessionRepo.Delete(session.ID); err != nil {
+				logger.FromContext(ctx).Warn("Failed to delete expired upload", "upload_id", session.ID, "error", err)
+			}
+		case model.UploadSessionInProgress, model.UploadSessionAborting:
+			// 前回中止に失敗したアップロードも中止し直す
+			aborting, err := s.sessionRepo.Transition(session.ID, session.Status, model.UploadSessionAborting)
+			if err != nil {
+				continue
+			}
+			if err := s.abort(ctx, aborting); err != nil {
+				logger.FromContext(ctx).Warn("Failed to abort expired upload", "upload_id", session.ID, "error", err)
+				continue
+			}
+			logger.FromContext(ctx).Info("Aborted expired upload", "upload_id", session.ID, "key", session.Key)
+		}
+	}
+}
+
+// abort は、S3 のマルチパートアップロードを中止してアップロードを削除します。
+// 中止に失敗した場合はアップロードを ABORTING のまま残し、期限切れの破棄で中止し直します
+func (s *ResumableUploadService) abort(ctx context.Context, session *model.UploadSession) error {
+	if err := s.s3StorageService.AbortMultipartUpload(ctx, *session); err != nil {
+		return fmt.Errorf("failed to abort upload: %w", err)
+	}
+	return s.sessionRepo.Delete(session.ID)
+}
+
+// resumeUpload は、結合できなかったアップロードを、パートを受け付ける状態に戻します
+func (s *ResumableUploadService) resumeUpload(ctx context.Context, uploadID string) {
+	if _, err := s.sessionRepo.Transition(uploadID, model.UploadSessionCompleting, model.UploadSessionInProgress); err != nil {
+		logger.FromContext(ctx).Warn("Failed to resume upload", "upload_id", uploadID, "error", err)
+	}
+}
+
+// findSession アップロードを取得します。他のテナントや他の呼び出し元のアップロードは存在しないものとして扱います
+func (s *ResumableUploadService) findSession(ctx context.Context, uploadID string) (*model.UploadSession, error) {
+	session, err := s.sessionRepo.FindByID(uploadID)
+	if err != nil {
+		return nil, err
+	}
+	if !model.TenantFromContext(ctx).OwnsKey(session.Key) || !model.PrincipalFromContext(ctx).CanAccess(session.Owner) {
+		return nil, domainerr.New(domainerr.NotFound, "upload_not_found", "upload %s does not exist", uploadID)
+	}
+	return session, nil
+}
+
+// toUploadSessionDto アップロードをレスポンス用のDTOに変換します
+func toUploadSessionDto(session *model.UploadSession) *dto.UploadSessionDto {
+	parts := make([]dto.UploadPartDto, 0, len(session.Parts))
+	for _, part := range session.SortedParts() {
+		parts = append(parts, *toUploadPartDto(part))
+	}
+	return &dto.UploadSessionDto{
+		UploadID:      session.ID,
+		Key:           session.Key,
+		Status:        session.Status,
+		FileName:      session.FileName,
+		ContentType:   session.ContentType,
+		Size:          session.Size,
+		ReceivedBytes: session.ReceivedBytes(),
+		MinPartSize:   model.MinUploadPartSize,
+		MaxPartSize:   config.AppConfig.UploadPartMaxBytes,
+		Parts:         parts,
+		URI:           session.URI,
+		CreatedAt:     session.CreatedAt,
+		UpdatedAt:     session.UpdatedAt,
+	}
+}
+
+// toUploadPartDto パートをレスポンス用のDTOに変換します
+func toUploadPartDto(part *model.UploadPart) *dto.UploadPartDto {
+	return &dto.UploadPartDto{
+		PartNumber: part.Number,
+		Size:       part.Size,
+		Checksum:   part.Checksum,
+		UploadedAt: part.UploadedAt,
+	}
+}
@@ -0,0 +1,52 @@
+package service
+
+import (
+	"cmTranscribe/internal/domain/domainerr"
+	"cmTranscribe/internal/domain/model"
+	"cmTranscribe/internal/infra/persistence"
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestResumableUploadServiceFindSession(t *testing.T) {
+	repo, err := persistence.NewUploadSessionRepository()
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := NewResumableUploadService(repo, nil, nil)
+	session := model.NewUploadSession("bucket", "tenants/a/uploads", "a.wav", "audio/wav", 0, 1024, "alice")
+	if err := repo.Save(session); err != nil {
+		t.Fatal(err)
+	}
+	tenantA := model.ContextWithTenant(context.Background(), model.NewTenant("a", "", "tenants/a/uploads", "", "", model.TenantQuota{}))
+	tenantB := model.ContextWithTenant(context.Background(), model.NewTenant("b", "", "tenants/b/uploads", "", "", model.TenantQuota{}))
+
+	tests := []struct {
+		name      string
+		ctx       context.Context
+		principal *model.Principal
+		wantFound bool
+	}{
+		{name: "owner", ctx: tenantA, principal: &model.Principal{Subject: "alice"}, wantFound: true},
+		{name: "admin", ctx: tenantA, principal: &model.Principal{Subject: "root", Admin: true}, wantFound: true},
+		{name: "other caller in the same tenant", ctx: tenantA, principal: &model.Principal{Subject: "bob"}, wantFound: false},
+		{name: "owner in another tenant", ctx: tenantB, principal: &model.Principal{Subject: "alice"}, wantFound: false},
+		{name: "authentication disabled", ctx: tenantA, wantFound: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := tt.ctx
+			if tt.principal != nil {
+				ctx = model.ContextWithPrincipal(ctx, tt.principal)
+			}
+			_, err := s.findSession(ctx, session.ID)
+			if tt.wantFound && err != nil {
+				t.Fatalf("findSession() error = %v", err)
+			}
+			if !tt.wantFound && !errors.Is(err, domainerr.NotFound) {
+				t.Fatalf("findSession() error = %v, want NotFound", err)
+			}
+		})
+	}
+}
@@ -1,39 +1,225 @@
 package service
 
 import (
+	"cmTranscribe/internal/app/dto"
+	"cmTranscribe/internal/domain/domainerr"
 	"cmTranscribe/internal/domain/model"
+	"cmTranscribe/internal/domain/repository"
 	"cmTranscribe/internal/domain/service"
+	"cmTranscribe/internal/infra/config"
+	"cmTranscribe/internal/shared/logger"
 	"cmTranscribe/internal/shared/validator" // validatorパッケージをインポート
 	"context"
+	"errors"
 	"fmt"
+	"io"
+	"os"
+	"path"
+	"strings"
 )
 
 type S3UploadService struct {
 	s3StorageService service.S3StorageService
+	mediaProber      service.MediaProber
+	issuedUploadRepo repository.IssuedUploadRepository
 }
 
-func NewS3UploadService(s3StorageService service.S3StorageService) *S3UploadService {
-	return &S3UploadService{s3StorageService: s3StorageService}
+func NewS3UploadService(s3StorageService service.S3StorageService, mediaProber service.MediaProber, issuedUploadRepo repository.IssuedUploadRepository) *S3UploadService {
+	return &S3UploadService{
+		s3StorageService: s3StorageService,
+		mediaProber:      mediaProber,
+		issuedUploadRepo: issuedUploadRepo,
+	}
 }
 
-// UploadToS3 は、指定されたファイルを S3 にアップロードします
-func (s *S3UploadService) UploadToS3(ctx context.Context, filePath, bucketName, keyPrefix string) (string, error) {
+// UploadToS3 は、指定されたファイルを解析して文字起こしできるメディアであることを確認してから S3 にアップロードします。
+// 解析結果はオブジェクトのメタデータにも記録します
+func (s *S3UploadService) UploadToS3(ctx context.Context, filePath, bucketName, keyPrefix string) (*dto.S3UploadResponseDto, error) {
+	media, err := s.probeFile(ctx, filePath)
+	if err != nil {
+		return nil, err
+	}
+
 	// S3Fileのインスタンスを作成
+	metadata := media.Metadata()
+	for name, value := range model.OwnerMetadata(model.OwnerFromContext(ctx)) {
+		metadata[name] = value
+	}
 	s3File := model.S3File{
 		FilePath:   filePath,
 		BucketName: bucketName,
 		KeyPrefix:  keyPrefix,
+		Metadata:   metadata,
 	}
 
 	// バリデーションの共通ロジックを適用
 	if err := validator.Validate(&s3File); err != nil {
-		return "", fmt.Errorf("validation failed: %v", err)
+		return nil, fmt.Errorf("validation failed: %w", err)
 	}
 
 	// S3 にアップロード
 	url, err := s.s3StorageService.UploadToS3(ctx, s3File)
 	if err != nil {
-		return "", fmt.Errorf("failed to upload to S3: %v", err)
+		return nil, fmt.Errorf("failed to upload to S3: %w", err)
+	}
+	return &dto.S3UploadResponseDto{URL: url, Media: toMediaInfoDto(media)}, nil
+}
+
+// probeFile は、ローカルのファイルを解析します
+func (s *S3UploadService) probeFile(ctx context.Context, filePath string) (*model.MediaInfo, error) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open file: %w", err)
+	}
+	defer func() {
+		if err := file.Close(); err != nil {
+			logger.FromContext(ctx).Warn("Error closing file", "path", filePath, "error", err)
+		}
+	}()
+	stat, err := file.Stat()
+	if err != nil {
+		return nil, fmt.Errorf("failed to stat file: %w", err)
+	}
+	return probeMedia(s.mediaProber, file, stat.Size())
+}
+
+// CreateUploadURL は、テナントのアップロード先のプレフィックスの下に生成したキーへ直接アップロードするための署名付きURLを発行します
+func (s *S3UploadService) CreateUploadURL(ctx context.Context, request dto.CreateUploadURLDto) (*dto.UploadURLResponseDto, error) {
+	upload := model.NewUploadRequest(request.FileName, request.ContentType, request.Size, request.Method, UploadMaxBytes(ctx), UploadPrefix(ctx), model.OwnerFromContext(ctx))
+	if err := validator.Validate(upload); err != nil {
+		return nil, err
+	}
+
+	presigned, err := s.s3StorageService.PresignUpload(ctx, config.AppConfig.S3BucketName, *upload, config.AppConfig.UploadURLExpiry)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create upload URL: %w", err)
+	}
+	// URLの有効期限の直前に始まったアップロードも確認できるよう、有効期限からさらに同じ時間だけ確認を受け付ける
+	issued := model.NewIssuedUpload(presigned.Key, model.OwnerFromContext(ctx), tenantID(ctx), presigned.ExpiresAt.Add(config.AppConfig.UploadURLExpiry))
+	if err := s.issuedUploadRepo.Save(issued); err != nil {
+		return nil, fmt.Errorf("failed to save issued upload: %w", err)
+	}
+	return &dto.UploadURLResponseDto{
+		Method:    presigned.Method,
+		URL:       presigned.URL,
+		Key:       presigned.Key,
+		Headers:   presigned.Headers,
+		Fields:    presigned.Fields,
+		ExpiresAt: presigned.ExpiresAt,
+	}, nil
+}
+
+// ConfirmUpload は、署名付きURLでアップロードされたオブジェクトが存在し、制約を満たしていることを確認して s3:// 形式のURIを返します
+func (s *S3UploadService) ConfirmUpload(ctx context.Context, request dto.ConfirmUploadDto) (*dto.ConfirmUploadResponseDto, error) {
+	// 呼び出し元に発行したキー以外を確認に使わせない
+	if err := s.checkIssuedUpload(ctx, request.Key); err != nil {
+		return nil, err
+	}
+
+	object, err := s.s3StorageService.HeadObject(ctx, config.AppConfig.S3BucketName, request.Key)
+	if err != nil {
+		return nil, err
+	}
+	if maxBytes := UploadMaxBytes(ctx); object.Size > maxBytes {
+		return nil, s.discardIssuedUpload(ctx, object, domainerr.New(domainerr.Unprocessable, "upload_too_large", "uploaded object is larger than %d bytes", maxBytes))
+	}
+	if !model.IsUploadContentType(object.ContentType) {
+		return nil, s.discardIssuedUpload(ctx, object, domainerr.New(domainerr.Unprocessable, "unsupported_content_type", "uploaded object has unsupported content type %s", object.ContentType))
+	}
+	media, err := probeS3Media(ctx, s.s3StorageService, s.mediaProber, object.BucketName, object.Key)
+	if err != nil {
+		return nil, s.discardIssuedUpload(ctx, object, err)
+	}
+
+	return &dto.ConfirmUploadResponseDto{
+		URI:         object.URI(),
+		Size:        object.Size,
+		ContentType: object.ContentType,
+		Media:       toMediaInfoDto(media),
+	}, nil
+}
+
+// checkIssuedUpload キーが呼び出し元に発行し、確認の期限を過ぎていないアップロード先であることを確認します
+func (s *S3UploadService) checkIssuedUpload(ctx context.Context, key string) error {
+	if err := validateUploadKey(ctx, key); err != nil {
+		return err
+	}
+	issued, err := s.issuedUploadRepo.FindByKey(key)
+	if err != nil || !issued.IssuedTo(model.OwnerFromContext(ctx), tenantID(ctx)) {
+		return domainerr.New(domainerr.Validation, "invalid_upload_key", "key must be an upload key issued by this server")
+	}
+	return nil
+}
+
+// discardIssuedUpload 制約を満たさないオブジェクトを削除して発行したアップロード先の記録も削除し、元のエラーを返します。
+// checkIssuedUpload で確認したキーに対してだけ呼び出します
+func (s *S3UploadService) discardIssuedUpload(ctx context.Context, object *model.S3ObjectInfo, err error) error {
+	if !errors.Is(err, domainerr.Unprocessable) {
+		return err
+	}
+	if deleteErr := s.issuedUploadRepo.Delete(object.Key); deleteErr != nil {
+		logger.FromContext(ctx).Warn("Failed to delete issued upload", "key", object.Key, "error", deleteErr)
+	}
+	return discardInvalidMedia(ctx, s.s3StorageService, object.BucketName, object.Key, err)
+}
+
+// validateUploadKey キーが呼び出し元のテナントのアップロード先のプレフィックスの下のメディアファイルのキーであることを確認します
+func validateUploadKey(ctx context.Context, key string) error {
+	prefix := strings.TrimSuffix(path.Clean(UploadPrefix(ctx)), "/") + "/"
+	if key == "" || path.Clean(key) != key || !strings.HasPrefix(key, prefix) {
+		return domainerr.New(domainerr.Validation, "invalid_upload_key", "key must be an upload key issued by this server")
+	}
+	return nil
+}
+
+// probeMedia は、メディアを解析して Amazon Transcribe で文字起こしできることを確認します
+func probeMedia(mediaProber service.MediaProber, r io.ReaderAt, size int64) (*model.MediaInfo, error) {
+	media, err := mediaProber.Probe(r, size)
+	if err != nil {
+		// オブジェクトを読み込めない場合は、読み込みのエラーをそのまま返す
+		if _, ok := domainerr.As(err); ok {
+			return nil, err
+		}
+		return nil, domainerr.New(domainerr.Unprocessable, "media_not_transcribable", "%v", err)
+	}
+	if err := media.Validate(); err != nil {
+		return nil, domainerr.New(domainerr.Unprocessable, "media_not_transcribable", "%v", err)
+	}
+	return media, nil
+}
+
+// probeS3Media は、S3 オブジェクトを必要な範囲だけ読み込んで解析します
+func probeS3Media(ctx context.Context, s3StorageService service.S3StorageService, mediaProber service.MediaProber, bucketName, key string) (*model.MediaInfo, error) {
+	reader, size, err := s3StorageService.OpenObjectReader(ctx, bucketName, key)
+	if err != nil {
+		return nil, err
+	}
+	return probeMedia(mediaProber, reader, size)
+}
+
+// discardInvalidMedia は、文字起こしできないメディアとして解析されたオブジェクトを削除し、元のエラーを返します。
+// サーバーが発行したキー (確認済みのアップロードURLのキーやアップロードセッションのキー) に対してだけ呼び出します
+func discardInvalidMedia(ctx context.Context, s3StorageService service.S3StorageService, bucketName, key string, err error) error {
+	if !errors.Is(err, domainerr.Unprocessable) {
+		return err
+	}
+	if deleteErr := s3StorageService.DeleteObject(ctx, bucketName, key); deleteErr != nil {
+		logger.FromContext(ctx).Warn("Failed to delete invalid media", "key", key, "error", deleteErr)
+	}
+	return err
+}
+
+// toMediaInfoDto メディアの解析結果をレスポンス用のDTOに変換します
+func toMediaInfoDto(media *model.MediaInfo) *dto.MediaInfoDto {
+	if media == nil {
+		return nil
+	}
+	return &dto.MediaInfoDto{
+		Format:          media.Format,
+		Codec:           media.Codec,
+		VideoCodec:      media.VideoCodec,
+		DurationSeconds: media.DurationSeconds,
+		SampleRateHertz: media.SampleRateHertz,
+		Channels:        media.Channels,
 	}
-	return url, nil
 }
@@ -0,0 +1,98 @@
+package service
+
+import (
+	"cmTranscribe/internal/app/dto"
+	"cmTranscribe/internal/domain/domainerr"
+	"cmTranscribe/internal/domain/model"
+	"cmTranscribe/internal/domain/service"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// StorageObjectService は、ローカルストレージの署名付きURLを検証してオブジェクトを配信・保存するアプリケーションサービスです。
+// S3 の署名付きURLの代わりに、クライアントがサーバーから直接ファイルを読み書きするために使います
+type StorageObjectService struct {
+	store service.SignedObjectStore
+}
+
+// NewStorageObjectService は StorageObjectService のインスタンスを生成します
+func NewStorageObjectService(store service.SignedObjectStore) *StorageObjectService {
+	return &StorageObjectService{
+		store: store,
+	}
+}
+
+// GetObject は、署名付きURL (GET) を検証してオブジェクトを開きます。呼び出し元が Body を閉じる必要があります
+func (s *StorageObjectService) GetObject(ctx context.Context, params url.Values) (*dto.StorageObjectDto, error) {
+	request, err := s.store.VerifySignedRequest(http.MethodGet, params)
+	if err != nil {
+		return nil, err
+	}
+	body, object, err := s.store.OpenObject(ctx, request.BucketName, request.Key)
+	if err != nil {
+		return nil, err
+	}
+	return &dto.StorageObjectDto{
+		Body:         body,
+		Key:          object.Key,
+		ContentType:  object.ContentType,
+		Size:         object.Size,
+		LastModified: object.LastModified,
+	}, nil
+}
+
+// PutObject は、署名付きURL (PUT) を検証し、署名したContent-Typeとサイズのリクエストボディを保存します
+func (s *StorageObjectService) PutObject(ctx context.Context, params url.Values, contentType string, size int64, body io.Reader) (*dto.StorageUploadResponseDto, error) {
+	request, err := s.store.VerifySignedRequest(http.MethodPut, params)
+	if err != nil {
+		return nil, err
+	}
+	if !strings.EqualFold(contentType, request.ContentType) {
+		return nil, domainerr.New(domainerr.Forbidden, "content_type_mismatch", "Content-Type must be %s", request.ContentType)
+	}
+	if size != request.Size {
+		return nil, domainerr.New(domainerr.Validation, "content_length_mismatch", "Content-Length must be %d", request.Size)
+	}
+	return s.saveObject(ctx, request, &limitedObjectBody{reader: body, maxBytes: request.Size})
+}
+
+// PostObject は、署名付きのフォームフィールド (POST) を検証し、上限サイズまでのファイルを保存します
+func (s *StorageObjectService) PostObject(ctx context.Context, fields url.Values, body io.Reader) (*dto.StorageUploadResponseDto, error) {
+	request, err := s.store.VerifySignedRequest(http.MethodPost, fields)
+	if err != nil {
+		return nil, err
+	}
+	return s.saveObject(ctx, request, &limitedObjectBody{reader: body, maxBytes: request.MaxBytes})
+}
+
+// saveObject は、署名で許可されたキーにオブジェクトを保存します
+func (s *StorageObjectService) saveObject(ctx context.Context, request *model.SignedObjectRequest, body io.Reader) (*dto.StorageUploadResponseDto, error) {
+	object := model.NewS3Object(request.BucketName, request.Key, body, request.ContentType)
+	object.Metadata = model.OwnerMetadata(request.Owner)
+	uri, err := s.store.PutObject(ctx, *object)
+	if err != nil {
+		return nil, fmt.Errorf("failed to save object: %w", err)
+	}
+	return &dto.StorageUploadResponseDto{Key: request.Key, URI: uri}, nil
+}
+
+// limitedObjectBody は、上限を超えて読み込んだ時点でエラーを返す io.Reader です。
+// エラーで書き込みが中断されるため、上限を超えるオブジェクトは保存されません
+type limitedObjectBody struct {
+	reader   io.Reader
+	maxBytes int64
+	read     int64
+}
+
+func (b *limitedObjectBody) Read(p []byte) (int, error) {
+	n, err := b.reader.Read(p)
+	b.read += int64(n)
+	if b.read > b.maxBytes {
+		return n, domainerr.New(domainerr.TooLarge, "object_too_large", "object must not exceed %d bytes", b.maxBytes)
+	}
+	return n, err
+}
@@ -0,0 +1,84 @@
+package service
+
+import (
+	"cmTranscribe/internal/domain/domainerr"
+	"cmTranscribe/internal/domain/model"
+	"cmTranscribe/internal/infra/config"
+	"context"
+)
+
+// UploadPrefix 呼び出し元のテナントのメディアファイルをアップロードするプレフィックスを返します
+func UploadPrefix(ctx context.Context) string {
+	if tenant := model.TenantFromContext(ctx); tenant != nil {
+		return tenant.UploadPrefix
+	}
+	return config.AppConfig.S3PrefixUploadFile
+}
+
+// UploadMaxBytes 呼び出し元のテナントがアップロードできるファイル1件あたりのサイズの上限を返します
+func UploadMaxBytes(ctx context.Context) int64 {
+	maxBytes := config.AppConfig.UploadMaxBytes
+	if tenant := model.TenantFromContext(ctx); tenant != nil && tenant.Quota.MaxUploadBytes > 0 && tenant.Quota.MaxUploadBytes < maxBytes {
+		return tenant.Quota.MaxUploadBytes
+	}
+	return maxBytes
+}
+
+// vocabularyPrefix 呼び出し元のテナントの語彙ファイルをアップロードするプレフィックスを返します
+func vocabularyPrefix(ctx context.Context) string {
+	if tenant := model.TenantFromContext(ctx); tenant != nil {
+		return tenant.VocabularyPrefix
+	}
+	return config.AppConfig.S3PrefixVocabulary
+}
+
+// vocabularyFilterPrefix 呼び出し元のテナントの語彙フィルタの単語リストをアップロードするプレフィックスを返します
+func vocabularyFilterPrefix(ctx context.Context) string {
+	if tenant := model.TenantFromContext(ctx); tenant != nil {
+		return tenant.VocabularyFilterPrefix
+	}
+	return config.AppConfig.S3PrefixVocabularyFilter
+}
+
+// tenantID 呼び出し元のテナントのIDを返します。テナントが無い場合は空文字を返します
+func tenantID(ctx context.Context) string {
+	if tenant := model.TenantFromContext(ctx); tenant != nil {
+		return tenant.ID
+	}
+	return ""
+}
+
+// resourceName クライアントが指定したジョブ名やボキャブラリ名を、テナントの接頭辞を付けた Amazon Transcribe 上の名前にします
+func resourceName(ctx context.Context, name string) string {
+	return model.TenantFromContext(ctx).ResourceName(name)
+}
+
+// displayName Amazon Transcribe 上の名前から、クライアントに返す名前を返します
+func displayName(ctx context.Context, name string) string {
+	if display, ok := model.TenantFromContext(ctx).DisplayName(name); ok {
+		return display
+	}
+	return name
+}
+
+// ownsResource Amazon Transcribe 上の名前が呼び出し元のテナントのリソースかどうかを返します
+func ownsResource(ctx context.Context, name string) bool {
+	return model.TenantFromContext(ctx).OwnsName(name)
+}
+
+// resourceNameFilter Amazon Transcribe の一覧を呼び出し元のテナントのリソースに絞り込む文字列 (名前の接頭辞) を返します。
+// 名前に含むかどうかで絞り込むため、結果は ownsResource でも確認します
+func resourceNameFilter(ctx context.Context) string {
+	if tenant := model.TenantFromContext(ctx); tenant != nil {
+		return tenant.NamePrefix
+	}
+	return ""
+}
+
+// checkTenantQuota 使用数がテナントの上限に達している場合にエラーを返します。上限が0の場合は確認しません
+func checkTenantQuota(ctx context.Context, resource string, used, limit int) error {
+	if limit <= 0 || used < limit {
+		return nil
+	}
+	return domainerr.New(domainerr.Throttled, "tenant_quota_exceeded", "tenant %s has reached the limit of %d %s", model.TenantFromContext(ctx).ID, limit, resource)
+}
@@ -0,0 +1,156 @@
+package service
+
+import (
+	"cmTranscribe/internal/domain/model"
+	"cmTranscribe/internal/domain/repository"
+	"cmTranscribe/internal/domain/service"
+	"cmTranscribe/internal/infra/config"
+	"container/list"
+	"context"
+	"fmt"
+	"sync"
+)
+
+// TranscriptLoader 完了済みのジョブの文字起こし結果を並行して取得します。
+// 完了済みのジョブの文字起こし結果は変わらないため、取得した結果を新しいものから一定数だけ保持します
+type TranscriptLoader struct {
+	S3StorageService service.S3StorageService
+	JobRepo          repository.TranscriptionJobRepository
+	workers          int // 同時に取得するジョブの数
+	cacheSize        int // 保持する文字起こし結果の数 (0の場合は保持しない)
+
+	mu      sync.Mutex
+	entries map[string]*list.Element
+	order   *list.List // 最近使った順 (先頭が最新) の *cachedTranscript
+}
+
+// cachedTranscript 保持している文字起こし結果
+type cachedTranscript struct {
+	jobName    string
+	transcript *model.Transcript
+}
+
+// LoadedTranscript ジョブ1件分の取得結果
+type LoadedTranscript struct {
+	JobName    string
+	Transcript *model.Transcript
+	Err        error
+}
+
+// NewTranscriptLoader 新しい TranscriptLoader を作成します
+func NewTranscriptLoader(s3StorageService service.S3StorageService, jobRepo repository.TranscriptionJobRepository, workers, cacheSize int) *TranscriptLoader {
+	if workers <= 0 {
+		workers = 1
+	}
+	return &TranscriptLoader{
+		S3StorageService: s3StorageService,
+		JobRepo:          jobRepo,
+		workers:          workers,
+		cacheSize:        cacheSize,
+		entries:          map[string]*list.Element{},
+		order:            list.New(),
+	}
+}
+
+// LoadAll 完了済みのジョブの文字起こし結果を並行して取得し、jobNames と同じ順に返します。
+// 取得できなかったジョブは Err を設定して返します
+func (l *TranscriptLoader) LoadAll(ctx context.Context, jobNames []string) []LoadedTranscript {
+	results := make([]LoadedTranscript, len(jobNames))
+	forEachConcurrently(ctx, l.workers, len(jobNames), func(i int) {
+		transcript, err := l.Load(ctx, jobNames[i])
+		results[i] = LoadedTranscript{JobName: jobNames[i], Transcript: transcript, Err: err}
+	})
+	for i := range results {
+		if results[i].Transcript == nil && results[i].Err == nil {
+			// キャンセルされて取得しなかったジョブ
+			results[i] = LoadedTranscript{JobName: jobNames[i], Err: ctx.Err()}
+		}
+	}
+	return results
+}
+
+// Load 完了済みのジョブの文字起こし結果を取得します
+func (l *TranscriptLoader) Load(ctx context.Context, jobName string) (*model.Transcript, error) {
+	if transcript, ok := l.cached(jobName); ok {
+		return transcript, nil
+	}
+	transcript, err := fetchTranscript(ctx, l.S3StorageService, transcriptKey(ctx, l.JobRepo, jobName))
+	if err != nil {
+		return nil, err
+	}
+	l.store(jobName, transcript)
+	return transcript, nil
+}
+
+// cached 保持している文字起こし結果を返します
+func (l *TranscriptLoader) cached(jobName string) (*model.Transcript, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	element, ok := l.entries[jobName]
+	if !ok {
+		return nil, false
+	}
+	l.order.MoveToFront(element)
+	return element.Value.(*cachedTranscript).transcript, true
+}
+
+// store 文字起こし結果を保持し、上限を超えた分を古いものから削除します
+func (l *TranscriptLoader) store(jobName string, transcript *model.Transcript) {
+	if l.cacheSize <= 0 {
+		return
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if element, ok := l.entries[jobName]; ok {
+		l.order.MoveToFront(element)
+		return
+	}
+	l.entries[jobName] = l.order.PushFront(&cachedTranscript{jobName: jobName, transcript: transcript})
+	for l.order.Len() > l.cacheSize {
+		oldest := l.order.Back()
+		l.order.Remove(oldest)
+		delete(l.entries, oldest.Value.(*cachedTranscript).jobName)
+	}
+}
+
+// fetchTranscript key に出力されたジョブの文字起こし結果を取得してパースします
+func fetchTranscript(ctx context.Context, s3StorageService service.S3StorageService, key string) (*model.Transcript, error) {
+	signedURL, err := s3StorageService.GeneratePresignedURL(ctx, config.AppConfig.S3BucketName, key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate signed URL: %w", err)
+	}
+	content, err := s3StorageService.GetTranscriptionContent(ctx, signedURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get transcription content: %w", err)
+	}
+	return model.ParseTranscript([]byte(content))
+}
+
+// forEachConcurrently 0 から n-1 までの i について、最大 workers 個ずつ並行して fn を呼び出し、すべて終わるまで待ちます。
+// ctx がキャンセルされた場合は、まだ始めていない i の fn を呼び出しません
+func forEachConcurrently(ctx context.Context, workers, n int, fn func(i int)) {
+	if workers > n {
+		workers = n
+	}
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				fn(i)
+			}
+		}()
+	}
+	for i := 0; i < n; i++ {
+		if ctx.Err() != nil {
+			break
+		}
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+}
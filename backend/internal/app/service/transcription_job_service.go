@@ -2,13 +2,19 @@ package service
 
 import (
 	"cmTranscribe/internal/app/dto"
+	"cmTranscribe/internal/domain/domainerr"
 	"cmTranscribe/internal/domain/model"
 	"cmTranscribe/internal/domain/repository"
 	"cmTranscribe/internal/domain/service"
+	"cmTranscribe/internal/infra/config"
+	"cmTranscribe/internal/shared/logger"
+	"cmTranscribe/internal/shared/metrics"
 	"cmTranscribe/internal/shared/validator"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"sync"
 	"time"
 )
 
@@ -17,6 +23,13 @@ type TranscriptionJobService struct {
 	Repo                    repository.TranscriptionJobRepository
 	TranscriptionJobService service.TranscriptionJobService
 	S3StorageService        service.S3StorageService
+	CustomVocabularyService service.CustomVocabularyService
+	MediaProber             service.MediaProber
+	SubmissionGovernor      *TranscriptionSubmissionGovernor
+	statusMu                sync.Mutex // 完了したジョブを一度だけ数えるためのロック
+
+	startingMu sync.Mutex
+	starting   map[string]bool // 送信の結果を待っているジョブ名 (同じ名前のジョブを同時に開始させない)
 }
 
 // NewTranscriptionJobService 新しい TranscriptionJobService を作成します。
@@ -24,85 +37,303 @@ func NewTranscriptionJobService(
 	repo repository.TranscriptionJobRepository,
 	jobService service.TranscriptionJobService,
 	s3StorageService service.S3StorageService,
+	customVocabularyService service.CustomVocabularyService,
+	mediaProber service.MediaProber,
+	submissionGovernor *TranscriptionSubmissionGovernor,
 ) *TranscriptionJobService {
 	return &TranscriptionJobService{
 		Repo:                    repo,
 		TranscriptionJobService: jobService,
 		S3StorageService:        s3StorageService,
+		CustomVocabularyService: customVocabularyService,
+		MediaProber:             mediaProber,
+		SubmissionGovernor:      submissionGovernor,
+		starting:                make(map[string]bool),
 	}
 }
 
 // StartTranscriptionJob 新しい文字起こしジョブを開始します。
+// ジョブ名と参照するボキャブラリ、語彙フィルタの名前には、呼び出し元のテナントの接頭辞を付けます。
+// 同時実行数の上限に達している場合は PENDING_SUBMISSION の状態と送信を待つ順番を返し、空きができてから送信します
 func (s *TranscriptionJobService) StartTranscriptionJob(ctx context.Context, req *dto.TranscriptionDto) (*dto.TranscriptionJobStatusResponseDto, error) {
 	// UUIDを使用してユニークなジョブIDを生成
 	//jobName := uuid.New().String()
-	// ドメインモデルを作成
-	job := model.NewTranscriptionJobDB(req.JobName, req.MediaURI, req.LanguageCode)
+	jobName := resourceName(ctx, req.JobName)
+	transcriptionJob := model.NewTranscriptionJob(jobName, req.MediaURI, req.LanguageCode, resourceName(ctx, req.CustomVocabularyName)).
+		WithVocabularyFilter(resourceName(ctx, req.VocabularyFilterName), req.VocabularyFilterMethod)
+	// バリデーションの実行
+	if err := validator.Validate(transcriptionJob); err != nil {
+		// エラーハンドリングのみ行う
+		return nil, fmt.Errorf("error processing transcriptionJob: %w", err)
+	}
+	if err := checkTenantMedia(ctx, transcriptionJob.MediaFileURI); err != nil {
+		return nil, err
+	}
+	// ボキャブラリの待機やメディアの解析の前に上限を確認する (送信時にも改めて確認する)
+	if err := s.SubmissionGovernor.CheckQuota(ctx); err != nil {
+		return nil, err
+	}
 
-	// ジョブをリポジトリに保存
-	err := s.Repo.Save(job)
+	// カスタムボキャブラリが利用可能か事前に確認
+	if transcriptionJob.CustomVocabularyName != "" {
+		timeout := time.Duration(0)
+		if req.WaitForVocabulary {
+			timeout = vocabularyWaitTimeout(req.VocabularyWaitTimeoutSeconds)
+		}
+		if err := s.checkVocabularyReady(ctx, transcriptionJob.CustomVocabularyName, transcriptionJob.LanguageCode, timeout); err != nil {
+			return nil, err
+		}
+	}
+
+	// メディアを事前に解析し、フォーマットとサンプルレートを指定する
+	media, err := s.probeJobMedia(ctx, transcriptionJob.MediaFileURI)
 	if err != nil {
 		return nil, err
 	}
+	transcriptionJob.WithMedia(media, config.AppConfig.MediaFormat)
+	// 文字起こし結果は呼び出し元のテナントのプレフィックスの下に出力する
+	transcriptionJob.OutputKey = model.TenantFromContext(ctx).TranscriptKey(jobName)
 
-	transcriptionJob := model.NewTranscriptionJob(req.JobName, req.MediaURI, req.LanguageCode, req.CustomVocabularyName)
-	// バリデーションの実行
-	if err := validator.Validate(transcriptionJob); err != nil {
-		// エラーハンドリングのみ行う
-		return nil, fmt.Errorf("error processing transcriptionJob: %v", err)
+	if err := s.reserveJobName(ctx, jobName, req.JobName); err != nil {
+		return nil, err
 	}
+	defer s.releaseJobName(jobName)
 
-	result, err := s.TranscriptionJobService.StartTranscriptionJob(ctx, transcriptionJob)
+	// ドメインモデルを作成。記録は Amazon Transcribe が受け付けたか、送信を待たせた時に保存する
+	job := model.NewTranscriptionJobDB(jobName, req.MediaURI, req.LanguageCode, model.OwnerFromContext(ctx))
+	job.Media = media
+	job.TenantID = tenantID(ctx)
+	job.OutputKey = transcriptionJob.OutputKey
+
+	result, position, err := s.SubmissionGovernor.Submit(ctx, transcriptionJob, job)
 	if err != nil {
-		return nil, fmt.Errorf("failed to start transcription job: %v", err)
+		return nil, fmt.Errorf("failed to start transcription job: %w", err)
 	}
 	response := &dto.TranscriptionJobStatusResponseDto{
-		JobName:                result.JobName,
+		JobName:                displayName(ctx, result.JobName),
 		TranscriptionJobStatus: result.TranscriptionJobStatus,
+		Media:                  toMediaInfoDto(media),
+		QueuePosition:          position,
 	}
 
 	return response, nil
 }
 
+// reserveJobName は、同じ名前のジョブを開始していないことを確認し、送信の結果が出るまで名前を予約します。
+// 送信済みや送信を待っているジョブの記録は上書きしません (同じ名前のジョブは Amazon Transcribe でも作成できない)。
+// 送信できなかったジョブだけは、開始した呼び出し元が同じ名前で開始し直せます
+func (s *TranscriptionJobService) reserveJobName(ctx context.Context, jobName, display string) error {
+	s.startingMu.Lock()
+	defer s.startingMu.Unlock()
+
+	if s.starting[jobName] || s.SubmissionGovernor.Position(jobName) > 0 {
+		return domainerr.New(domainerr.Conflict, "transcription_job_conflict", "transcription job %s is already being started", display)
+	}
+	if existing, err := s.Repo.FindByID(jobName); err == nil &&
+		(existing.Status != model.TranscriptionJobStatusSubmissionFailed || !model.PrincipalFromContext(ctx).CanAccess(existing.Owner)) {
+		return domainerr.New(domainerr.Conflict, "transcription_job_conflict", "transcription job %s already exists", display)
+	}
+	s.starting[jobName] = true
+	return nil
+}
+
+// releaseJobName は、reserveJobName で予約した名前を解放します
+func (s *TranscriptionJobService) releaseJobName(jobName string) {
+	s.startingMu.Lock()
+	defer s.startingMu.Unlock()
+	delete(s.starting, jobName)
+}
+
+// checkTenantMedia は、テナントのジョブが他のテナントのプレフィックスにあるメディアを参照していないことを確認します。
+// 設定したバケット以外のメディアは Amazon Transcribe の権限に任せます
+func checkTenantMedia(ctx context.Context, mediaURI string) error {
+	tenant := model.TenantFromContext(ctx)
+	bucketName, key, ok := model.ParseS3URI(mediaURI)
+	if tenant == nil || !ok || bucketName != config.AppConfig.S3BucketName {
+		return nil
+	}
+	if validateUploadKey(ctx, key) != nil {
+		return domainerr.New(domainerr.Forbidden, "media_forbidden", "media %s is not in the uploads of tenant %s", mediaURI, tenant.ID)
+	}
+	return nil
+}
+
+// probeJobMedia は、ジョブのメディアを解析します。文字起こしできないメディアの場合はジョブを開始する前にエラーを返します。
+// S3 以外のURI、設定されたバケット以外のオブジェクト、読み込めないオブジェクトは解析せずにnilを返し、Amazon Transcribe の判定に任せます
+func (s *TranscriptionJobService) probeJobMedia(ctx context.Context, mediaURI string) (*model.MediaInfo, error) {
+	bucketName, key, ok := model.ParseS3URI(mediaURI)
+	// サーバーの権限で任意のバケットを読み込まないよう、設定されたバケットのオブジェクトだけを解析する
+	if !ok || bucketName != config.AppConfig.S3BucketName {
+		return nil, nil
+	}
+	media, err := probeS3Media(ctx, s.S3StorageService, s.MediaProber, bucketName, key)
+	if err != nil {
+		if domainErr, ok := domainerr.As(err); ok {
+			switch domainErr.Kind {
+			case domainerr.Unprocessable:
+				return nil, domainerr.Wrap(domainerr.Unprocessable, domainErr.Code, err, "media %s cannot be transcribed: %s", mediaURI, domainErr.Message)
+			case domainerr.NotFound:
+				return nil, domainerr.Wrap(domainerr.Unprocessable, "media_not_found", err, "media %s does not exist", mediaURI)
+			}
+		}
+		logger.FromContext(ctx).Warn("Failed to probe media", "media_uri", mediaURI, "error", err)
+		return nil, nil
+	}
+	return media, nil
+}
+
+const (
+	defaultVocabularyWaitTimeout = 60 * time.Second
+	maxVocabularyWaitTimeout     = 10 * time.Minute
+	vocabularyPollInterval       = 5 * time.Second
+)
+
+// vocabularyWaitTimeout リクエストで指定された待機秒数を上限内に丸めます
+func vocabularyWaitTimeout(seconds int) time.Duration {
+	if seconds <= 0 {
+		return defaultVocabularyWaitTimeout
+	}
+	timeout := time.Duration(seconds) * time.Second
+	if timeout > maxVocabularyWaitTimeout {
+		return maxVocabularyWaitTimeout
+	}
+	return timeout
+}
+
+// checkVocabularyReady カスタムボキャブラリがREADYで、ジョブと同じ言語コードであることを確認します。
+// timeoutが0より大きい場合、PENDINGのボキャブラリがREADYになるまで待機します。
+func (s *TranscriptionJobService) checkVocabularyReady(ctx context.Context, name, languageCode string, timeout time.Duration) error {
+	deadline := time.Now().Add(timeout)
+	for {
+		vocabulary, err := s.CustomVocabularyService.GetCustomVocabularyByName(ctx, name)
+		if err != nil {
+			if errors.Is(err, domainerr.NotFound) {
+				return domainerr.Wrap(domainerr.Unprocessable, "custom_vocabulary_not_found", err, "custom vocabulary %s does not exist", name)
+			}
+			return fmt.Errorf("failed to check custom vocabulary: %w", err)
+		}
+
+		if vocabulary.LanguageCode != languageCode {
+			return domainerr.New(domainerr.Unprocessable, "custom_vocabulary_language_mismatch", "custom vocabulary %s is for %s but the job language is %s", name, vocabulary.LanguageCode, languageCode)
+		}
+
+		switch vocabulary.VocabularyState {
+		case model.VocabularyStateReady:
+			return nil
+		case model.VocabularyStateFailed:
+			return domainerr.New(domainerr.Unprocessable, "custom_vocabulary_failed", "custom vocabulary %s is FAILED: %s", name, vocabulary.FailureReason)
+		}
+
+		// PENDINGの場合はタイムアウトまで待機
+		if !time.Now().Add(vocabularyPollInterval).Before(deadline) {
+			return domainerr.New(domainerr.Conflict, "custom_vocabulary_not_ready", "custom vocabulary %s is %s, not READY", name, vocabulary.VocabularyState)
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(vocabularyPollInterval):
+		}
+	}
+}
+
 const timeFormat = "2006/01/02 15:04:05" // 'yyyy/MM/dd HH:mm:ss'形式
 
-// GetTranscriptionJobList AWS Transcribeからジョブリストを取得します。
-func (s *TranscriptionJobService) GetTranscriptionJobList(ctx context.Context) (*dto.TranscriptionJobsResponseDto, error) {
+// defaultJobPageSize ジョブの一覧の1ページに取得するジョブの数の既定値
+const defaultJobPageSize = 50
+
+// maxScannedJobs 語彙の提案や効果レポートで走査する、新しい順の完了済みジョブの数の上限
+const maxScannedJobs = 500
+
+// recentCompletedJobs 呼び出し元のテナントの完了済みのジョブを新しい順に、最大 maxScannedJobs 件まで取得します。
+// リクエストごとにアカウントのすべてのジョブを読まないよう、状態とテナントの接頭辞で絞り込んでページを辿ります
+func recentCompletedJobs(ctx context.Context, jobService service.TranscriptionJobService) ([]*model.TranscriptionJobSummaryResponse, error) {
+	var jobs []*model.TranscriptionJobSummaryResponse
+	query := model.TranscriptionJobListQuery{
+		NameContains: resourceNameFilter(ctx),
+		Status:       model.TranscriptionJobStatusCompleted,
+		MaxResults:   model.MaxTranscriptionJobPageSize,
+	}
+	for len(jobs) < maxScannedJobs {
+		page, err := jobService.GetTranscriptionJobList(ctx, query)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get transcription job list: %w", err)
+		}
+		for _, job := range page.Jobs {
+			if ownsResource(ctx, job.JobName) && len(jobs) < maxScannedJobs {
+				jobs = append(jobs, job)
+			}
+		}
+		if page.NextToken == "" {
+			break
+		}
+		query.NextToken = page.NextToken
+	}
+	return jobs, nil
+}
+
+// GetTranscriptionJobList AWS Transcribeからジョブリストを1ページ分取得します。
+// 最初のページ (nextToken が空) には、Amazon Transcribe に送信していないジョブも先頭に含めます。
+// テナントの接頭辞で絞り込んだ後に参照できないジョブを除くため、1ページのジョブが limit より少なくなることがあります
+func (s *TranscriptionJobService) GetTranscriptionJobList(ctx context.Context, nextToken string, limit int) (*dto.TranscriptionJobsResponseDto, error) {
+	if limit <= 0 {
+		limit = defaultJobPageSize
+	}
+	if limit > model.MaxTranscriptionJobPageSize {
+		limit = model.MaxTranscriptionJobPageSize
+	}
+
 	// 日本時間のLocationを取得
 	jst, err := time.LoadLocation("Asia/Tokyo")
 	if err != nil {
-		return nil, fmt.Errorf("failed to load JST location: %v", err)
+		return nil, fmt.Errorf("failed to load JST location: %w", err)
 	}
 
 	// AWS Transcribeからジョブリストを取得
-	jobs, err := s.TranscriptionJobService.GetTranscriptionJobList(ctx) // ドメイン層のメソッドを呼び出す
+	jobs, err := s.TranscriptionJobService.GetTranscriptionJobList(ctx, model.TranscriptionJobListQuery{
+		NameContains: resourceNameFilter(ctx),
+		NextToken:    nextToken,
+		MaxResults:   limit,
+	}) // ドメイン層のメソッドを呼び出す
 	if err != nil {
-		return nil, fmt.Errorf("failed to get transcription job list: %v", err)
+		return nil, fmt.Errorf("failed to get transcription job list: %w", err)
 	}
 
-	// DTOに変換する
-	var dtoJobs []dto.TranscriptionJobSummaryDto
+	// DTOに変換する (呼び出し元のテナントの、呼び出し元が参照できるジョブのみ)
+	principal := model.PrincipalFromContext(ctx)
+	dtoJobs := []dto.TranscriptionJobSummaryDto{}
+	if nextToken == "" {
+		if dtoJobs, err = s.unsubmittedJobSummaries(ctx, jst); err != nil {
+			return nil, err
+		}
+	}
 	for _, job := range jobs.Jobs { // jobs.Jobs はドメインモデル内のジョブリスト
+		owner := s.jobOwner(job.JobName)
+		if !ownsResource(ctx, job.JobName) || !principal.CanAccess(owner) {
+			continue
+		}
+		s.observeJobStatus(job.JobName, job.LanguageCode, job.TranscriptionJobStatus, job.CreationTime, job.CompletionTime)
 		// CreationTimeとCompletionTimeをフォーマットしてDTOにセット
 		dtoJob := dto.TranscriptionJobSummaryDto{
-			JobName:                job.JobName,
+			JobName:                displayName(ctx, job.JobName),
 			CreationTime:           job.CreationTime.In(jst).Format(timeFormat),
 			CompletionTime:         formatCompletionTime(job.CompletionTime, jst),
 			LanguageCode:           job.LanguageCode,
 			TranscriptionJobStatus: job.TranscriptionJobStatus,
 			OutputLocationType:     job.OutputLocationType,
+			Owner:                  owner,
 		}
 
 		// DTOのValidateメソッドを呼び出す
 		if err := dtoJob.Validate(); err != nil {
-			return nil, fmt.Errorf("validation error: %v", err)
+			return nil, fmt.Errorf("invalid transcription job %s: %w", job.JobName, err)
 		}
 		dtoJobs = append(dtoJobs, dtoJob)
 	}
 
 	// 最終的にDTOリストを返す
 	response := dto.TranscriptionJobsResponseDto{
-		Jobs: dtoJobs,
+		Jobs:      dtoJobs,
+		NextToken: jobs.NextToken,
 	}
 	return &response, nil
 }
@@ -112,34 +343,165 @@ func (s *TranscriptionJobService) GetTranscriptionJob(ctx context.Context, jobNa
 	// 日本時間のLocationを取得
 	jst, err := time.LoadLocation("Asia/Tokyo")
 	if err != nil {
-		return nil, fmt.Errorf("failed to load JST location: %v", err)
+		return nil, fmt.Errorf("failed to load JST location: %w", err)
+	}
+
+	// 他の呼び出し元のジョブは存在しないものとして扱う
+	jobName = resourceName(ctx, jobName)
+	if err := checkJobAccess(ctx, s.Repo, jobName); err != nil {
+		return nil, err
+	}
+
+	// Amazon Transcribe に送信していないジョブは記録から返す
+	if record, err := s.Repo.FindByID(jobName); err == nil && record.IsUnsubmitted() {
+		return s.unsubmittedJob(ctx, record, jst), nil
 	}
 
 	// AWS Transcribeから特定のジョブを取得
 	job, err := s.TranscriptionJobService.GetTranscriptionJob(ctx, jobName) // ドメイン層のメソッドを呼び出し
 	if err != nil {
-		return nil, fmt.Errorf("failed to get transcription job: %v", err)
+		return nil, fmt.Errorf("failed to get transcription job: %w", err)
 	}
+	s.observeJobStatus(job.JobName, job.LanguageCode, job.TranscriptionJobStatus, job.CreationTime, job.CompletionTime)
 
 	// DTOに変換する
 	dtoJob := dto.TranscriptionJobResponseDto{
-		JobName:                job.JobName,
+		JobName:                displayName(ctx, job.JobName),
 		CreationTime:           job.CreationTime.In(jst).Format(timeFormat),   // 日本時間に変換
 		CompletionTime:         formatCompletionTime(job.CompletionTime, jst), // 完了時間も日本時間に変換
 		LanguageCode:           job.LanguageCode,
 		TranscriptionJobStatus: job.TranscriptionJobStatus,
 		TranscriptFileUri:      job.OutputLocation, // 出力ファイルのURLをセット
+		Owner:                  s.jobOwner(jobName),
 	}
 
 	// DTOのバリデーションを実行
 	if err := dtoJob.Validate(); err != nil {
-		return nil, fmt.Errorf("validation error: %v", err)
+		return nil, fmt.Errorf("invalid transcription job %s: %w", jobName, err)
 	}
 
 	// DTOを返す
 	return &dtoJob, nil
 }
 
+// unsubmittedJobSummaries 送信を待っているジョブを順番に並べ、送信に失敗したジョブを続けた一覧を返します
+func (s *TranscriptionJobService) unsubmittedJobSummaries(ctx context.Context, loc *time.Location) ([]dto.TranscriptionJobSummaryDto, error) {
+	summaries := []dto.TranscriptionJobSummaryDto{}
+	for _, queued := range s.SubmissionGovernor.Queued() {
+		if canAccessJob(ctx, s.Repo, queued.Record.JobName) {
+			summaries = append(summaries, toUnsubmittedJobSummary(ctx, queued.Record, s.SubmissionGovernor.Position(queued.Record.JobName), loc))
+		}
+	}
+	records, err := s.Repo.FindAll()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get transcription jobs: %w", err)
+	}
+	for _, record := range records {
+		if record.Status == model.TranscriptionJobStatusSubmissionFailed && canAccessJob(ctx, s.Repo, record.JobName) {
+			summaries = append(summaries, toUnsubmittedJobSummary(ctx, record, 0, loc))
+		}
+	}
+	return summaries, nil
+}
+
+// toUnsubmittedJobSummary Amazon Transcribe に送信していないジョブの記録を一覧の要素に変換します
+func toUnsubmittedJobSummary(ctx context.Context, record *model.TranscriptionJobDB, position int, loc *time.Location) dto.TranscriptionJobSummaryDto {
+	return dto.TranscriptionJobSummaryDto{
+		JobName:                displayName(ctx, record.JobName),
+		CreationTime:           record.CreatedAt.In(loc).Format(timeFormat),
+		LanguageCode:           record.Language,
+		TranscriptionJobStatus: record.Status,
+		Owner:                  record.Owner,
+		QueuePosition:          position,
+		FailureReason:          record.FailureReason,
+	}
+}
+
+// unsubmittedJob Amazon Transcribe に送信していないジョブの記録を返却値に変換します
+func (s *TranscriptionJobService) unsubmittedJob(ctx context.Context, record *model.TranscriptionJobDB, loc *time.Location) *dto.TranscriptionJobResponseDto {
+	return &dto.TranscriptionJobResponseDto{
+		JobName:                displayName(ctx, record.JobName),
+		CreationTime:           record.CreatedAt.In(loc).Format(timeFormat),
+		LanguageCode:           record.Language,
+		TranscriptionJobStatus: record.Status,
+		Owner:                  record.Owner,
+		QueuePosition:          s.SubmissionGovernor.Position(record.JobName),
+		FailureReason:          record.FailureReason,
+	}
+}
+
+// observeJobStatus このサービスで開始したジョブの状態を記録します。
+// 完了したことを初めて確認した場合はメトリクスに記録し、送信を待っているジョブを次の確認を待たずに送信させます
+func (s *TranscriptionJobService) observeJobStatus(jobName, languageCode, status string, creationTime time.Time, completionTime *time.Time) {
+	s.statusMu.Lock()
+	defer s.statusMu.Unlock()
+
+	record, err := s.Repo.FindByID(jobName)
+	if err != nil || record.Status == status || record.IsUnsubmitted() {
+		return
+	}
+	// リポジトリはコピーを返すため、変更した状態を保存し直す
+	completed := record.ApplyStatus(status)
+	if err := s.Repo.Save(record); err != nil {
+		logger.Default().Error("Failed to save transcription job", "job_name", jobName, "error", err)
+	}
+	if !completed {
+		return
+	}
+	if status == model.TranscriptionJobStatusCompleted {
+		metrics.TranscriptionJobsCompleted.Inc(languageCode)
+	} else {
+		metrics.TranscriptionJobsFailed.Inc(languageCode)
+	}
+	if completionTime != nil {
+		metrics.TranscriptionJobDuration.Observe(completionTime.Sub(creationTime).Seconds(), languageCode)
+	}
+	s.SubmissionGovernor.Finished(jobName)
+}
+
+// jobOwner ジョブを開始した呼び出し元を返します。このサービス以外で開始されたジョブは空です
+func (s *TranscriptionJobService) jobOwner(jobName string) string {
+	job, err := s.Repo.FindByID(jobName)
+	if err != nil {
+		return ""
+	}
+	return job.Owner
+}
+
+// canAccessJob 呼び出し元がジョブを参照できるかどうかを返します。jobName はテナントの接頭辞を付けた名前です。
+// 他のテナントのジョブは参照できず、所有者の記録が無いジョブ (このサービス以外で開始されたジョブなど) は管理者のみが参照できます
+func canAccessJob(ctx context.Context, repo repository.TranscriptionJobRepository, jobName string) bool {
+	if !ownsResource(ctx, jobName) {
+		return false
+	}
+	principal := model.PrincipalFromContext(ctx)
+	if principal == nil || principal.Admin {
+		return true
+	}
+	job, err := repo.FindByID(jobName)
+	return err == nil && principal.CanAccess(job.Owner)
+}
+
+// transcriptKey ジョブの文字起こし結果が出力されたS3のキーを返します。
+// 出力先を記録する前に開始したジョブはバケット直下、記録が無いジョブは呼び出し元のテナントの出力先にあるものとします
+func transcriptKey(ctx context.Context, repo repository.TranscriptionJobRepository, jobName string) string {
+	if job, err := repo.FindByID(jobName); err == nil {
+		if job.OutputKey != "" {
+			return job.OutputKey
+		}
+		return model.TranscriptKey("", jobName)
+	}
+	return model.TenantFromContext(ctx).TranscriptKey(jobName)
+}
+
+// checkJobAccess 呼び出し元が参照できないジョブの場合、存在を明かさないよう NotFound を返します
+func checkJobAccess(ctx context.Context, repo repository.TranscriptionJobRepository, jobName string) error {
+	if !canAccessJob(ctx, repo, jobName) {
+		return domainerr.New(domainerr.NotFound, "transcription_job_not_found", "transcription job %s does not exist", displayName(ctx, jobName))
+	}
+	return nil
+}
+
 // CompletionTimeのフォーマット（nilチェック付き、JSTに変換）
 func formatCompletionTime(completionTime *time.Time, loc *time.Location) string {
 	if completionTime != nil {
@@ -150,23 +512,28 @@ func formatCompletionTime(completionTime *time.Time, loc *time.Location) string
 
 // GetTranscriptionContent refactors transcription content for frontend
 func (s *TranscriptionJobService) GetTranscriptionContent(ctx context.Context, transcriptFileUri string) (*dto.TranscriptionContentResponseDto, error) {
+	transcriptFileUri = resourceName(ctx, transcriptFileUri)
+	if err := checkJobAccess(ctx, s.Repo, transcriptFileUri); err != nil {
+		return nil, err
+	}
+
 	// S3ストレージサービスを使って署名付きURLを生成
-	signedURL, err := s.S3StorageService.GeneratePresignedURL(ctx, transcriptFileUri)
+	signedURL, err := s.S3StorageService.GeneratePresignedURL(ctx, config.AppConfig.S3BucketName, transcriptKey(ctx, s.Repo, transcriptFileUri))
 	if err != nil {
-		return nil, fmt.Errorf("failed to generate signed URL: %v", err)
+		return nil, fmt.Errorf("failed to generate signed URL: %w", err)
 	}
 
 	// 署名付きURLを使って文字起こしの内容を取得
 	content, err := s.S3StorageService.GetTranscriptionContent(ctx, signedURL)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get transcription content: %v", err)
+		return nil, fmt.Errorf("failed to get transcription content: %w", err)
 	}
 
 	// JSONをパースして、全体のデータを取得
 	var transcribeResult map[string]interface{}
 	err = json.Unmarshal([]byte(content), &transcribeResult)
 	if err != nil {
-		return nil, fmt.Errorf("failed to parse transcription content: %v", err)
+		return nil, fmt.Errorf("failed to parse transcription content: %w", err)
 	}
 
 	// transcriptsのテキストを取得
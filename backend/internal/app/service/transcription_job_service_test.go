@@ -0,0 +1,138 @@
+package service
+
+import (
+	"cmTranscribe/internal/domain/domainerr"
+	"cmTranscribe/internal/domain/model"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTranscriptionJobServiceReserveJobName(t *testing.T) {
+	repo := newTestJobRepo(t)
+	governor := NewTranscriptionSubmissionGovernor(repo, newStubTranscriptionJobService(), 0, 10, time.Second)
+	s := NewTranscriptionJobService(repo, nil, nil, nil, nil, governor)
+	alice := model.ContextWithPrincipal(context.Background(), &model.Principal{Subject: "alice"})
+	bob := model.ContextWithPrincipal(context.Background(), &model.Principal{Subject: "bob"})
+
+	for name, status := range map[string]string{
+		"completed": model.TranscriptionJobStatusCompleted,
+		"pending":   model.TranscriptionJobStatusPendingSubmission,
+		"failed":    model.TranscriptionJobStatusSubmissionFailed,
+	} {
+		record := model.NewTranscriptionJobDB(name, "s3://bucket/uploads/a.wav", "ja-JP", "alice")
+		record.Status = status
+		if err := repo.Save(record); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		name    string
+		ctx     context.Context
+		jobName string
+		wantErr bool
+	}{
+		{name: "new name", ctx: alice, jobName: "new"},
+		{name: "completed job of the owner", ctx: alice, jobName: "completed", wantErr: true},
+		{name: "pending job of the owner", ctx: alice, jobName: "pending", wantErr: true},
+		{name: "failed job of the owner", ctx: alice, jobName: "failed"},
+		{name: "failed job of another caller", ctx: bob, jobName: "failed", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := s.reserveJobName(tt.ctx, tt.jobName, tt.jobName)
+			if tt.wantErr {
+				if !errors.Is(err, domainerr.Conflict) {
+					t.Fatalf("reserveJobName() error = %v, want conflict", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			// 送信の結果が出るまで同じ名前を予約する
+			if err := s.reserveJobName(tt.ctx, tt.jobName, tt.jobName); !errors.Is(err, domainerr.Conflict) {
+				t.Errorf("second reserveJobName() error = %v, want conflict", err)
+			}
+			s.releaseJobName(tt.jobName)
+			if err := s.reserveJobName(tt.ctx, tt.jobName, tt.jobName); err != nil {
+				t.Errorf("reserveJobName() after release: %v", err)
+			}
+			s.releaseJobName(tt.jobName)
+		})
+	}
+}
+
+func TestRecentCompletedJobs(t *testing.T) {
+	jobService := newStubTranscriptionJobService()
+	for i := 0; i < maxScannedJobs+50; i++ {
+		jobService.listed = append(jobService.listed,
+			&model.TranscriptionJobSummaryResponse{JobName: fmt.Sprintf("team-a.job-%d", i), TranscriptionJobStatus: model.TranscriptionJobStatusCompleted},
+			&model.TranscriptionJobSummaryResponse{JobName: fmt.Sprintf("team-b.job-%d", i), TranscriptionJobStatus: model.TranscriptionJobStatusCompleted},
+		)
+	}
+	jobService.listed = append(jobService.listed,
+		&model.TranscriptionJobSummaryResponse{JobName: "team-a.running", TranscriptionJobStatus: model.TranscriptionJobStatusInProgress},
+		// 接頭辞を名前の途中に含む他のテナントのジョブ
+		&model.TranscriptionJobSummaryResponse{JobName: "team-b.team-a.job", TranscriptionJobStatus: model.TranscriptionJobStatusCompleted},
+	)
+
+	jobs, err := recentCompletedJobs(tenantContext("team-a", 0), jobService)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(jobs) != maxScannedJobs {
+		t.Fatalf("got %d jobs, want %d", len(jobs), maxScannedJobs)
+	}
+	for _, job := range jobs {
+		if !strings.HasPrefix(job.JobName, "team-a.") || job.TranscriptionJobStatus != model.TranscriptionJobStatusCompleted {
+			t.Fatalf("unexpected job %+v", job)
+		}
+	}
+	// 上限に達したら残りのページを読まない
+	if got, want := len(jobService.queries), maxScannedJobs/model.MaxTranscriptionJobPageSize; got != want {
+		t.Errorf("listed %d pages, want %d", got, want)
+	}
+	for _, query := range jobService.queries {
+		if query.NameContains != "team-a." || query.Status != model.TranscriptionJobStatusCompleted {
+			t.Errorf("query = %+v, want the tenant prefix and COMPLETED", query)
+		}
+	}
+}
+
+func TestTranscriptKey(t *testing.T) {
+	repo := newTestJobRepo(t)
+	recorded := model.NewTranscriptionJobDB("team-a.recorded", "s3://bucket/a.wav", "ja-JP", "")
+	recorded.OutputKey = "tenants/team-a/transcripts/team-a.recorded.json"
+	legacy := model.NewTranscriptionJobDB("team-a.legacy", "s3://bucket/a.wav", "ja-JP", "")
+	for _, job := range []*model.TranscriptionJobDB{recorded, legacy} {
+		if err := repo.Save(job); err != nil {
+			t.Fatal(err)
+		}
+	}
+	tenant := model.NewTenant("team-a", "", "", "", "", model.TenantQuota{}).WithDefaultPrefixes("uploads", "vocabulary", "vocabulary-filter")
+	tenantCtx := model.ContextWithTenant(context.Background(), tenant)
+
+	tests := []struct {
+		name    string
+		ctx     context.Context
+		jobName string
+		want    string
+	}{
+		{name: "recorded output key", ctx: context.Background(), jobName: "team-a.recorded", want: "tenants/team-a/transcripts/team-a.recorded.json"},
+		{name: "record without output key", ctx: tenantCtx, jobName: "team-a.legacy", want: "team-a.legacy.json"},
+		{name: "no record with tenant", ctx: tenantCtx, jobName: "team-a.other", want: "tenants/team-a/transcripts/team-a.other.json"},
+		{name: "no record without tenant", ctx: context.Background(), jobName: "other", want: "other.json"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := transcriptKey(tt.ctx, repo, tt.jobName); got != tt.want {
+				t.Errorf("transcriptKey() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,357 @@
+package service
+
+import (
+	"cmTranscribe/internal/domain/domainerr"
+	"cmTranscribe/internal/domain/model"
+	"cmTranscribe/internal/domain/repository"
+	"cmTranscribe/internal/domain/service"
+	"cmTranscribe/internal/shared/logger"
+	"cmTranscribe/internal/shared/metrics"
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// TranscriptionSubmissionGovernor アカウントの同時実行数の上限に達している間、文字起こしジョブの開始を待たせ、
+// 空きができた順に Amazon Transcribe に送信します。
+// テナントの実行中のジョブの上限も、送信したジョブと待っているジョブを自身で数えて確認します
+type TranscriptionSubmissionGovernor struct {
+	Repo                    repository.TranscriptionJobRepository
+	TranscriptionJobService service.TranscriptionJobService
+	maxConcurrentJobs       int // 0の場合は上限を設けず、すべてのジョブをすぐに送信する
+	maxQueuedJobs           int
+	interval                time.Duration
+	trigger                 chan struct{}
+
+	mu      sync.Mutex
+	active  int // 実行中のジョブの数 (同期の間に送信した分を加えた見込み)
+	queue   []*queuedSubmission
+	tracked map[string]*trackedJob // 送信中または実行中のテナントのジョブ (ジョブ名ごと)
+}
+
+// trackedJob テナントの上限に数える、送信中または実行中のジョブ
+type trackedJob struct {
+	tenantID  string
+	submitted bool // Amazon Transcribe が受け付けた (状態を確認して終了を判定できる)
+}
+
+// queuedSubmission 送信を待っているジョブと、開始を要求したリクエストのロガー
+type queuedSubmission struct {
+	model.QueuedTranscriptionJob
+	logger *logger.Logger
+}
+
+// NewTranscriptionSubmissionGovernor 新しい TranscriptionSubmissionGovernor を作成します
+func NewTranscriptionSubmissionGovernor(
+	repo repository.TranscriptionJobRepository,
+	jobService service.TranscriptionJobService,
+	maxConcurrentJobs, maxQueuedJobs int,
+	interval time.Duration,
+) *TranscriptionSubmissionGovernor {
+	return &TranscriptionSubmissionGovernor{
+		Repo:                    repo,
+		TranscriptionJobService: jobService,
+		maxConcurrentJobs:       maxConcurrentJobs,
+		maxQueuedJobs:           maxQueuedJobs,
+		interval:                interval,
+		trigger:                 make(chan struct{}, 1),
+		tracked:                 make(map[string]*trackedJob),
+	}
+}
+
+// Submit ジョブを Amazon Transcribe に送信します。
+// テナントの実行中のジョブと送信を待っているジョブの数が上限に達している場合はエラーを返します。
+// 同時実行数の上限に達している場合や Amazon Transcribe がスロットリングした場合は送信を待たせ、
+// PENDING_SUBMISSION の状態と待ち行列の順番 (1から) を返します。すぐに送信した場合の順番は0です
+func (g *TranscriptionSubmissionGovernor) Submit(ctx context.Context, job *model.TranscriptionJob, record *model.TranscriptionJobDB) (*model.TranscriptionJobStatusResponse, int, error) {
+	g.mu.Lock()
+	if g.indexOf(job.JobName) >= 0 {
+		g.mu.Unlock()
+		return nil, 0, domainerr.New(domainerr.Conflict, "transcription_job_conflict", "transcription job %s is already waiting for submission", displayName(ctx, job.JobName))
+	}
+	// 上限の確認と枠の確保を同じロックの中で行い、同時に開始したジョブが上限を超えないようにする
+	if err := g.checkQuota(ctx); err != nil {
+		g.mu.Unlock()
+		return nil, 0, err
+	}
+	if g.maxConcurrentJobs > 0 && (len(g.queue) > 0 || g.active >= g.maxConcurrentJobs) {
+		position, err := g.enqueue(ctx, job, record)
+		g.mu.Unlock()
+		if err != nil {
+			return nil, 0, err
+		}
+		return model.NewTranscriptionJobStatusResponse(job.JobName, record.Status), position, nil
+	}
+	g.active++
+	g.track(record)
+	g.mu.Unlock()
+
+	result, position, err := g.start(ctx, job, record)
+	if err == nil {
+		return result, position, nil
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.active--
+	delete(g.tracked, job.JobName)
+	// アカウントの上限に達していた場合は、送信を待たせて次の同期で再送する
+	if g.maxConcurrentJobs > 0 && errors.Is(err, domainerr.Throttled) {
+		if position, err := g.enqueue(ctx, job, record); err == nil {
+			return model.NewTranscriptionJobStatusResponse(job.JobName, record.Status), position, nil
+		}
+	}
+	return nil, 0, err
+}
+
+// start ジョブを送信し、受け付けられた状態を記録します
+func (g *TranscriptionSubmissionGovernor) start(ctx context.Context, job *model.TranscriptionJob, record *model.TranscriptionJobDB) (*model.TranscriptionJobStatusResponse, int, error) {
+	result, err := g.TranscriptionJobService.StartTranscriptionJob(ctx, job)
+	if err != nil {
+		return nil, 0, err
+	}
+	metrics.TranscriptionJobsSubmitted.Inc(job.LanguageCode)
+	g.mu.Lock()
+	if tracked, ok := g.tracked[job.JobName]; ok {
+		tracked.submitted = true
+	}
+	g.mu.Unlock()
+	record.MarkSubmitted(result.TranscriptionJobStatus)
+	if err := g.Repo.Save(record); err != nil {
+		logger.FromContext(ctx).Error("Failed to save transcription job", "job_name", job.JobName, "error", err)
+	}
+	return result, 0, nil
+}
+
+// enqueue ジョブを待ち行列の末尾に追加し、順番を返します。呼び出し元で mu をロックします
+func (g *TranscriptionSubmissionGovernor) enqueue(ctx context.Context, job *model.TranscriptionJob, record *model.TranscriptionJobDB) (int, error) {
+	if len(g.queue) >= g.maxQueuedJobs {
+		return 0, domainerr.New(domainerr.Throttled, "submission_queue_full", "%d transcription jobs are already waiting for submission, retry later", len(g.queue))
+	}
+	record.MarkPendingSubmission(job)
+	if err := g.Repo.Save(record); err != nil {
+		return 0, err
+	}
+	g.queue = append(g.queue, &queuedSubmission{
+		QueuedTranscriptionJob: model.QueuedTranscriptionJob{Job: job, Record: record, EnqueuedAt: time.Now()},
+		logger:                 logger.FromContext(ctx),
+	})
+	logger.FromContext(ctx).Info("Transcription job is waiting for submission", "job_name", job.JobName, "queue_position", len(g.queue), "active_jobs", g.active)
+	return len(g.queue), nil
+}
+
+// CheckQuota テナントの実行中のジョブと送信を待っているジョブの数が上限に達している場合にエラーを返します。
+// 送信する前の確認に使います。上限は Submit でも改めて確認します
+func (g *TranscriptionSubmissionGovernor) CheckQuota(ctx context.Context) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.checkQuota(ctx)
+}
+
+// checkQuota 呼び出し元のテナントの上限を確認します。呼び出し元で mu をロックします
+func (g *TranscriptionSubmissionGovernor) checkQuota(ctx context.Context) error {
+	tenant := model.TenantFromContext(ctx)
+	if tenant == nil || tenant.Quota.MaxActiveJobs <= 0 {
+		return nil
+	}
+	active := 0
+	for _, tracked := range g.tracked {
+		if tracked.tenantID == tenant.ID {
+			active++
+		}
+	}
+	for _, submission := range g.queue {
+		if submission.Record.TenantID == tenant.ID {
+			active++
+		}
+	}
+	return checkTenantQuota(ctx, "active transcription jobs", active, tenant.Quota.MaxActiveJobs)
+}
+
+// track テナントのジョブを上限に数え始めます。呼び出し元で mu をロックします
+func (g *TranscriptionSubmissionGovernor) track(record *model.TranscriptionJobDB) {
+	if record.TenantID != "" {
+		g.tracked[record.JobName] = &trackedJob{tenantID: record.TenantID}
+	}
+}
+
+// Finished ジョブの終了が分かった時に呼び出し、テナントの上限から外して次の確認を待たずに送信させます
+func (g *TranscriptionSubmissionGovernor) Finished(jobName string) {
+	g.mu.Lock()
+	delete(g.tracked, jobName)
+	g.mu.Unlock()
+	g.Notify()
+}
+
+// Recover リポジトリに残っている送信を待っているジョブを作成日時の順に待ち行列へ戻し、
+// 実行中のテナントのジョブを上限に数え直します。起動時に Run より前に呼び出します
+func (g *TranscriptionSubmissionGovernor) Recover(ctx context.Context) error {
+	records, err := g.Repo.FindAll()
+	if err != nil {
+		return fmt.Errorf("failed to find transcription jobs: %w", err)
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	for _, record := range records {
+		switch {
+		case record.Status == model.TranscriptionJobStatusPendingSubmission && record.Submission != nil:
+			g.queue = append(g.queue, &queuedSubmission{
+				QueuedTranscriptionJob: model.QueuedTranscriptionJob{Job: record.Submission, Record: record, EnqueuedAt: time.Now()},
+				logger:                 logger.FromContext(ctx),
+			})
+		case record.Status == model.TranscriptionJobStatusPendingSubmission:
+			record.MarkSubmissionFailed("the submission request was not recorded before the server restarted")
+			if err := g.Repo.Save(record); err != nil {
+				return err
+			}
+		case record.Status == model.TranscriptionJobStatusPending || model.IsActiveTranscriptionJobStatus(record.Status):
+			// 送信中だったジョブは Amazon Transcribe の状態を確認するまで実行中として数える
+			g.track(record)
+			if tracked, ok := g.tracked[record.JobName]; ok {
+				tracked.submitted = true
+			}
+		}
+	}
+	if len(g.queue) > 0 {
+		logger.FromContext(ctx).Info("Recovered transcription jobs waiting for submission", "queued_jobs", len(g.queue))
+	}
+	return nil
+}
+
+// Position 送信を待っているジョブの順番 (1から) を返します。待っていない場合は0です
+func (g *TranscriptionSubmissionGovernor) Position(jobName string) int {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.indexOf(jobName) + 1
+}
+
+// Queued 送信を待っているジョブを順番に返します
+func (g *TranscriptionSubmissionGovernor) Queued() []model.QueuedTranscriptionJob {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	queued := make([]model.QueuedTranscriptionJob, 0, len(g.queue))
+	for _, submission := range g.queue {
+		queued = append(queued, submission.QueuedTranscriptionJob)
+	}
+	return queued
+}
+
+// indexOf 待ち行列でのジョブの位置を返します。呼び出し元で mu をロックします
+func (g *TranscriptionSubmissionGovernor) indexOf(jobName string) int {
+	for i, submission := range g.queue {
+		if submission.Job.JobName == jobName {
+			return i
+		}
+	}
+	return -1
+}
+
+// Run ctxがキャンセルされるまで、一定間隔で実行中のジョブの数を確認し、空きができた分の待っているジョブを送信します。
+// テナントの上限に数えているジョブの終了も確認します
+func (g *TranscriptionSubmissionGovernor) Run(ctx context.Context) {
+	ticker := time.NewTicker(g.interval)
+	defer ticker.Stop()
+
+	for {
+		g.releaseFinished(ctx)
+		g.drain(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-g.trigger:
+		}
+	}
+}
+
+// Notify ジョブの完了が分かった時などに、次の確認を待たずに送信させます
+func (g *TranscriptionSubmissionGovernor) Notify() {
+	select {
+	case g.trigger <- struct{}{}:
+	default:
+	}
+}
+
+// releaseFinished テナントの上限に数えている送信済みのジョブの状態を確認し、終了したジョブを上限から外します。
+// 状態を誰も取得しないまま終了したジョブも、次の確認で上限から外れます
+func (g *TranscriptionSubmissionGovernor) releaseFinished(ctx context.Context) {
+	g.mu.Lock()
+	jobNames := make([]string, 0, len(g.tracked))
+	for jobName, tracked := range g.tracked {
+		if tracked.submitted {
+			jobNames = append(jobNames, jobName)
+		}
+	}
+	g.mu.Unlock()
+
+	for _, jobName := range jobNames {
+		job, err := g.TranscriptionJobService.GetTranscriptionJob(ctx, jobName)
+		if err != nil && !errors.Is(err, domainerr.NotFound) {
+			logger.FromContext(ctx).Warn("Failed to get transcription job", "job_name", jobName, "error", err)
+			continue
+		}
+		if err == nil && model.IsActiveTranscriptionJobStatus(job.TranscriptionJobStatus) {
+			continue
+		}
+		g.mu.Lock()
+		delete(g.tracked, jobName)
+		g.mu.Unlock()
+	}
+}
+
+// drain 実行中のジョブの数を Amazon Transcribe と同期し、上限に達するまで待っているジョブを古い順に送信します。
+// 同時実行数の上限が無い場合は、再起動後に待ち行列へ戻したジョブをすべて送信します
+func (g *TranscriptionSubmissionGovernor) drain(ctx context.Context) {
+	if g.maxConcurrentJobs > 0 {
+		active, err := g.TranscriptionJobService.CountActiveTranscriptionJobs(ctx)
+		if err != nil {
+			logger.FromContext(ctx).Warn("Failed to count active transcription jobs", "error", err)
+			return
+		}
+		g.mu.Lock()
+		g.active = active
+		g.mu.Unlock()
+	}
+
+	for {
+		g.mu.Lock()
+		if len(g.queue) == 0 || (g.maxConcurrentJobs > 0 && g.active >= g.maxConcurrentJobs) {
+			g.mu.Unlock()
+			return
+		}
+		submission := g.queue[0]
+		g.queue = g.queue[1:]
+		g.active++
+		g.track(submission.Record)
+		g.mu.Unlock()
+
+		jobCtx := logger.NewContext(ctx, submission.logger)
+		_, _, err := g.start(jobCtx, submission.Job, submission.Record)
+		if err == nil {
+			logger.FromContext(jobCtx).Info("Submitted queued transcription job", "job_name", submission.Job.JobName, "waited_ms", time.Since(submission.EnqueuedAt).Milliseconds())
+			continue
+		}
+
+		g.mu.Lock()
+		g.active--
+		delete(g.tracked, submission.Job.JobName)
+		if errors.Is(err, domainerr.Throttled) {
+			// 先頭に戻して次の同期で再送する
+			g.queue = append([]*queuedSubmission{submission}, g.queue...)
+			g.mu.Unlock()
+			logger.FromContext(jobCtx).Warn("Transcription job submission is throttled", "job_name", submission.Job.JobName, "error", err)
+			return
+		}
+		g.mu.Unlock()
+
+		submission.Record.MarkSubmissionFailed(err.Error())
+		if err := g.Repo.Save(submission.Record); err != nil {
+			logger.FromContext(jobCtx).Error("Failed to save transcription job", "job_name", submission.Job.JobName, "error", err)
+		}
+		logger.FromContext(jobCtx).Error("Failed to submit queued transcription job", "job_name", submission.Job.JobName, "error", err)
+	}
+}
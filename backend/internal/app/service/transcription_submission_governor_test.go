@@ -0,0 +1,363 @@
+package service
+
+import (
+	"cmTranscribe/internal/domain/domainerr"
+	"cmTranscribe/internal/domain/model"
+	"cmTranscribe/internal/infra/persistence"
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// stubTranscriptionJobService ジョブの開始と状態の取得を記録する文字起こしのドメインサービス
+type stubTranscriptionJobService struct {
+	mu       sync.Mutex
+	started  []string
+	statuses map[string]string                        // ジョブ名と状態 (無いジョブは NotFound)
+	startErr error                                    // 開始時に返すエラー
+	listed   []*model.TranscriptionJobSummaryResponse // 一覧で返すジョブ (新しい順)
+	queries  []model.TranscriptionJobListQuery        // 一覧の取得に使われた条件
+}
+
+func newStubTranscriptionJobService() *stubTranscriptionJobService {
+	return &stubTranscriptionJobService{statuses: map[string]string{}}
+}
+
+func (s *stubTranscriptionJobService) StartTranscriptionJob(_ context.Context, input *model.TranscriptionJob) (*model.TranscriptionJobStatusResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.startErr != nil {
+		return nil, s.startErr
+	}
+	s.started = append(s.started, input.JobName)
+	s.statuses[input.JobName] = model.TranscriptionJobStatusQueued
+	return model.NewTranscriptionJobStatusResponse(input.JobName, model.TranscriptionJobStatusQueued), nil
+}
+
+// GetTranscriptionJobList listed のジョブを条件で絞り込み、NextToken を先頭の位置として1ページ分返します
+func (s *stubTranscriptionJobService) GetTranscriptionJobList(_ context.Context, query model.TranscriptionJobListQuery) (*model.TranscriptionJobSummariesResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.queries = append(s.queries, query)
+	var jobs []*model.TranscriptionJobSummaryResponse
+	for _, job := range s.listed {
+		if strings.Contains(job.JobName, query.NameContains) && (query.Status == "" || job.TranscriptionJobStatus == query.Status) {
+			jobs = append(jobs, job)
+		}
+	}
+	offset, _ := strconv.Atoi(query.NextToken)
+	if offset > len(jobs) {
+		offset = len(jobs)
+	}
+	page := &model.TranscriptionJobSummariesResponse{Jobs: jobs[offset:]}
+	if end := offset + query.MaxResults; end < len(jobs) {
+		page.Jobs, page.NextToken = jobs[offset:end], strconv.Itoa(end)
+	}
+	return page, nil
+}
+
+func (s *stubTranscriptionJobService) GetTranscriptionJob(_ context.Context, jobName string) (*model.TranscriptionJobResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	status, ok := s.statuses[jobName]
+	if !ok {
+		return nil, domainerr.New(domainerr.NotFound, "transcription_job_not_found", "transcription job %s does not exist", jobName)
+	}
+	return &model.TranscriptionJobResponse{JobName: jobName, TranscriptionJobStatus: status}, nil
+}
+
+func (s *stubTranscriptionJobService) CountActiveTranscriptionJobs(context.Context) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	active := 0
+	for _, status := range s.statuses {
+		if model.IsActiveTranscriptionJobStatus(status) {
+			active++
+		}
+	}
+	return active, nil
+}
+
+func (s *stubTranscriptionJobService) setStatus(jobName, status string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.statuses[jobName] = status
+}
+
+func (s *stubTranscriptionJobService) startedJobs() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.started...)
+}
+
+func newTestJobRepo(t *testing.T) *persistence.TranscriptionJobRepository {
+	t.Helper()
+	repo, err := persistence.NewTranscriptionJobRepository("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return repo
+}
+
+// tenantContext 実行中のジョブの上限を持つテナントのコンテキストを返します
+func tenantContext(id string, maxActiveJobs int) context.Context {
+	return model.ContextWithTenant(context.Background(), model.NewTenant(id, "", "", "", "", model.TenantQuota{MaxActiveJobs: maxActiveJobs}))
+}
+
+// submit リクエストのハンドラと同じ手順でジョブを送信します
+func submit(ctx context.Context, governor *TranscriptionSubmissionGovernor, jobName string) (*model.TranscriptionJobStatusResponse, int, error) {
+	job := model.NewTranscriptionJob(jobName, "s3://bucket/uploads/"+jobName+".wav", "ja-JP", "")
+	record := model.NewTranscriptionJobDB(jobName, job.MediaFileURI, job.LanguageCode, "")
+	record.TenantID = tenantID(ctx)
+	return governor.Submit(ctx, job, record)
+}
+
+func TestTranscriptionSubmissionGovernorQueuesAboveConcurrency(t *testing.T) {
+	repo := newTestJobRepo(t)
+	jobService := newStubTranscriptionJobService()
+	governor := NewTranscriptionSubmissionGovernor(repo, jobService, 1, 1, time.Second)
+	ctx := context.Background()
+
+	if result, position, err := submit(ctx, governor, "a"); err != nil || position != 0 || result.TranscriptionJobStatus != model.TranscriptionJobStatusQueued {
+		t.Fatalf("Submit(a) = %+v, %d, %v", result, position, err)
+	}
+	result, position, err := submit(ctx, governor, "b")
+	if err != nil || position != 1 || result.TranscriptionJobStatus != model.TranscriptionJobStatusPendingSubmission {
+		t.Fatalf("Submit(b) = %+v, %d, %v", result, position, err)
+	}
+	if got := governor.Position("b"); got != 1 {
+		t.Errorf("Position(b) = %d, want 1", got)
+	}
+	if _, _, err := submit(ctx, governor, "c"); !errors.Is(err, domainerr.Throttled) {
+		t.Errorf("Submit(c) error = %v, want throttled", err)
+	}
+	if _, _, err := governor.Submit(ctx, model.NewTranscriptionJob("b", "s3://bucket/uploads/b.wav", "ja-JP", ""), model.NewTranscriptionJobDB("b", "", "ja-JP", "")); !errors.Is(err, domainerr.Conflict) {
+		t.Errorf("Submit(b) again error = %v, want conflict", err)
+	}
+
+	// 空きができるまで送信しない
+	governor.drain(ctx)
+	if got := jobService.startedJobs(); len(got) != 1 {
+		t.Fatalf("started %v before a slot was free", got)
+	}
+
+	jobService.setStatus("a", model.TranscriptionJobStatusCompleted)
+	governor.drain(ctx)
+	if got := jobService.startedJobs(); len(got) != 2 || got[1] != "b" {
+		t.Fatalf("started = %v, want [a b]", got)
+	}
+	if got := governor.Position("b"); got != 0 {
+		t.Errorf("Position(b) = %d, want 0 after submission", got)
+	}
+	record, err := repo.FindByID("b")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if record.Status != model.TranscriptionJobStatusQueued || record.Submission != nil {
+		t.Errorf("record = %+v, want a submitted record", record)
+	}
+}
+
+func TestTranscriptionSubmissionGovernorEnforcesTenantQuota(t *testing.T) {
+	jobService := newStubTranscriptionJobService()
+	governor := NewTranscriptionSubmissionGovernor(newTestJobRepo(t), jobService, 0, 10, time.Second)
+	ctx := tenantContext("team-a", 2)
+
+	// 同時に開始しても上限を超えない
+	var wg sync.WaitGroup
+	errs := make(chan error, 10)
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			_, _, err := submit(ctx, governor, fmt.Sprintf("team-a.job-%d", i))
+			errs <- err
+		}(i)
+	}
+	wg.Wait()
+	close(errs)
+	accepted := 0
+	for err := range errs {
+		switch {
+		case err == nil:
+			accepted++
+		case !errors.Is(err, domainerr.Throttled):
+			t.Errorf("unexpected error: %v", err)
+		}
+	}
+	if accepted != 2 {
+		t.Fatalf("accepted %d jobs, want 2", accepted)
+	}
+
+	// 他のテナントは上限に数えない
+	if _, _, err := submit(tenantContext("team-b", 1), governor, "team-b.job"); err != nil {
+		t.Fatalf("Submit for another tenant: %v", err)
+	}
+
+	// 終了したジョブは上限から外れる
+	started := jobService.startedJobs()
+	jobService.setStatus(started[0], model.TranscriptionJobStatusCompleted)
+	governor.releaseFinished(ctx)
+	if err := governor.CheckQuota(ctx); err != nil {
+		t.Fatalf("CheckQuota after a job finished: %v", err)
+	}
+	if _, _, err := submit(ctx, governor, "team-a.job-next"); err != nil {
+		t.Fatalf("Submit after a job finished: %v", err)
+	}
+	if err := governor.CheckQuota(ctx); !errors.Is(err, domainerr.Throttled) {
+		t.Errorf("CheckQuota error = %v, want throttled", err)
+	}
+
+	governor.Finished(started[1])
+	if err := governor.CheckQuota(ctx); err != nil {
+		t.Errorf("CheckQuota after Finished: %v", err)
+	}
+}
+
+func TestTranscriptionSubmissionGovernorCountsQueuedJobsInTenantQuota(t *testing.T) {
+	jobService := newStubTranscriptionJobService()
+	governor := NewTranscriptionSubmissionGovernor(newTestJobRepo(t), jobService, 1, 10, time.Second)
+	ctx := tenantContext("team-a", 2)
+
+	if _, _, err := submit(context.Background(), governor, "other"); err != nil {
+		t.Fatal(err)
+	}
+	for _, jobName := range []string{"team-a.1", "team-a.2"} {
+		if _, position, err := submit(ctx, governor, jobName); err != nil || position == 0 {
+			t.Fatalf("Submit(%s) = %d, %v, want a queued job", jobName, position, err)
+		}
+	}
+	if _, _, err := submit(ctx, governor, "team-a.3"); !errors.Is(err, domainerr.Throttled) {
+		t.Errorf("Submit(team-a.3) error = %v, want throttled", err)
+	}
+}
+
+func TestTranscriptionSubmissionGovernorRequeuesThrottledSubmission(t *testing.T) {
+	repo := newTestJobRepo(t)
+	jobService := newStubTranscriptionJobService()
+	jobService.startErr = domainerr.New(domainerr.Throttled, "transcription_throttled", "limit exceeded")
+	governor := NewTranscriptionSubmissionGovernor(repo, jobService, 5, 5, time.Second)
+	ctx := tenantContext("team-a", 1)
+
+	result, position, err := submit(ctx, governor, "team-a.job")
+	if err != nil || position != 1 || result.TranscriptionJobStatus != model.TranscriptionJobStatusPendingSubmission {
+		t.Fatalf("Submit = %+v, %d, %v, want a queued job", result, position, err)
+	}
+	// 待っているジョブもテナントの上限に数える
+	if err := governor.CheckQuota(ctx); !errors.Is(err, domainerr.Throttled) {
+		t.Errorf("CheckQuota error = %v, want throttled", err)
+	}
+
+	jobService.mu.Lock()
+	jobService.startErr = nil
+	jobService.mu.Unlock()
+	governor.drain(ctx)
+	if got := jobService.startedJobs(); len(got) != 1 {
+		t.Fatalf("started = %v, want the requeued job", got)
+	}
+}
+
+func TestTranscriptionSubmissionGovernorMarksFailedSubmission(t *testing.T) {
+	repo := newTestJobRepo(t)
+	jobService := newStubTranscriptionJobService()
+	governor := NewTranscriptionSubmissionGovernor(repo, jobService, 1, 5, time.Second)
+	ctx := tenantContext("team-a", 5)
+
+	jobService.setStatus("running", model.TranscriptionJobStatusInProgress)
+	governor.active = 1
+	if _, _, err := submit(ctx, governor, "team-a.job"); err != nil {
+		t.Fatal(err)
+	}
+	jobService.setStatus("running", model.TranscriptionJobStatusCompleted)
+	jobService.mu.Lock()
+	jobService.startErr = domainerr.New(domainerr.Validation, "transcription_job_rejected", "bad request")
+	jobService.mu.Unlock()
+	governor.drain(ctx)
+
+	record, err := repo.FindByID("team-a.job")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if record.Status != model.TranscriptionJobStatusSubmissionFailed || record.FailureReason == "" {
+		t.Errorf("record = %+v, want SUBMISSION_FAILED", record)
+	}
+	if err := governor.CheckQuota(ctx); err != nil {
+		t.Errorf("failed submission should not count in the quota: %v", err)
+	}
+}
+
+func TestTranscriptionSubmissionGovernorRecover(t *testing.T) {
+	filePath := filepath.Join(t.TempDir(), "jobs.json")
+	repo, err := persistence.NewTranscriptionJobRepository(filePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	jobService := newStubTranscriptionJobService()
+	governor := NewTranscriptionSubmissionGovernor(repo, jobService, 1, 5, time.Second)
+	ctx := tenantContext("team-a", 3)
+
+	for _, jobName := range []string{"team-a.running", "team-a.first", "team-a.second"} {
+		if _, _, err := submit(ctx, governor, jobName); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// 送信する内容を記録する前の形式で残っているジョブ
+	orphan := model.NewTranscriptionJobDB("orphan", "s3://bucket/uploads/orphan.wav", "ja-JP", "")
+	orphan.Status = model.TranscriptionJobStatusPendingSubmission
+	if err := repo.Save(orphan); err != nil {
+		t.Fatal(err)
+	}
+
+	// 再起動後に記録を読み込む
+	repo, err = persistence.NewTranscriptionJobRepository(filePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	jobService = newStubTranscriptionJobService()
+	jobService.setStatus("team-a.running", model.TranscriptionJobStatusInProgress)
+	governor = NewTranscriptionSubmissionGovernor(repo, jobService, 1, 5, time.Second)
+	if err := governor.Recover(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := governor.Position("team-a.first"); got != 1 {
+		t.Errorf("Position(team-a.first) = %d, want 1", got)
+	}
+	if got := governor.Position("team-a.second"); got != 2 {
+		t.Errorf("Position(team-a.second) = %d, want 2", got)
+	}
+	record, err := repo.FindByID("orphan")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if record.Status != model.TranscriptionJobStatusSubmissionFailed {
+		t.Errorf("orphan status = %s, want SUBMISSION_FAILED", record.Status)
+	}
+	// 実行中のジョブと待っているジョブを上限に数え直す
+	if err := governor.CheckQuota(ctx); !errors.Is(err, domainerr.Throttled) {
+		t.Errorf("CheckQuota error = %v, want throttled", err)
+	}
+
+	jobService.setStatus("team-a.running", model.TranscriptionJobStatusCompleted)
+	governor.releaseFinished(ctx)
+	governor.drain(ctx)
+	if got := jobService.startedJobs(); len(got) != 1 || got[0] != "team-a.first" {
+		t.Fatalf("started = %v, want [team-a.first]", got)
+	}
+	submitted, err := repo.FindByID("team-a.first")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if submitted.Status != model.TranscriptionJobStatusQueued || submitted.Submission != nil {
+		t.Errorf("record = %+v, want a submitted record", submitted)
+	}
+	if submitted.TenantID != "team-a" {
+		t.Errorf("record = %+v, want the tenant to be kept", submitted)
+	}
+}
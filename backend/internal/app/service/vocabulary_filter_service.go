@@ -0,0 +1,183 @@
+package service
+
+import (
+	"bufio"
+	"cmTranscribe/internal/app/dto"
+	"cmTranscribe/internal/domain/domainerr"
+	"cmTranscribe/internal/domain/model"
+	"cmTranscribe/internal/domain/service"
+	"cmTranscribe/internal/shared/logger"
+	"cmTranscribe/internal/shared/validator"
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// VocabularyFilterService 語彙フィルタに関するアプリケーション層のサービス
+type VocabularyFilterService struct {
+	VocabularyFilterService service.VocabularyFilterService
+	FileService             service.FileService
+	S3StorageService        service.S3StorageService
+}
+
+// NewVocabularyFilterService 新しい VocabularyFilterService を作成します
+func NewVocabularyFilterService(
+	vocabularyFilterService service.VocabularyFilterService,
+	fileService service.FileService,
+	s3StorageService service.S3StorageService,
+) *VocabularyFilterService {
+	return &VocabularyFilterService{
+		VocabularyFilterService: vocabularyFilterService,
+		FileService:             fileService,
+		S3StorageService:        s3StorageService,
+	}
+}
+
+// CreateVocabularyFilter 単語リストをS3にアップロードして語彙フィルタを作成します。名前には呼び出し元のテナントの接頭辞を付けます。
+// 使われない単語リストを残さないよう、リクエストの内容はアップロードの前に検証します
+func (s *VocabularyFilterService) CreateVocabularyFilter(ctx context.Context, request dto.CreateVocabularyFilterDto) error {
+	if request.LanguageCode == "" {
+		return domainerr.New(domainerr.Validation, "language_code_required", "language_code is required")
+	}
+	if err := validateWords(request.Words); err != nil {
+		return err
+	}
+
+	name := resourceName(ctx, request.FilterName)
+	s3Uri, err := s.uploadWordListFile(ctx, name, request.Words)
+	if err != nil {
+		return fmt.Errorf("failed to create vocabulary filter: %w", err)
+	}
+
+	filter := model.NewVocabularyFilter(name, request.LanguageCode, s3Uri)
+	if err := validator.Validate(filter); err != nil {
+		return fmt.Errorf("error processing vocabularyFilter: %w", err)
+	}
+
+	if err := s.VocabularyFilterService.CreateVocabularyFilter(ctx, *filter); err != nil {
+		return fmt.Errorf("failed to create vocabulary filter: %w", err)
+	}
+	return nil
+}
+
+// UpdateVocabularyFilter 単語リストをS3にアップロードして語彙フィルタを更新します
+func (s *VocabularyFilterService) UpdateVocabularyFilter(ctx context.Context, request dto.UpdateVocabularyFilterDto) error {
+	if err := validateWords(request.Words); err != nil {
+		return err
+	}
+
+	name := resourceName(ctx, request.FilterName)
+	s3Uri, err := s.uploadWordListFile(ctx, name, request.Words)
+	if err != nil {
+		return fmt.Errorf("failed to update vocabulary filter: %w", err)
+	}
+
+	filter := model.NewVocabularyFilter(name, "", s3Uri)
+	if err := validator.Validate(filter); err != nil {
+		return fmt.Errorf("error processing vocabularyFilter: %w", err)
+	}
+
+	if err := s.VocabularyFilterService.UpdateVocabularyFilter(ctx, *filter); err != nil {
+		return fmt.Errorf("failed to update vocabulary filter: %w", err)
+	}
+	return nil
+}
+
+// validateWords 単語リストに空でない単語が含まれていることを確認します
+func validateWords(words []string) error {
+	if len(model.ConvertWordsToContent(words)) == 0 {
+		return domainerr.New(domainerr.Validation, "words_required", "words are required")
+	}
+	return nil
+}
+
+// GetVocabularyFilter 名前で語彙フィルタを取得し、単語リストを含めて返します
+func (s *VocabularyFilterService) GetVocabularyFilter(ctx context.Context, name string) (*dto.VocabularyFilterResponse, error) {
+	filter, err := s.VocabularyFilterService.GetVocabularyFilter(ctx, resourceName(ctx, name))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get vocabulary filter: %w", err)
+	}
+
+	words, err := s.downloadWordList(ctx, filter.DownloadUri)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download word list: %w", err)
+	}
+
+	return &dto.VocabularyFilterResponse{
+		FilterName:       displayName(ctx, filter.FilterName),
+		LanguageCode:     filter.LanguageCode,
+		Words:            words,
+		LastModifiedTime: filter.LastModifiedTime,
+	}, nil
+}
+
+// ListVocabularyFilters 呼び出し元のテナントの語彙フィルタの一覧を返します
+func (s *VocabularyFilterService) ListVocabularyFilters(ctx context.Context) (*dto.VocabularyFiltersResponseDto, error) {
+	filters, err := s.VocabularyFilterService.ListVocabularyFilters(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list vocabulary filters: %w", err)
+	}
+
+	response := &dto.VocabularyFiltersResponseDto{
+		Filters: make([]dto.VocabularyFilterResponse, 0, len(filters)),
+	}
+	for _, filter := range filters {
+		if !ownsResource(ctx, filter.FilterName) {
+			continue
+		}
+		response.Filters = append(response.Filters, dto.VocabularyFilterResponse{
+			FilterName:       displayName(ctx, filter.FilterName),
+			LanguageCode:     filter.LanguageCode,
+			LastModifiedTime: filter.LastModifiedTime,
+		})
+	}
+	return response, nil
+}
+
+// DeleteVocabularyFilter 語彙フィルタを削除します
+func (s *VocabularyFilterService) DeleteVocabularyFilter(ctx context.Context, name string) error {
+	if err := s.VocabularyFilterService.DeleteVocabularyFilter(ctx, resourceName(ctx, name)); err != nil {
+		return fmt.Errorf("failed to delete vocabulary filter: %w", err)
+	}
+	return nil
+}
+
+// uploadWordListFile 単語リストから1行1単語のファイルを作成してS3にアップロードし、そのS3 URIを返します
+func (s *VocabularyFilterService) uploadWordListFile(ctx context.Context, name string, words []string) (string, error) {
+	// ドメインモデルを作成
+	tsvFile := model.NewTSVFile(name, model.ConvertWordsToContent(words))
+	return uploadTSVFile(ctx, s.FileService, s.S3StorageService, tsvFile, vocabularyFilterPrefix(ctx))
+}
+
+// downloadWordList 単語リストをダウンロードして1行1単語として読み込みます
+func (s *VocabularyFilterService) downloadWordList(ctx context.Context, uri string) ([]string, error) {
+	if uri == "" {
+		return nil, nil
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, uri, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download file: %w", err)
+	}
+	defer func() {
+		if err := resp.Body.Close(); err != nil {
+			logger.FromContext(ctx).Warn("Failed to close response body", "error", err)
+		}
+	}()
+
+	var words []string
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		if word := strings.TrimSpace(scanner.Text()); word != "" {
+			words = append(words, word)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read word list: %w", err)
+	}
+	return words, nil
+}
@@ -0,0 +1,164 @@
+package service
+
+import (
+	"cmTranscribe/internal/app/dto"
+	"cmTranscribe/internal/domain/model"
+	"cmTranscribe/internal/domain/repository"
+	"cmTranscribe/internal/domain/service"
+	"cmTranscribe/internal/infra/config"
+	"cmTranscribe/internal/shared/logger"
+	"container/list"
+	"context"
+	"sync"
+	"time"
+)
+
+// VocabularyReportService ボキャブラリの効果を集計するサービス
+type VocabularyReportService struct {
+	JobRepo                 repository.TranscriptionJobRepository
+	TranscriptionJobService service.TranscriptionJobService
+	TranscriptLoader        *TranscriptLoader
+	CustomVocabularyService *CustomVocabularyService
+
+	// 完了済みのジョブが使用したボキャブラリ (完了後は変わらないため、新しいものから一定数だけ保持する)
+	mu              sync.Mutex
+	jobVocabularies map[jobVocabularyKey]*list.Element
+	order           *list.List // 最近使った順 (先頭が最新) の *cachedJobVocabulary
+}
+
+// jobVocabularyCacheSize 保持するジョブのボキャブラリの数 (複数のテナントが走査する maxScannedJobs 件を保持できる数)
+const jobVocabularyCacheSize = 5000
+
+// jobVocabularyKey ジョブを識別するキー。削除されたジョブと同じ名前で作り直されたジョブを区別するため、作成日時を含めます
+type jobVocabularyKey struct {
+	jobName   string
+	createdAt time.Time
+}
+
+// cachedJobVocabulary 保持しているジョブのボキャブラリ
+type cachedJobVocabulary struct {
+	key            jobVocabularyKey
+	vocabularyName string
+}
+
+// NewVocabularyReportService 新しい VocabularyReportService を作成します
+func NewVocabularyReportService(
+	jobRepo repository.TranscriptionJobRepository,
+	jobService service.TranscriptionJobService,
+	transcriptLoader *TranscriptLoader,
+	customVocabularyService *CustomVocabularyService,
+) *VocabularyReportService {
+	return &VocabularyReportService{
+		JobRepo:                 jobRepo,
+		TranscriptionJobService: jobService,
+		TranscriptLoader:        transcriptLoader,
+		CustomVocabularyService: customVocabularyService,
+		jobVocabularies:         map[jobVocabularyKey]*list.Element{},
+		order:                   list.New(),
+	}
+}
+
+// GetEffectivenessReport ボキャブラリを使用した、呼び出し元のテナントの呼び出し元が参照できる完了済みのジョブをすべて走査し、
+// 語彙ごとの出現回数と信頼度、一度も出現しなかった語彙を返します
+func (s *VocabularyReportService) GetEffectivenessReport(ctx context.Context, vocabularyName string) (*dto.VocabularyReportResponseDto, error) {
+	vocabulary, err := s.CustomVocabularyService.GetCustomVocabularyByName(ctx, vocabularyName)
+	if err != nil {
+		return nil, err
+	}
+	analyzer := model.NewEffectivenessAnalyzer(vocabulary.LanguageCode, model.NewVocabularyEntries(vocabulary.Vocabularies))
+
+	jobs, err := recentCompletedJobs(ctx, s.TranscriptionJobService)
+	if err != nil {
+		return nil, err
+	}
+	var candidates []*model.TranscriptionJobSummaryResponse
+	for _, summary := range jobs {
+		if summary.LanguageCode == vocabulary.LanguageCode && canAccessJob(ctx, s.JobRepo, summary.JobName) {
+			candidates = append(candidates, summary)
+		}
+	}
+
+	// 一覧にはジョブの設定が含まれないため、ジョブごとに使用したボキャブラリを並行して確認する
+	used := make([]bool, len(candidates))
+	forEachConcurrently(ctx, config.AppConfig.TranscriptFetchWorkers, len(candidates), func(i int) {
+		name, err := s.jobVocabulary(ctx, candidates[i])
+		if err != nil {
+			logger.FromContext(ctx).Warn("Skipping transcription job", "job_name", candidates[i].JobName, "error", err)
+			return
+		}
+		used[i] = name == resourceName(ctx, vocabularyName)
+	})
+	var jobNames []string
+	for i, candidate := range candidates {
+		if used[i] {
+			jobNames = append(jobNames, candidate.JobName)
+		}
+	}
+
+	scanned := 0
+	for _, loaded := range s.TranscriptLoader.LoadAll(ctx, jobNames) {
+		if loaded.Err != nil {
+			logger.FromContext(ctx).Warn("Skipping transcript", "job_name", loaded.JobName, "error", loaded.Err)
+			continue
+		}
+		analyzer.AddTranscript(loaded.JobName, loaded.Transcript)
+		scanned++
+	}
+
+	response := &dto.VocabularyReportResponseDto{
+		VocabularyName: vocabularyName,
+		LanguageCode:   vocabulary.LanguageCode,
+		ScannedJobs:    scanned,
+		Entries:        []dto.VocabularyEntryReportDto{},
+		NeverMatched:   []string{},
+	}
+	for _, result := range analyzer.Results() {
+		response.Entries = append(response.Entries, dto.VocabularyEntryReportDto{
+			Phrase:            result.Entry.Phrase,
+			DisplayAs:         result.Entry.DisplayAs,
+			Matches:           result.Matches,
+			AverageConfidence: result.AverageConfidence(),
+			JobCount:          len(result.Jobs),
+		})
+	}
+	for _, result := range analyzer.NeverMatched() {
+		response.NeverMatched = append(response.NeverMatched, result.Entry.Phrase)
+	}
+	return response, nil
+}
+
+// jobVocabulary 完了済みのジョブが使用したボキャブラリの名前を返します (使用していない場合は空)
+func (s *VocabularyReportService) jobVocabulary(ctx context.Context, summary *model.TranscriptionJobSummaryResponse) (string, error) {
+	key := jobVocabularyKey{jobName: summary.JobName, createdAt: summary.CreationTime}
+	s.mu.Lock()
+	if element, ok := s.jobVocabularies[key]; ok {
+		s.order.MoveToFront(element)
+		s.mu.Unlock()
+		return element.Value.(*cachedJobVocabulary).vocabularyName, nil
+	}
+	s.mu.Unlock()
+
+	job, err := s.TranscriptionJobService.GetTranscriptionJob(ctx, summary.JobName)
+	if err != nil {
+		return "", err
+	}
+	s.storeJobVocabulary(key, job.VocabularyName)
+	return job.VocabularyName, nil
+}
+
+// storeJobVocabulary ジョブのボキャブラリを保持し、上限を超えた分を古いものから削除します
+func (s *VocabularyReportService) storeJobVocabulary(key jobVocabularyKey, vocabularyName string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if element, ok := s.jobVocabularies[key]; ok {
+		s.order.MoveToFront(element)
+		return
+	}
+	s.jobVocabularies[key] = s.order.PushFront(&cachedJobVocabulary{key: key, vocabularyName: vocabularyName})
+	for s.order.Len() > jobVocabularyCacheSize {
+		oldest := s.order.Back()
+		s.order.Remove(oldest)
+		delete(s.jobVocabularies, oldest.Value.(*cachedJobVocabulary).key)
+	}
+}
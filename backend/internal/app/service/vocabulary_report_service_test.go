@@ -0,0 +1,34 @@
+package service
+
+import (
+	"testing"
+	"time"
+)
+
+func TestVocabularyReportServiceStoreJobVocabulary(t *testing.T) {
+	s := NewVocabularyReportService(nil, nil, nil, nil)
+	created := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	first := jobVocabularyKey{jobName: "job-0", createdAt: created}
+	s.storeJobVocabulary(first, "vocab-a")
+
+	// 同じ名前で作り直されたジョブは別のエントリとして扱います
+	recreated := jobVocabularyKey{jobName: "job-0", createdAt: created.Add(time.Hour)}
+	s.storeJobVocabulary(recreated, "vocab-b")
+	if got := s.jobVocabularies[first].Value.(*cachedJobVocabulary).vocabularyName; got != "vocab-a" {
+		t.Errorf("first vocabulary = %q, want vocab-a", got)
+	}
+	if got := s.jobVocabularies[recreated].Value.(*cachedJobVocabulary).vocabularyName; got != "vocab-b" {
+		t.Errorf("recreated vocabulary = %q, want vocab-b", got)
+	}
+
+	// 上限を超えた分は古いものから削除します
+	for i := 0; i < jobVocabularyCacheSize; i++ {
+		s.storeJobVocabulary(jobVocabularyKey{jobName: "job-new", createdAt: created.Add(time.Duration(i) * time.Second)}, "")
+	}
+	if s.order.Len() != jobVocabularyCacheSize || len(s.jobVocabularies) != jobVocabularyCacheSize {
+		t.Errorf("cache size = %d, %d, want %d", s.order.Len(), len(s.jobVocabularies), jobVocabularyCacheSize)
+	}
+	if _, ok := s.jobVocabularies[first]; ok {
+		t.Error("the oldest entry should be evicted")
+	}
+}
@@ -0,0 +1,110 @@
+package service
+
+import (
+	"cmTranscribe/internal/domain/domainerr"
+	"cmTranscribe/internal/domain/model"
+	"cmTranscribe/internal/domain/repository"
+	"cmTranscribe/internal/domain/service"
+	"cmTranscribe/internal/shared/logger"
+	"context"
+	"errors"
+	"time"
+)
+
+// VocabularyStateSyncer READYまたはFAILEDになるまで、カスタムボキャブラリの状態をバックグラウンドで同期します
+type VocabularyStateSyncer struct {
+	Repo                    repository.CustomVocabularyRepository
+	CustomVocabularyService service.CustomVocabularyService
+	interval                time.Duration
+	trigger                 chan struct{}
+}
+
+// NewVocabularyStateSyncer 新しい VocabularyStateSyncer を作成します
+func NewVocabularyStateSyncer(
+	repo repository.CustomVocabularyRepository,
+	customVocabularyService service.CustomVocabularyService,
+	interval time.Duration,
+) *VocabularyStateSyncer {
+	return &VocabularyStateSyncer{
+		Repo:                    repo,
+		CustomVocabularyService: customVocabularyService,
+		interval:                interval,
+		trigger:                 make(chan struct{}, 1),
+	}
+}
+
+// Run ctxがキャンセルされるまで、一定間隔で同期を行います
+func (s *VocabularyStateSyncer) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-s.trigger:
+		}
+		s.syncPending(ctx)
+	}
+}
+
+// Notify 作成・更新の直後に次の同期を待たずに状態を確認させます
+func (s *VocabularyStateSyncer) Notify() {
+	select {
+	case s.trigger <- struct{}{}:
+	default:
+	}
+}
+
+// syncPending 状態が確定していないボキャブラリをAmazon Transcribeと同期します。
+// 状態を取得している間に更新を依頼されたボキャブラリには、古い状態を反映しません
+func (s *VocabularyStateSyncer) syncPending(ctx context.Context) {
+	vocabularies, err := s.Repo.FindAll()
+	if err != nil {
+		logger.FromContext(ctx).Error("Failed to list custom vocabularies for sync", "error", err)
+		return
+	}
+
+	for _, vocabulary := range vocabularies {
+		if vocabulary.IsTerminal() {
+			continue
+		}
+		current, err := s.CustomVocabularyService.GetCustomVocabularyByName(ctx, vocabulary.Name)
+		if errors.Is(err, domainerr.NotFound) {
+			s.forget(ctx, vocabulary)
+			continue
+		}
+		if err != nil {
+			logger.FromContext(ctx).Warn("Failed to sync custom vocabulary", "vocabulary_name", vocabulary.Name, "error", err)
+			continue
+		}
+		applied, err := s.Repo.ApplyState(vocabulary.Name, vocabulary.UpdatedAt, current.VocabularyState, current.FailureReason)
+		if err != nil {
+			logger.FromContext(ctx).Warn("Failed to save custom vocabulary", "vocabulary_name", vocabulary.Name, "error", err)
+			continue
+		}
+		if !applied {
+			logger.FromContext(ctx).Debug("Custom vocabulary was resubmitted during sync", "vocabulary_name", vocabulary.Name)
+			continue
+		}
+		vocabulary.ApplyState(current.VocabularyState, current.FailureReason)
+		if vocabulary.IsTerminal() {
+			logger.FromContext(ctx).Info("Custom vocabulary is settled", "vocabulary_name", vocabulary.Name, "state", vocabulary.State, "failure_reason", vocabulary.FailureReason)
+		}
+	}
+}
+
+// forget Amazon Transcribe に存在しなくなったボキャブラリ (コンソールや別のホストで削除されたもの) の記録を削除し、
+// 以降の同期で取得し続けないようにします。取得している間に更新を依頼されたボキャブラリは削除しません
+func (s *VocabularyStateSyncer) forget(ctx context.Context, vocabulary *model.CustomVocabularyDB) {
+	latest, err := s.Repo.FindByName(vocabulary.Name)
+	if err != nil || !latest.UpdatedAt.Equal(vocabulary.UpdatedAt) {
+		return
+	}
+	if err := s.Repo.Delete(vocabulary.Name); err != nil {
+		logger.FromContext(ctx).Warn("Failed to delete custom vocabulary", "vocabulary_name", vocabulary.Name, "error", err)
+		return
+	}
+	logger.FromContext(ctx).Info("Custom vocabulary no longer exists and was removed", "vocabulary_name", vocabulary.Name)
+}
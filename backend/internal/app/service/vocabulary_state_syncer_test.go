@@ -0,0 +1,207 @@
+package service
+
+import (
+	"cmTranscribe/internal/domain/domainerr"
+	"cmTranscribe/internal/domain/model"
+	"cmTranscribe/internal/infra/persistence"
+	"context"
+	"sync"
+	"testing"
+	"time"
+)
+
+// stubVocabularyService 状態の取得だけを行うカスタムボキャブラリのドメインサービス
+type stubVocabularyService struct {
+	mu    sync.Mutex
+	state string
+	onGet func(name string) // 状態を返す前に呼ぶ関数 (同期中の更新を再現する)
+	err   error             // 状態の代わりに返すエラー
+}
+
+func (s *stubVocabularyService) CreateCustomVocabulary(context.Context, model.CustomVocabulary) error {
+	return nil
+}
+
+func (s *stubVocabularyService) UpdateCustomVocabulary(context.Context, model.CustomVocabulary) error {
+	return nil
+}
+
+func (s *stubVocabularyService) GetCustomVocabularyByName(_ context.Context, name string) (*model.CustomVocabularyResponse, error) {
+	s.mu.Lock()
+	state, onGet, err := s.state, s.onGet, s.err
+	s.mu.Unlock()
+	if onGet != nil {
+		onGet(name)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return model.NewCustomVocabularyResponse(name, "ja-JP", "", state, time.Now()), nil
+}
+
+func (s *stubVocabularyService) ListCustomVocabularies(context.Context) ([]*model.CustomVocabularyResponse, error) {
+	return nil, nil
+}
+
+func (s *stubVocabularyService) DeleteCustomVocabulary(context.Context, string) error {
+	return nil
+}
+
+func newTestVocabularyRepo(t *testing.T, names ...string) *persistence.CustomVocabularyRepository {
+	t.Helper()
+	repo, err := persistence.NewCustomVocabularyRepository()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range names {
+		if err := repo.Save(model.NewCustomVocabularyDB(name, "ja-JP", "s3://bucket/"+name+".tsv", "")); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return repo
+}
+
+// resubmit リクエストのハンドラと同じ手順で更新を依頼します
+func resubmit(t *testing.T, repo *persistence.CustomVocabularyRepository, name string) {
+	record, err := repo.FindByName(name)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	record.Resubmit("ja-JP", "s3://bucket/"+name+"-new.tsv")
+	if err := repo.Save(record); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestVocabularyStateSyncerAppliesState(t *testing.T) {
+	repo := newTestVocabularyRepo(t, "a", "b")
+	syncer := NewVocabularyStateSyncer(repo, &stubVocabularyService{state: model.VocabularyStateReady}, time.Second)
+
+	syncer.syncPending(context.Background())
+
+	for _, name := range []string{"a", "b"} {
+		record, err := repo.FindByName(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if record.State != model.VocabularyStateReady {
+			t.Errorf("state of %s = %s, want %s", name, record.State, model.VocabularyStateReady)
+		}
+		if record.SyncedAt.IsZero() {
+			t.Errorf("SyncedAt of %s is not set", name)
+		}
+	}
+}
+
+func TestVocabularyStateSyncerKeepsResubmittedVocabularyPending(t *testing.T) {
+	repo := newTestVocabularyRepo(t, "a")
+	// 状態を取得している間に更新を依頼された場合、取得した (古い) READY を反映しない
+	stub := &stubVocabularyService{state: model.VocabularyStateReady}
+	stub.onGet = func(name string) { resubmit(t, repo, name) }
+	syncer := NewVocabularyStateSyncer(repo, stub, time.Second)
+
+	syncer.syncPending(context.Background())
+
+	record, err := repo.FindByName("a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if record.State != model.VocabularyStatePending {
+		t.Errorf("state = %s, want %s", record.State, model.VocabularyStatePending)
+	}
+	if record.FileUri != "s3://bucket/a-new.tsv" {
+		t.Errorf("FileUri = %s, want the resubmitted file", record.FileUri)
+	}
+
+	// 次の同期では反映する
+	stub.mu.Lock()
+	stub.onGet = nil
+	stub.mu.Unlock()
+	syncer.syncPending(context.Background())
+	if record, _ = repo.FindByName("a"); record.State != model.VocabularyStateReady {
+		t.Errorf("state after the next sync = %s, want %s", record.State, model.VocabularyStateReady)
+	}
+}
+
+func TestVocabularyStateSyncerConcurrentResubmit(t *testing.T) {
+	names := []string{"a", "b", "c"}
+	repo := newTestVocabularyRepo(t, names...)
+	syncer := NewVocabularyStateSyncer(repo, &stubVocabularyService{state: model.VocabularyStateReady}, time.Millisecond)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		syncer.Run(ctx)
+	}()
+
+	// go test -race で、同期とリクエストの更新が同じレコードを共有していないことを確認する
+	var wg sync.WaitGroup
+	for _, name := range names {
+		wg.Add(1)
+		go func(name string) {
+			defer wg.Done()
+			for i := 0; i < 100; i++ {
+				resubmit(t, repo, name)
+				syncer.Notify()
+			}
+		}(name)
+	}
+	wg.Wait()
+	cancel()
+	<-done
+
+	syncer.syncPending(context.Background())
+	for _, name := range names {
+		record, err := repo.FindByName(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if record.State != model.VocabularyStateReady {
+			t.Errorf("state of %s = %s, want %s", name, record.State, model.VocabularyStateReady)
+		}
+	}
+}
+
+func TestVocabularyStateSyncerForgetsDeletedVocabulary(t *testing.T) {
+	repo := newTestVocabularyRepo(t, "a")
+	stub := &stubVocabularyService{err: domainerr.New(domainerr.NotFound, "custom_vocabulary_not_found", "custom vocabulary a does not exist")}
+	syncer := NewVocabularyStateSyncer(repo, stub, time.Second)
+
+	syncer.syncPending(context.Background())
+
+	if _, err := repo.FindByName("a"); err == nil {
+		t.Error("a vocabulary deleted outside the service should be removed")
+	}
+}
+
+func TestVocabularyStateSyncerKeepsVocabularyOnOtherErrors(t *testing.T) {
+	repo := newTestVocabularyRepo(t, "a")
+	stub := &stubVocabularyService{err: domainerr.New(domainerr.Upstream, "transcribe_error", "service unavailable")}
+	syncer := NewVocabularyStateSyncer(repo, stub, time.Second)
+
+	syncer.syncPending(context.Background())
+
+	record, err := repo.FindByName("a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if record.State != model.VocabularyStatePending {
+		t.Errorf("state = %s, want %s", record.State, model.VocabularyStatePending)
+	}
+}
+
+func TestVocabularyStateSyncerKeepsVocabularyResubmittedWhileNotFound(t *testing.T) {
+	repo := newTestVocabularyRepo(t, "a")
+	// 取得している間に作り直された場合は、NotFound でも記録を残す
+	stub := &stubVocabularyService{err: domainerr.New(domainerr.NotFound, "custom_vocabulary_not_found", "custom vocabulary a does not exist")}
+	stub.onGet = func(name string) { resubmit(t, repo, name) }
+	syncer := NewVocabularyStateSyncer(repo, stub, time.Second)
+
+	syncer.syncPending(context.Background())
+
+	if _, err := repo.FindByName("a"); err != nil {
+		t.Errorf("a resubmitted vocabulary should be kept: %v", err)
+	}
+}
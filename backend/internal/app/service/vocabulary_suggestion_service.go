@@ -0,0 +1,192 @@
+package service
+
+import (
+	"cmTranscribe/internal/app/dto"
+	"cmTranscribe/internal/domain/domainerr"
+	"cmTranscribe/internal/domain/model"
+	"cmTranscribe/internal/domain/repository"
+	"cmTranscribe/internal/domain/service"
+	"cmTranscribe/internal/shared/logger"
+	"cmTranscribe/internal/shared/validator"
+	"context"
+	"fmt"
+	"sort"
+)
+
+const (
+	defaultSuggestionMaxConfidence = 0.7
+	defaultSuggestionLimit         = 50
+)
+
+// VocabularySuggestionService 文字起こし結果からボキャブラリへの追加候補を提案するサービス
+type VocabularySuggestionService struct {
+	CorrectionRepo          repository.TranscriptCorrectionRepository
+	JobRepo                 repository.TranscriptionJobRepository
+	TranscriptionJobService service.TranscriptionJobService
+	TranscriptLoader        *TranscriptLoader
+	CustomVocabularyService *CustomVocabularyService
+}
+
+// NewVocabularySuggestionService 新しい VocabularySuggestionService を作成します
+func NewVocabularySuggestionService(
+	correctionRepo repository.TranscriptCorrectionRepository,
+	jobRepo repository.TranscriptionJobRepository,
+	jobService service.TranscriptionJobService,
+	transcriptLoader *TranscriptLoader,
+	customVocabularyService *CustomVocabularyService,
+) *VocabularySuggestionService {
+	return &VocabularySuggestionService{
+		CorrectionRepo:          correctionRepo,
+		JobRepo:                 jobRepo,
+		TranscriptionJobService: jobService,
+		TranscriptLoader:        transcriptLoader,
+		CustomVocabularyService: customVocabularyService,
+	}
+}
+
+// RecordCorrection レビュアーによる文字起こし結果の修正を記録します
+func (s *VocabularySuggestionService) RecordCorrection(ctx context.Context, request dto.TranscriptCorrectionDto) error {
+	correction := model.NewTranscriptCorrection(resourceName(ctx, request.JobName), request.Original, request.Corrected, request.Reviewer)
+	if err := validator.Validate(correction); err != nil {
+		return err
+	}
+	if err := checkJobAccess(ctx, s.JobRepo, correction.JobName); err != nil {
+		return err
+	}
+	if err := s.CorrectionRepo.Save(correction); err != nil {
+		return fmt.Errorf("failed to record transcript correction: %w", err)
+	}
+	return nil
+}
+
+// GetSuggestions 完了済みの文字起こし結果の低信頼度の単語と、レビュアーが修正した固有名詞から候補を作成し、ランキングして返します。
+// vocabularyNameを指定した場合、そのボキャブラリの言語を対象とし、既に登録済みの語彙は除外します。
+func (s *VocabularySuggestionService) GetSuggestions(ctx context.Context, languageCode, vocabularyName string, maxConfidence float64, limit int) (*dto.VocabularySuggestionsResponseDto, error) {
+	if maxConfidence <= 0 || maxConfidence > 1 {
+		maxConfidence = defaultSuggestionMaxConfidence
+	}
+	if limit <= 0 {
+		limit = defaultSuggestionLimit
+	}
+
+	existing := map[string]bool{}
+	if vocabularyName != "" {
+		vocabulary, err := s.CustomVocabularyService.GetCustomVocabularyByName(ctx, vocabularyName)
+		if err != nil {
+			return nil, err
+		}
+		if languageCode == "" {
+			languageCode = vocabulary.LanguageCode
+		}
+		for _, entry := range vocabulary.Vocabularies {
+			existing[entry.Phrase] = true
+			existing[model.NormalizePhrase(entry.DisplayAs)] = true
+		}
+	}
+	if languageCode == "" {
+		return nil, domainerr.New(domainerr.Validation, "language_code_required", "language_code or name is required")
+	}
+
+	// 呼び出し元のテナントの、呼び出し元が参照できる対象言語の完了済みジョブを取得
+	jobs, err := recentCompletedJobs(ctx, s.TranscriptionJobService)
+	if err != nil {
+		return nil, err
+	}
+	var jobNames []string
+	for _, job := range jobs {
+		if job.LanguageCode == languageCode && canAccessJob(ctx, s.JobRepo, job.JobName) {
+			jobNames = append(jobNames, job.JobName)
+		}
+	}
+	collector := model.NewSuggestionCollector(languageCode, maxConfidence)
+	scanned := map[string]bool{}
+	for _, loaded := range s.TranscriptLoader.LoadAll(ctx, jobNames) {
+		if loaded.Err != nil {
+			logger.FromContext(ctx).Warn("Skipping transcript", "job_name", loaded.JobName, "error", loaded.Err)
+			continue
+		}
+		collector.AddTranscript(loaded.JobName, loaded.Transcript)
+		scanned[loaded.JobName] = true
+	}
+
+	// 対象ジョブに対する修正を加える
+	corrections, err := s.CorrectionRepo.FindAll()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get transcript corrections: %w", err)
+	}
+	for _, correction := range corrections {
+		if scanned[correction.JobName] {
+			collector.AddCorrection(correction)
+		}
+	}
+
+	response := &dto.VocabularySuggestionsResponseDto{
+		LanguageCode: languageCode,
+		ScannedJobs:  len(scanned),
+		Suggestions:  []dto.VocabularySuggestionDto{},
+	}
+	for _, suggestion := range collector.Ranked(existing, limit) {
+		response.Suggestions = append(response.Suggestions, dto.VocabularySuggestionDto{
+			Phrase:            suggestion.Phrase,
+			DisplayAs:         suggestion.DisplayAs,
+			Occurrences:       suggestion.Occurrences,
+			AverageConfidence: suggestion.AverageConfidence(),
+			Corrections:       suggestion.Corrections,
+			JobCount:          len(suggestion.Jobs),
+			Sources:           sortedKeys(suggestion.Sources),
+			MisrecognizedAs:   suggestion.MisrecognizedAs,
+			Score:             suggestion.Score(),
+		})
+	}
+	return response, nil
+}
+
+// AcceptSuggestions 採用した候補のうち未登録のものを、部分更新でボキャブラリに追加します。追加した件数を返します
+func (s *VocabularySuggestionService) AcceptSuggestions(ctx context.Context, request dto.AcceptSuggestionsDto) (int, error) {
+	vocabulary, err := s.CustomVocabularyService.GetCustomVocabularyByName(ctx, request.VocabularyName)
+	if err != nil {
+		return 0, err
+	}
+	existing := make(map[string]bool, len(vocabulary.Vocabularies))
+	for _, entry := range vocabulary.Vocabularies {
+		existing[entry.Phrase] = true
+	}
+
+	var operations []dto.VocabularyOperation
+	for i := range request.Vocabularies {
+		entry := request.Vocabularies[i]
+		if entry.Phrase == "" || existing[entry.Phrase] {
+			continue
+		}
+		existing[entry.Phrase] = true
+		operations = append(operations, dto.VocabularyOperation{
+			Op:         model.VocabularyOpAdd,
+			Phrase:     entry.Phrase,
+			Vocabulary: &entry,
+		})
+	}
+	if len(operations) == 0 {
+		return 0, nil
+	}
+
+	_, err = s.CustomVocabularyService.PatchCustomVocabulary(ctx, dto.PatchVocabularyDto{
+		VocabularyName: request.VocabularyName,
+		LanguageCode:   vocabulary.LanguageCode,
+		Operations:     operations,
+		Author:         request.Author,
+	})
+	if err != nil {
+		return 0, err
+	}
+	return len(operations), nil
+}
+
+// sortedKeys mapのキーをソートして返します
+func sortedKeys(m map[string]bool) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,84 @@
+package service
+
+import (
+	"cmTranscribe/internal/app/dto"
+	"cmTranscribe/internal/domain/model"
+	"cmTranscribe/internal/shared/validator"
+	"context"
+	"fmt"
+)
+
+// VocabularySyncService 定義ファイルで宣言されたボキャブラリをAmazon Transcribeに同期するサービス
+type VocabularySyncService struct {
+	CustomVocabularyService *CustomVocabularyService
+}
+
+// NewVocabularySyncService 新しい VocabularySyncService を作成します
+func NewVocabularySyncService(customVocabularyService *CustomVocabularyService) *VocabularySyncService {
+	return &VocabularySyncService{
+		CustomVocabularyService: customVocabularyService,
+	}
+}
+
+// Plan 定義とAmazon Transcribeに登録されているボキャブラリを比較し、作成・更新・削除の計画を作成します。
+// 対象はコンテキストのテナントのボキャブラリです。pruneがtrueの場合、managedPrefixで始まる名前のうち定義の無いボキャブラリを削除の対象にします
+func (s *VocabularySyncService) Plan(ctx context.Context, definitions []*model.VocabularyDefinition, prune bool, managedPrefix string) (*model.VocabularySyncPlan, error) {
+	defined := make(map[string]bool, len(definitions))
+	for _, definition := range definitions {
+		if err := validator.Validate(definition); err != nil {
+			return nil, err
+		}
+		defined[definition.Name] = true
+	}
+
+	summaries, err := s.CustomVocabularyService.ListCustomVocabularies(ctx)
+	if err != nil {
+		return nil, err
+	}
+	remotes := make([]*model.RemoteVocabulary, 0, len(summaries))
+	for _, summary := range summaries {
+		remote := &model.RemoteVocabulary{Name: summary.VocabularyName, LanguageCode: summary.LanguageCode}
+		// 差分の計算には定義のあるボキャブラリの語彙だけが必要
+		if defined[summary.VocabularyName] {
+			vocabulary, err := s.CustomVocabularyService.GetCustomVocabularyByName(ctx, summary.VocabularyName)
+			if err != nil {
+				return nil, err
+			}
+			remote.Entries = model.NewVocabularyEntries(vocabulary.Vocabularies)
+		}
+		remotes = append(remotes, remote)
+	}
+
+	return model.PlanVocabularySync(definitions, remotes, prune, managedPrefix)
+}
+
+// Apply 計画に沿ってボキャブラリを作成・更新・削除します。失敗した時点で中断します
+func (s *VocabularySyncService) Apply(ctx context.Context, plan *model.VocabularySyncPlan, author string) error {
+	for _, action := range plan.Actions {
+		var err error
+		switch action.Action {
+		case model.SyncActionCreate:
+			err = s.CustomVocabularyService.CreateCustomVocabulary(ctx, dto.CreateVocabularyDto{
+				VocabularyName: action.Name,
+				LanguageCode:   action.LanguageCode,
+				Vocabularies:   toVocabularyDtos(action.Definition.Entries),
+				Author:         author,
+			})
+		case model.SyncActionUpdate:
+			err = s.CustomVocabularyService.UpdateCustomVocabulary(ctx, dto.UpdateVocabularyDto{
+				VocabularyName: action.Name,
+				LanguageCode:   action.LanguageCode,
+				Vocabularies:   toVocabularyDtos(action.Definition.Entries),
+				Author:         author,
+			})
+		case model.SyncActionDelete:
+			err = s.CustomVocabularyService.DeleteCustomVocabulary(ctx, action.Name)
+		default:
+			err = fmt.Errorf("unknown sync action %s", action.Action)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to %s vocabulary %s: %w", action.Action, action.Name, err)
+		}
+	}
+	return nil
+}
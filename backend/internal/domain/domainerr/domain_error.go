@@ -0,0 +1,74 @@
+package domainerr
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Kind ドメインエラーの種類。errors.Is(err, domainerr.NotFound) のように比較に使えます
+type Kind string
+
+// Error errorインターフェースの実装
+func (k Kind) Error() string {
+	return string(k)
+}
+
+// ドメインエラーの種類
+const (
+	NotFound             Kind = "not_found"              // 対象が存在しない
+	Conflict             Kind = "conflict"               // 既存の状態と競合する
+	Validation           Kind = "validation"             // リクエストの内容が不正
+	Unprocessable        Kind = "unprocessable"          // リクエストは正しいが処理できない
+	TooLarge             Kind = "too_large"              // サイズの上限を超えている
+	UnsupportedMediaType Kind = "unsupported_media_type" // 対応していない形式
+	Unauthorized         Kind = "unauthorized"           // 認証されていない
+	Forbidden            Kind = "forbidden"              // 許可されていない操作
+	Throttled            Kind = "throttled"              // リクエストが多すぎる
+	Upstream             Kind = "upstream"               // AWS など外部サービスの障害
+)
+
+// Error 種類と安定したエラーコードを持つドメインエラー
+type Error struct {
+	Kind    Kind
+	Code    string // APIのレスポンスに含める安定したエラーコード (例: custom_vocabulary_not_found)
+	Message string // 利用者に返してよいメッセージ
+	Err     error  // 原因となったエラー
+}
+
+// Error errorインターフェースの実装
+func (e *Error) Error() string {
+	if e.Err != nil {
+		return fmt.Sprintf("%s: %v", e.Message, e.Err)
+	}
+	return e.Message
+}
+
+// Unwrap 原因となったエラーを返します
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Is 種類 (Kind) との比較を可能にします
+func (e *Error) Is(target error) bool {
+	kind, ok := target.(Kind)
+	return ok && kind == e.Kind
+}
+
+// New 新しいドメインエラーを作成します
+func New(kind Kind, code, format string, args ...interface{}) error {
+	return &Error{Kind: kind, Code: code, Message: fmt.Sprintf(format, args...)}
+}
+
+// Wrap 原因となったエラーを保持したドメインエラーを作成します
+func Wrap(kind Kind, code string, err error, format string, args ...interface{}) error {
+	return &Error{Kind: kind, Code: code, Message: fmt.Sprintf(format, args...), Err: err}
+}
+
+// As エラーの連鎖からドメインエラーを取り出します
+func As(err error) (*Error, bool) {
+	var domainErr *Error
+	if errors.As(err, &domainErr) {
+		return domainErr, true
+	}
+	return nil, false
+}
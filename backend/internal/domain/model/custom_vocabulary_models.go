@@ -8,9 +8,10 @@ import (
 
 // CustomVocabulary カスタムボキャブラリを表すドメインモデル
 type CustomVocabulary struct {
-	VocabularyName string // ボキャブラリーの名前
-	LanguageCode   string // 言語コード
-	FileUri        string // ボキャブラリーの語彙リストがあるURI
+	VocabularyName string   // ボキャブラリーの名前
+	LanguageCode   string   // 言語コード
+	FileUri        string   // ボキャブラリーの語彙リストがあるURI (テーブル形式)
+	Phrases        []string // フレーズのリスト (フレーズリスト形式)
 }
 
 // NewCustomVocabulary 新しいCustomVocabularyを作成するファクトリ関数
@@ -22,35 +23,120 @@ func NewCustomVocabulary(name, language, fileUri string) *CustomVocabulary {
 	}
 }
 
+// NewPhraseListVocabulary フレーズリスト形式のCustomVocabularyを作成するファクトリ関数
+func NewPhraseListVocabulary(name, language string, phrases []string) *CustomVocabulary {
+	return &CustomVocabulary{
+		VocabularyName: name,
+		LanguageCode:   language,
+		Phrases:        phrases,
+	}
+}
+
 func (s *CustomVocabulary) Validate() error {
 	if s.VocabularyName == "" || s.LanguageCode == "" {
 		return fmt.Errorf("VocabularyName and LanguageCode are required")
 	}
-	return nil
+	if (s.FileUri == "") == (len(s.Phrases) == 0) {
+		return fmt.Errorf("either FileUri or Phrases is required")
+	}
+	return ValidateResourceName(s.VocabularyName)
+}
+
+// IsPhraseList フレーズリスト形式かどうかを返します
+func (s *CustomVocabulary) IsPhraseList() bool {
+	return len(s.Phrases) > 0
+}
+
+// phraseListMaxBytes フレーズリスト形式で登録する語彙の合計サイズの上限
+const phraseListMaxBytes = 50 * 1024
+
+// CanUsePhraseList IPA, SoundsLike, DisplayAsの列を使っておらず、フレーズリスト形式で登録できる小さな語彙かどうかを判定します
+func CanUsePhraseList(entries []VocabularyEntry) bool {
+	if len(entries) == 0 {
+		return false
+	}
+	size := 0
+	for _, entry := range entries {
+		if entry.IPA != "" || entry.SoundsLike != "" || entry.DisplayAs != "" {
+			return false
+		}
+		size += len(entry.Phrase) + 1
+	}
+	return size <= phraseListMaxBytes
 }
 
+// PhrasesOf 語彙リストからPhraseだけを取り出します
+func PhrasesOf(entries []VocabularyEntry) []string {
+	phrases := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		phrases = append(phrases, entry.Phrase)
+	}
+	return phrases
+}
+
+// ボキャブラリーの登録形式
+const (
+	VocabularyModeTable      = "table"
+	VocabularyModePhraseList = "list"
+)
+
+// ボキャブラリーのステータス (Amazon TranscribeのVocabularyStateと同じ値)
+const (
+	VocabularyStatePending = "PENDING"
+	VocabularyStateReady   = "READY"
+	VocabularyStateFailed  = "FAILED"
+)
+
 // CustomVocabularyDB カスタムボキャブラリ（DB用）を表すドメインモデル
 type CustomVocabularyDB struct {
-	ID        string // ユニークな識別子
-	Name      string // ボキャブラリーの名前
-	Language  string // 言語コード
-	FileUri   string // ボキャブラリーの語彙リストがあるURI
-	State     string // ボキャブラリーのステータス
-	CreatedAt time.Time
+	ID            string // ユニークな識別子
+	Name          string // ボキャブラリーの名前
+	Language      string // 言語コード
+	FileUri       string // ボキャブラリーの語彙リストがあるURI
+	State         string // ボキャブラリーのステータス
+	FailureReason string // FAILEDになった理由
+	Owner         string // 作成を依頼した呼び出し元 (認証が無効な場合や、このサービス以外で作成された場合は空)
+	CreatedAt     time.Time
+	UpdatedAt     time.Time // 最後に作成・更新を依頼した日時
+	SyncedAt      time.Time // 最後にAmazon Transcribeと状態を同期した日時
 }
 
 // NewCustomVocabularyDB 新しいCustomVocabularyを作成するファクトリ関数
-func NewCustomVocabularyDB(name, language string, fileUri string) *CustomVocabularyDB {
+func NewCustomVocabularyDB(name, language string, fileUri, owner string) *CustomVocabularyDB {
+	now := time.Now()
 	return &CustomVocabularyDB{
 		ID:        uuid.New().String(),
 		Name:      name,
 		Language:  language,
 		FileUri:   fileUri,
-		State:     "Pending",
-		CreatedAt: time.Now(),
+		State:     VocabularyStatePending,
+		Owner:     owner,
+		CreatedAt: now,
+		UpdatedAt: now,
 	}
 }
 
+// Resubmit 更新を依頼したボキャブラリをPENDINGに戻します
+func (v *CustomVocabularyDB) Resubmit(language, fileUri string) {
+	v.Language = language
+	v.FileUri = fileUri
+	v.State = VocabularyStatePending
+	v.FailureReason = ""
+	v.UpdatedAt = time.Now()
+}
+
+// ApplyState Amazon Transcribeから取得した状態を反映します
+func (v *CustomVocabularyDB) ApplyState(state, failureReason string) {
+	v.State = state
+	v.FailureReason = failureReason
+	v.SyncedAt = time.Now()
+}
+
+// IsTerminal READYまたはFAILEDで、これ以上状態が変化しないかどうかを返します
+func (v *CustomVocabularyDB) IsTerminal() bool {
+	return v.State == VocabularyStateReady || v.State == VocabularyStateFailed
+}
+
 // CustomVocabularyResponse カスタムボキャブラリの返却値を表すドメインモデル
 type CustomVocabularyResponse struct {
 	VocabularyName   string    // ボキャブラリーの名前
@@ -58,6 +144,7 @@ type CustomVocabularyResponse struct {
 	FileUri          string    // ボキャブラリーの語彙リストがあるURI
 	VocabularyState  string    // ステータス
 	VocabularyLastModifiedTime time.Time // 最終更新日時
+	FailureReason    string    // FAILEDになった理由
 }
 
 // NewCustomVocabularyResponse 新しいNewCustomVocabularyResponseを作成するファクトリ関数
@@ -4,33 +4,36 @@ import (
 	"cmTranscribe/internal/app/dto"
 	"cmTranscribe/internal/shared/constant"
 	"fmt"
-	"time"
+	"path"
 )
 
-type CSVFile struct {
+// ContentTypeTSV TSVファイルのContent-Type
+const ContentTypeTSV = "text/tab-separated-values"
+
+// TSVFile ディスクに書き出さずにアップロードするTSVファイルを表すドメインモデル
+type TSVFile struct {
 	Name    string
-	Path    string
 	Content [][]string
 }
 
-func NewCSVFile(name, path string, content [][]string) *CSVFile {
-	return &CSVFile{
+func NewTSVFile(name string, content [][]string) *TSVFile {
+	return &TSVFile{
 		Name:    name,
-		Path:    path,
 		Content: content,
 	}
 }
 
-func (s *CSVFile) Validate() error {
-	if s.Name == "" || s.Path == "" || len(s.Content) == 0 {
-		return fmt.Errorf("FilePath, BucketName, Content are required")
+func (s *TSVFile) Validate() error {
+	if s.Name == "" || len(s.Content) == 0 {
+		return fmt.Errorf("Name and Content are required")
 	}
-	return nil
+	// 名前はS3のキーの一部になるため、プレフィックスの外を指す名前をアップロードの前に拒否する
+	return ValidateResourceName(s.Name)
 }
 
-func GenerateFilePath(name string) string {
-	timestamp := time.Now().Format("20060102150405")
-	return fmt.Sprintf("/tmp/%s_%s.csv", name, timestamp)
+// ContentKey エンコードした内容のSHA-256 (16進数) から、内容ごとに一意なS3のキーを作成します
+func (s *TSVFile) ContentKey(keyPrefix, digest string) string {
+	return path.Join(keyPrefix, s.Name, digest+".tsv")
 }
 
 // ConvertEntriesToContent は[]dto.Vocabulary[][]stringに変換する
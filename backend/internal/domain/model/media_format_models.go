@@ -0,0 +1,135 @@
+package model
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"strconv"
+)
+
+// Amazon Transcribeで文字起こしできるメディアフォーマット
+const (
+	MediaFormatMP3  = "mp3"
+	MediaFormatMP4  = "mp4"
+	MediaFormatM4A  = "m4a"
+	MediaFormatWAV  = "wav"
+	MediaFormatFLAC = "flac"
+	MediaFormatOGG  = "ogg"
+	MediaFormatWebM = "webm"
+)
+
+// MediaSniffLength フォーマットの判定に読み込む先頭のバイト数
+const MediaSniffLength = 512
+
+// m4aBrands 音声のみのMP4 (m4a) を示すftypのブランド
+var m4aBrands = map[string]bool{"M4A ": true, "M4B ": true, "M4P ": true}
+
+// mp4Brands 動画または汎用のMP4を示すftypのブランド
+var mp4Brands = map[string]bool{"isom": true, "iso2": true, "iso4": true, "iso5": true, "iso6": true, "mp41": true, "mp42": true, "avc1": true, "dash": true, "MSNV": true, "M4V ": true}
+
+// DetectMediaFormat ファイルの先頭のバイト列 (マジックナンバー) からメディアフォーマットを判定します。
+// 判定できない場合は空文字を返します
+func DetectMediaFormat(header []byte) string {
+	switch {
+	case len(header) >= 12 && bytes.Equal(header[0:4], []byte("RIFF")) && bytes.Equal(header[8:12], []byte("WAVE")):
+		return MediaFormatWAV
+	case bytes.HasPrefix(header, []byte("fLaC")):
+		return MediaFormatFLAC
+	case bytes.HasPrefix(header, []byte("OggS")):
+		return MediaFormatOGG
+	case bytes.HasPrefix(header, []byte{0x1A, 0x45, 0xDF, 0xA3}):
+		// EBMLのDocTypeがwebmのもののみ (Matroskaは対象外)
+		if bytes.Contains(header[:minInt(len(header), 64)], []byte("webm")) {
+			return MediaFormatWebM
+		}
+		return ""
+	case len(header) >= 12 && bytes.Equal(header[4:8], []byte("ftyp")):
+		return detectMP4Brand(header)
+	case bytes.HasPrefix(header, []byte("ID3")), isMPEGAudioFrame(header):
+		return MediaFormatMP3
+	}
+	return ""
+}
+
+// detectMP4Brand ftypボックスのブランドからmp4とm4aを区別します
+func detectMP4Brand(header []byte) string {
+	if m4aBrands[string(header[8:12])] {
+		return MediaFormatM4A
+	}
+	if mp4Brands[string(header[8:12])] {
+		return MediaFormatMP4
+	}
+	// メジャーブランドが不明な場合は互換ブランドを確認する
+	size := int(binary.BigEndian.Uint32(header[0:4]))
+	for i := 16; i+4 <= size && i+4 <= len(header); i += 4 {
+		if m4aBrands[string(header[i:i+4])] {
+			return MediaFormatM4A
+		}
+		if mp4Brands[string(header[i:i+4])] {
+			return MediaFormatMP4
+		}
+	}
+	return ""
+}
+
+// isMPEGAudioFrame ID3タグの無いMP3のフレームヘッダーかどうかを判定します (AACのADTSは除く)
+func isMPEGAudioFrame(header []byte) bool {
+	if len(header) < 2 || header[0] != 0xFF || header[1]&0xE0 != 0xE0 {
+		return false
+	}
+	version := (header[1] >> 3) & 0x03
+	layer := (header[1] >> 1) & 0x03
+	return version != 0x01 && layer != 0x00
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+// Amazon Transcribeが受け付けるサンプルレートの範囲
+const (
+	MinMediaSampleRateHertz = 8000
+	MaxMediaSampleRateHertz = 48000
+)
+
+// MediaInfo メディアファイルのヘッダーから読み取った情報を表すドメインモデル
+type MediaInfo struct {
+	Format          string  // Amazon TranscribeのMediaFormat (mp3, mp4, wav, flac, ogg, webm, m4a)
+	Codec           string  // 音声のコーデック (例: pcm_s16le, mp3, aac, vorbis, opus, flac)
+	VideoCodec      string  // 動画を含む場合のコーデック (例: h264)
+	DurationSeconds float64 // 長さ (読み取れない場合は0)
+	SampleRateHertz int     // サンプルレート (読み取れない場合は0)
+	Channels        int     // チャンネル数 (読み取れない場合は0)
+}
+
+// Validate Amazon Transcribeで文字起こしできるメディアかどうかを検証します
+func (m *MediaInfo) Validate() error {
+	if m.Format == "" {
+		return fmt.Errorf("media format could not be determined")
+	}
+	if m.SampleRateHertz != 0 && (m.SampleRateHertz < MinMediaSampleRateHertz || m.SampleRateHertz > MaxMediaSampleRateHertz) {
+		return fmt.Errorf("sample rate %d Hz is outside the supported range (%d-%d Hz)", m.SampleRateHertz, MinMediaSampleRateHertz, MaxMediaSampleRateHertz)
+	}
+	return nil
+}
+
+// Metadata S3のユーザー定義メタデータとして記録する値を返します
+func (m *MediaInfo) Metadata() map[string]string {
+	metadata := map[string]string{"media-format": m.Format}
+	if m.Codec != "" {
+		metadata["codec"] = m.Codec
+	}
+	if m.DurationSeconds > 0 {
+		metadata[MediaDurationMetadataKey] = strconv.FormatFloat(m.DurationSeconds, 'f', 3, 64)
+	}
+	if m.SampleRateHertz > 0 {
+		metadata["sample-rate-hertz"] = strconv.Itoa(m.SampleRateHertz)
+	}
+	if m.Channels > 0 {
+		metadata["channels"] = strconv.Itoa(m.Channels)
+	}
+	return metadata
+}
@@ -0,0 +1,36 @@
+package model
+
+import "testing"
+
+func TestDetectMediaFormat(t *testing.T) {
+	tests := []struct {
+		name   string
+		header []byte
+		want   string
+	}{
+		{name: "wav", header: []byte("RIFF\x24\x00\x00\x00WAVEfmt "), want: MediaFormatWAV},
+		{name: "riff without wave", header: []byte("RIFF\x24\x00\x00\x00AVI LIST"), want: ""},
+		{name: "flac", header: []byte("fLaC\x00\x00\x00\x22"), want: MediaFormatFLAC},
+		{name: "ogg", header: []byte("OggS\x00\x02"), want: MediaFormatOGG},
+		{name: "webm", header: []byte("\x1a\x45\xdf\xa3\x9f\x42\x86\x81\x01\x42\x82\x84webm"), want: MediaFormatWebM},
+		{name: "matroska", header: []byte("\x1a\x45\xdf\xa3\x9f\x42\x86\x81\x01\x42\x82\x88matroska"), want: ""},
+		{name: "m4a brand", header: []byte("\x00\x00\x00\x18ftypM4A \x00\x00\x00\x00"), want: MediaFormatM4A},
+		{name: "mp4 brand", header: []byte("\x00\x00\x00\x18ftypisom\x00\x00\x02\x00"), want: MediaFormatMP4},
+		{name: "compatible m4a brand", header: []byte("\x00\x00\x00\x18ftypXXXX\x00\x00\x00\x00M4A mp42"), want: MediaFormatM4A},
+		{name: "compatible brand beyond the box", header: []byte("\x00\x00\x00\x10ftypXXXX\x00\x00\x00\x00M4A "), want: ""},
+		{name: "ftyp size beyond the header", header: []byte("\xff\xff\xff\xffftypXXXX\x00\x00\x00\x00"), want: ""},
+		{name: "truncated ftyp", header: []byte("\x00\x00\x00\x18ftyp"), want: ""},
+		{name: "mp3 with id3", header: []byte("ID3\x04\x00\x00"), want: MediaFormatMP3},
+		{name: "mp3 frame", header: []byte{0xFF, 0xFB, 0x90, 0x64}, want: MediaFormatMP3},
+		{name: "aac adts", header: []byte{0xFF, 0xF1, 0x50, 0x80}, want: ""},
+		{name: "empty", header: nil, want: ""},
+		{name: "unknown", header: []byte("%PDF-1.7"), want: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := DetectMediaFormat(tt.header); got != tt.want {
+				t.Errorf("DetectMediaFormat() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
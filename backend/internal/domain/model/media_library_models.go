@@ -0,0 +1,97 @@
+package model
+
+import (
+	"cmTranscribe/internal/shared/utils"
+	"fmt"
+	"path"
+	"strconv"
+	"strings"
+)
+
+// MediaDurationMetadataKey メディアの長さ (秒) を記録するS3のユーザー定義メタデータのキー
+const MediaDurationMetadataKey = "duration-seconds"
+
+// MaxCopyObjectSize S3 の CopyObject で1回にコピーできるオブジェクトの最大サイズ (5GiB)
+const MaxCopyObjectSize = 5 * 1024 * 1024 * 1024
+
+// MediaOwnerMetadataKey メディアをアップロードした呼び出し元を記録するS3のユーザー定義メタデータのキー
+const MediaOwnerMetadataKey = "owner"
+
+// OwnerMetadata アップロードした呼び出し元を記録するユーザー定義メタデータを返します。認証が無効な場合 (owner が空) は nil を返します
+func OwnerMetadata(owner string) map[string]string {
+	if owner == "" {
+		return nil
+	}
+	return map[string]string{MediaOwnerMetadataKey: owner}
+}
+
+// mediaExtensions メディアライブラリに表示するファイルの拡張子
+var mediaExtensions = map[string]bool{
+	"." + MediaFormatMP3:  true,
+	"." + MediaFormatMP4:  true,
+	"." + MediaFormatM4A:  true,
+	"." + MediaFormatWAV:  true,
+	"." + MediaFormatFLAC: true,
+	"." + MediaFormatOGG:  true,
+	"." + MediaFormatWebM: true,
+	".amr":                true,
+}
+
+// IsMediaKey メディアファイルのキーかどうかを拡張子で判定します (文字起こし結果や語彙ファイルを除くため)
+func IsMediaKey(key string) bool {
+	return mediaExtensions[strings.ToLower(path.Ext(key))]
+}
+
+// MediaFile アップロード済みのメディアファイルを表すドメインモデル
+type MediaFile struct {
+	Object          *S3ObjectInfo
+	FileName        string
+	DurationSeconds *float64 // 長さが記録されていない場合はnil
+	Jobs            []*TranscriptionJobDB
+}
+
+// NewMediaFile S3オブジェクトのメタデータからMediaFileを作成するファクトリ関数
+func NewMediaFile(object *S3ObjectInfo, jobs []*TranscriptionJobDB) *MediaFile {
+	file := &MediaFile{
+		Object:   object,
+		FileName: path.Base(object.Key),
+		Jobs:     jobs,
+	}
+	if value, exists := object.Metadata[MediaDurationMetadataKey]; exists {
+		if duration, err := strconv.ParseFloat(value, 64); err == nil {
+			file.DurationSeconds = &duration
+		}
+	}
+	return file
+}
+
+// MediaRename メディアファイルの名前の変更を表すドメインモデル
+type MediaRename struct {
+	SourceKey      string
+	DestinationKey string
+}
+
+// NewMediaRename 新しいMediaRenameを作成するファクトリ関数。
+// 変更後のキーは同じディレクトリの下にサニタイズした名前で作成し、拡張子は元のファイルのものを引き継ぎます
+func NewMediaRename(sourceKey, newName string) *MediaRename {
+	ext := path.Ext(sourceKey)
+	baseName := utils.SanitizeFileName(strings.TrimSuffix(newName, path.Ext(newName)), "")
+	destinationKey := ""
+	if baseName != "" {
+		destinationKey = path.Join(path.Dir(sourceKey), baseName+ext)
+	}
+	return &MediaRename{
+		SourceKey:      sourceKey,
+		DestinationKey: destinationKey,
+	}
+}
+
+func (r *MediaRename) Validate() error {
+	if r.SourceKey == "" || r.DestinationKey == "" {
+		return fmt.Errorf("key and a valid newName are required")
+	}
+	if r.SourceKey == r.DestinationKey {
+		return fmt.Errorf("newName must differ from the current name")
+	}
+	return nil
+}
@@ -0,0 +1,58 @@
+package model
+
+import "context"
+
+// Principal 認証されたAPIの呼び出し元
+type Principal struct {
+	Subject string   // 呼び出し元を一意に表す識別子 (JWTの sub、APIキーに設定した名前)
+	Roles   []string // 呼び出し元のロール
+	Admin   bool     // 管理者ロールを持ち、他の呼び出し元のリソースも参照できる
+	Tenant  string   // 認証情報に紐付いたテナントのID (空の場合、管理者だけが X-Tenant-ID ヘッダーでテナントを指定できる)
+}
+
+// NewPrincipal 新しいPrincipalを作成するファクトリ関数。adminRole を持つ場合は管理者として扱います
+func NewPrincipal(subject string, roles []string, adminRole string) *Principal {
+	principal := &Principal{Subject: subject, Roles: roles}
+	for _, role := range roles {
+		if adminRole != "" && role == adminRole {
+			principal.Admin = true
+		}
+	}
+	return principal
+}
+
+// WithTenant 認証情報に紐付いたテナントを設定します
+func (p *Principal) WithTenant(tenant string) *Principal {
+	p.Tenant = tenant
+	return p
+}
+
+// CanAccess owner が所有するリソースを参照できるかどうかを返します。
+// 認証が無効な場合 (Principal が nil) は、すべてのリソースを参照できます
+func (p *Principal) CanAccess(owner string) bool {
+	if p == nil || p.Admin {
+		return true
+	}
+	return owner != "" && owner == p.Subject
+}
+
+type principalContextKey struct{}
+
+// ContextWithPrincipal 呼び出し元を保持したコンテキストを返します
+func ContextWithPrincipal(ctx context.Context, principal *Principal) context.Context {
+	return context.WithValue(ctx, principalContextKey{}, principal)
+}
+
+// PrincipalFromContext コンテキストの呼び出し元を返します。認証が無効な場合は nil を返します
+func PrincipalFromContext(ctx context.Context) *Principal {
+	principal, _ := ctx.Value(principalContextKey{}).(*Principal)
+	return principal
+}
+
+// OwnerFromContext リソースの所有者として記録する呼び出し元の識別子を返します。認証が無効な場合は空です
+func OwnerFromContext(ctx context.Context) string {
+	if principal := PrincipalFromContext(ctx); principal != nil {
+		return principal.Subject
+	}
+	return ""
+}
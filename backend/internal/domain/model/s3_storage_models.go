@@ -1,11 +1,15 @@
 package model
 
-import "fmt"
+import (
+	"fmt"
+	"io"
+)
 
 type S3File struct {
 	FilePath   string
 	BucketName string
 	KeyPrefix  string
+	Metadata   map[string]string // オブジェクトに記録するユーザー定義メタデータ
 }
 
 func NewS3File(path, bucketName, keyPrefix string) *S3File {
@@ -22,3 +26,28 @@ func (s *S3File) Validate() error {
 	}
 	return nil
 }
+
+// S3Object ストリームから直接アップロードするS3オブジェクトを表すドメインモデル
+type S3Object struct {
+	BucketName  string
+	Key         string
+	Body        io.Reader
+	ContentType string
+	Metadata    map[string]string // ユーザー定義メタデータ
+}
+
+func NewS3Object(bucketName, key string, body io.Reader, contentType string) *S3Object {
+	return &S3Object{
+		BucketName:  bucketName,
+		Key:         key,
+		Body:        body,
+		ContentType: contentType,
+	}
+}
+
+func (s *S3Object) Validate() error {
+	if s.BucketName == "" || s.Key == "" || s.Body == nil {
+		return fmt.Errorf("BucketName, Key and Body are required")
+	}
+	return nil
+}
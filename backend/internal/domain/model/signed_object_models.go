@@ -0,0 +1,107 @@
+package model
+
+import (
+	"cmTranscribe/internal/domain/domainerr"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// 署名付きURLのパラメータ名
+const (
+	signedParamBucket      = "bucket"
+	signedParamKey         = "key"
+	signedParamContentType = "contentType"
+	signedParamSize        = "size"
+	signedParamMaxBytes    = "maxBytes"
+	signedParamExpires     = "expires"
+	signedParamOwner       = "owner"
+	SignedParamSignature   = "signature"
+)
+
+// SignedObjectRequest サーバー自身が発行・検証する署名付きURLで許可されたオブジェクトの操作を表すドメインモデル
+type SignedObjectRequest struct {
+	Method      string // GET, PUT または POST
+	BucketName  string
+	Key         string
+	ContentType string // アップロードの場合に送る必要のあるContent-Type
+	Size        int64  // PUTの場合に送る必要のあるサイズ
+	MaxBytes    int64  // POSTの場合にアップロードできるサイズの上限
+	Owner       string // アップロードしたオブジェクトのメタデータに記録する呼び出し元
+	ExpiresAt   time.Time
+}
+
+// StringToSign 署名の対象となる文字列を返します
+func (r *SignedObjectRequest) StringToSign() string {
+	return strings.Join([]string{
+		r.Method,
+		r.BucketName,
+		r.Key,
+		r.ContentType,
+		strconv.FormatInt(r.Size, 10),
+		strconv.FormatInt(r.MaxBytes, 10),
+		strconv.FormatInt(r.ExpiresAt.Unix(), 10),
+		r.Owner,
+	}, "\n")
+}
+
+// Params 署名を含めたURLのクエリパラメータ (POSTの場合はフォームフィールド) を返します
+func (r *SignedObjectRequest) Params(signature string) url.Values {
+	params := url.Values{}
+	params.Set(signedParamBucket, r.BucketName)
+	params.Set(signedParamKey, r.Key)
+	if r.ContentType != "" {
+		params.Set(signedParamContentType, r.ContentType)
+	}
+	if r.Size > 0 {
+		params.Set(signedParamSize, strconv.FormatInt(r.Size, 10))
+	}
+	if r.MaxBytes > 0 {
+		params.Set(signedParamMaxBytes, strconv.FormatInt(r.MaxBytes, 10))
+	}
+	if r.Owner != "" {
+		params.Set(signedParamOwner, r.Owner)
+	}
+	params.Set(signedParamExpires, strconv.FormatInt(r.ExpiresAt.Unix(), 10))
+	params.Set(SignedParamSignature, signature)
+	return params
+}
+
+// Expired 有効期限が切れているかどうかを返します
+func (r *SignedObjectRequest) Expired(now time.Time) bool {
+	return now.After(r.ExpiresAt)
+}
+
+// ParseSignedObjectRequest URLのクエリパラメータ (POSTの場合はフォームフィールド) から署名付きの操作と署名を取り出します
+func ParseSignedObjectRequest(method string, params url.Values) (*SignedObjectRequest, string, error) {
+	request := &SignedObjectRequest{
+		Method:      strings.ToUpper(method),
+		BucketName:  params.Get(signedParamBucket),
+		Key:         params.Get(signedParamKey),
+		ContentType: params.Get(signedParamContentType),
+		Owner:       params.Get(signedParamOwner),
+	}
+	signature := params.Get(SignedParamSignature)
+	if request.BucketName == "" || request.Key == "" || signature == "" {
+		return nil, "", domainerr.New(domainerr.Forbidden, "invalid_signed_url", "bucket, key and signature are required")
+	}
+
+	expires, err := strconv.ParseInt(params.Get(signedParamExpires), 10, 64)
+	if err != nil {
+		return nil, "", domainerr.New(domainerr.Forbidden, "invalid_signed_url", "expires is invalid")
+	}
+	request.ExpiresAt = time.Unix(expires, 0)
+
+	if value := params.Get(signedParamSize); value != "" {
+		if request.Size, err = strconv.ParseInt(value, 10, 64); err != nil {
+			return nil, "", domainerr.New(domainerr.Forbidden, "invalid_signed_url", "size is invalid")
+		}
+	}
+	if value := params.Get(signedParamMaxBytes); value != "" {
+		if request.MaxBytes, err = strconv.ParseInt(value, 10, 64); err != nil {
+			return nil, "", domainerr.New(domainerr.Forbidden, "invalid_signed_url", "maxBytes is invalid")
+		}
+	}
+	return request, signature, nil
+}
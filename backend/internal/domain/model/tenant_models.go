@@ -0,0 +1,183 @@
+package model
+
+import (
+	"cmTranscribe/internal/domain/domainerr"
+	"context"
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+)
+
+// tenantIDPattern テナントIDに使える文字。ジョブ名などの区切りに使う "." を含めないことで、名前の接頭辞が他のテナントと重ならないようにします
+var tenantIDPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9-]{0,62}$`)
+
+// resourceNamePattern Amazon Transcribe のジョブ名やボキャブラリ名に使える文字
+var resourceNamePattern = regexp.MustCompile(`^[0-9a-zA-Z._-]*$`)
+
+// ValidateResourceName ジョブ名やボキャブラリ名などの Amazon Transcribe 上の名前を検証します。
+// 名前はS3のキーの一部にもなるため、プレフィックスの外を指す "." と ".." も拒否します
+func ValidateResourceName(name string) error {
+	if name == "" || !resourceNamePattern.MatchString(name) || name == "." || name == ".." {
+		return domainerr.New(domainerr.Validation, "invalid_name", "name %q must be letters, digits, '.', '_' and '-'", name)
+	}
+	return nil
+}
+
+// Tenant 1つのデプロイを共有するチームごとの区画
+type Tenant struct {
+	ID                     string
+	NamePrefix             string // ジョブ名、ボキャブラリ名、語彙フィルタ名に付ける接頭辞
+	UploadPrefix           string // メディアファイルをアップロードするS3のプレフィックス
+	VocabularyPrefix       string // カスタムボキャブラリの語彙ファイルをアップロードするS3のプレフィックス
+	VocabularyFilterPrefix string // 語彙フィルタの単語リストをアップロードするS3のプレフィックス
+	TranscriptPrefix       string // Amazon Transcribe が文字起こし結果を出力するS3のプレフィックス
+	Quota                  TenantQuota
+}
+
+// TenantQuota テナントごとの上限。0の場合は上限を設けません
+type TenantQuota struct {
+	MaxActiveJobs   int   // 同時に実行できる (QUEUED または IN_PROGRESS の) 文字起こしジョブの数
+	MaxVocabularies int   // 作成できるカスタムボキャブラリの数
+	MaxUploadBytes  int64 // アップロードできるファイル1件あたりのサイズ (UPLOAD_MAX_BYTES より小さい場合のみ有効)
+}
+
+// NewTenant 新しいTenantを作成するファクトリ関数。名前の接頭辞を省略した場合は "<id>." になります
+func NewTenant(id, namePrefix, uploadPrefix, vocabularyPrefix, vocabularyFilterPrefix string, quota TenantQuota) *Tenant {
+	if namePrefix == "" {
+		namePrefix = id + "."
+	}
+	return &Tenant{
+		ID:                     id,
+		NamePrefix:             namePrefix,
+		UploadPrefix:           uploadPrefix,
+		VocabularyPrefix:       vocabularyPrefix,
+		VocabularyFilterPrefix: vocabularyFilterPrefix,
+		Quota:                  quota,
+	}
+}
+
+// WithDefaultPrefixes 省略されたS3のプレフィックスを、"tenants/<id>/" の下に既定のプレフィックスを付けたものにします。
+// 文字起こし結果の出力先は "tenants/<id>/transcripts" になります
+func (t *Tenant) WithDefaultPrefixes(uploadPrefix, vocabularyPrefix, vocabularyFilterPrefix string) *Tenant {
+	if t.UploadPrefix == "" {
+		t.UploadPrefix = path.Join("tenants", t.ID, uploadPrefix)
+	}
+	if t.VocabularyPrefix == "" {
+		t.VocabularyPrefix = path.Join("tenants", t.ID, vocabularyPrefix)
+	}
+	if t.VocabularyFilterPrefix == "" {
+		t.VocabularyFilterPrefix = path.Join("tenants", t.ID, vocabularyFilterPrefix)
+	}
+	if t.TranscriptPrefix == "" {
+		t.TranscriptPrefix = path.Join("tenants", t.ID, "transcripts")
+	}
+	return t
+}
+
+// Validate テナントの設定を検証します
+func (t *Tenant) Validate() error {
+	if !tenantIDPattern.MatchString(t.ID) {
+		return fmt.Errorf("tenant id %q must be lowercase letters, digits and hyphens", t.ID)
+	}
+	if t.NamePrefix == "" || !resourceNamePattern.MatchString(t.NamePrefix) {
+		return fmt.Errorf("name prefix of tenant %s must be letters, digits, '.', '_' and '-'", t.ID)
+	}
+	for _, prefix := range t.prefixes() {
+		if prefix == "" || prefix == "." || path.Clean(prefix) != strings.TrimSuffix(prefix, "/") || strings.HasPrefix(prefix, "/") || strings.HasPrefix(prefix, "..") {
+			return fmt.Errorf("S3 prefix %q of tenant %s is invalid", prefix, t.ID)
+		}
+	}
+	if t.Quota.MaxActiveJobs < 0 || t.Quota.MaxVocabularies < 0 || t.Quota.MaxUploadBytes < 0 {
+		return fmt.Errorf("quota of tenant %s must not be negative", t.ID)
+	}
+	return nil
+}
+
+// Overlaps 他のテナントと名前の接頭辞やS3のプレフィックスが重なり、リソースを区別できないかどうかを返します
+func (t *Tenant) Overlaps(other *Tenant) bool {
+	if strings.HasPrefix(t.NamePrefix, other.NamePrefix) || strings.HasPrefix(other.NamePrefix, t.NamePrefix) {
+		return true
+	}
+	for _, prefix := range t.prefixes() {
+		for _, otherPrefix := range other.prefixes() {
+			if keyUnderPrefix(prefix, otherPrefix) || keyUnderPrefix(otherPrefix, prefix) || path.Clean(prefix) == path.Clean(otherPrefix) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// prefixes テナントが使うS3のプレフィックスを返します
+func (t *Tenant) prefixes() []string {
+	return []string{t.UploadPrefix, t.VocabularyPrefix, t.VocabularyFilterPrefix, t.TranscriptPrefix}
+}
+
+// TranscriptKey ジョブの文字起こし結果を出力するS3のキーを返します。
+// テナントが無い場合 (nil) は、これまでどおりバケット直下の "<ジョブ名>.json" を返します
+func (t *Tenant) TranscriptKey(jobName string) string {
+	if t == nil {
+		return TranscriptKey("", jobName)
+	}
+	return TranscriptKey(t.TranscriptPrefix, jobName)
+}
+
+// ResourceName クライアントが指定した名前に接頭辞を付けた Amazon Transcribe 上の名前を返します。
+// テナントが無い場合 (nil) は名前をそのまま返します
+func (t *Tenant) ResourceName(name string) string {
+	if t == nil || name == "" {
+		return name
+	}
+	return t.NamePrefix + name
+}
+
+// DisplayName Amazon Transcribe 上の名前から接頭辞を除いた、クライアントに返す名前を返します。
+// テナントのリソースではない場合は false を返します
+func (t *Tenant) DisplayName(name string) (string, bool) {
+	if t == nil {
+		return name, true
+	}
+	if !strings.HasPrefix(name, t.NamePrefix) {
+		return "", false
+	}
+	return strings.TrimPrefix(name, t.NamePrefix), true
+}
+
+// OwnsName Amazon Transcribe 上の名前がテナントのリソースかどうかを返します
+func (t *Tenant) OwnsName(name string) bool {
+	_, ok := t.DisplayName(name)
+	return ok
+}
+
+// OwnsKey S3のキーがテナントのプレフィックスの下にあるかどうかを返します
+func (t *Tenant) OwnsKey(key string) bool {
+	if t == nil {
+		return true
+	}
+	for _, prefix := range []string{t.UploadPrefix, t.VocabularyPrefix, t.VocabularyFilterPrefix} {
+		if keyUnderPrefix(key, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// keyUnderPrefix キーがプレフィックスのディレクトリの下にあるかどうかを返します
+func keyUnderPrefix(key, prefix string) bool {
+	return strings.HasPrefix(key, strings.TrimSuffix(path.Clean(prefix), "/")+"/")
+}
+
+type tenantContextKey struct{}
+
+// ContextWithTenant テナントを保持したコンテキストを返します
+func ContextWithTenant(ctx context.Context, tenant *Tenant) context.Context {
+	return context.WithValue(ctx, tenantContextKey{}, tenant)
+}
+
+// TenantFromContext コンテキストのテナントを返します。
+// テナントが設定されていない場合や、管理者がテナントを指定しなかった場合は nil を返し、すべてのリソースを対象にします
+func TenantFromContext(ctx context.Context) *Tenant {
+	tenant, _ := ctx.Value(tenantContextKey{}).(*Tenant)
+	return tenant
+}
@@ -0,0 +1,92 @@
+package model
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// TranscriptItem Amazon Transcribeの出力JSONに含まれる単語・句読点1件分を表すドメインモデル
+type TranscriptItem struct {
+	Type       string  // pronunciation または punctuation
+	Content    string  // 認識された文字列
+	Confidence float64 // 信頼度 (0〜1)
+}
+
+// IsPronunciation 単語（句読点以外）かどうかを返します
+func (i TranscriptItem) IsPronunciation() bool {
+	return i.Type == "pronunciation"
+}
+
+// Transcript Amazon Transcribeの出力JSONを表すドメインモデル
+type Transcript struct {
+	JobName string
+	Text    string
+	Items   []TranscriptItem
+}
+
+// transcribeOutput Amazon Transcribeの出力JSONの構造
+type transcribeOutput struct {
+	JobName string `json:"jobName"`
+	Results struct {
+		Transcripts []struct {
+			Transcript string `json:"transcript"`
+		} `json:"transcripts"`
+		Items []struct {
+			Type         string `json:"type"`
+			Alternatives []struct {
+				Confidence string `json:"confidence"`
+				Content    string `json:"content"`
+			} `json:"alternatives"`
+		} `json:"items"`
+	} `json:"results"`
+}
+
+// ParseTranscript Amazon Transcribeの出力JSONをパースします
+func ParseTranscript(content []byte) (*Transcript, error) {
+	var output transcribeOutput
+	if err := json.Unmarshal(content, &output); err != nil {
+		return nil, fmt.Errorf("failed to parse transcription content: %w", err)
+	}
+
+	transcript := &Transcript{JobName: output.JobName}
+	if len(output.Results.Transcripts) > 0 {
+		transcript.Text = output.Results.Transcripts[0].Transcript
+	}
+	for _, item := range output.Results.Items {
+		if len(item.Alternatives) == 0 {
+			continue
+		}
+		alt := item.Alternatives[0]
+		// 句読点は信頼度が空文字になることがある
+		confidence, err := strconv.ParseFloat(alt.Confidence, 64)
+		if err != nil {
+			confidence = 0
+		}
+		transcript.Items = append(transcript.Items, TranscriptItem{
+			Type:       item.Type,
+			Content:    alt.Content,
+			Confidence: confidence,
+		})
+	}
+	return transcript, nil
+}
+
+// JoinTokens 言語に応じて単語を連結します（日本語などは空白を入れない）
+func JoinTokens(languageCode string, tokens []string) string {
+	if IsUnspacedLanguage(languageCode) {
+		return strings.Join(tokens, "")
+	}
+	return strings.Join(tokens, " ")
+}
+
+// IsUnspacedLanguage 単語間に空白を入れない言語かどうかを返します
+func IsUnspacedLanguage(languageCode string) bool {
+	for _, prefix := range []string{"ja", "zh", "ko", "th"} {
+		if strings.HasPrefix(languageCode, prefix) {
+			return true
+		}
+	}
+	return false
+}
@@ -4,15 +4,29 @@ import (
 	"fmt"
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/transcribe/types"
+	"path"
 	"time"
 )
 
 // TranscriptionJob Amazon Transcribeに渡すデータ構造
 type TranscriptionJob struct {
-	JobName              string
-	MediaFileURI         string
-	LanguageCode         string
-	CustomVocabularyName string
+	JobName                string
+	MediaFileURI           string
+	LanguageCode           string
+	CustomVocabularyName   string
+	VocabularyFilterName   string
+	VocabularyFilterMethod string // mask, remove, tag
+	MediaFormat            string // 省略時はAmazon Transcribeが判定する
+	MediaSampleRateHertz   int    // 省略時はAmazon Transcribeが判定する
+	OutputKey              string // 文字起こし結果を出力するS3のキー (省略時はバケット直下の "<ジョブ名>.json")
+}
+
+// TranscriptKey プレフィックスの下にジョブの文字起こし結果を出力するS3のキーを返します。プレフィックスが空の場合はバケット直下です
+func TranscriptKey(prefix, jobName string) string {
+	if prefix == "" {
+		return jobName + ".json"
+	}
+	return path.Join(prefix, jobName+".json")
 }
 
 func NewTranscriptionJob(jobName, mediaFileUri, languageCode, customVocabularyName string) *TranscriptionJob {
@@ -24,33 +38,129 @@ func NewTranscriptionJob(jobName, mediaFileUri, languageCode, customVocabularyNa
 	}
 }
 
+// WithVocabularyFilter 語彙フィルタを設定します。適用方法を省略した場合はmaskになります
+func (s *TranscriptionJob) WithVocabularyFilter(name, method string) *TranscriptionJob {
+	s.VocabularyFilterName = name
+	s.VocabularyFilterMethod = method
+	if name != "" && method == "" {
+		s.VocabularyFilterMethod = VocabularyFilterMethodMask
+	}
+	return s
+}
+
+// WithMedia メディアの解析結果からフォーマットとサンプルレートを設定します。
+// 解析できなかった場合は defaultFormat をフォーマットとして使います
+func (s *TranscriptionJob) WithMedia(media *MediaInfo, defaultFormat string) *TranscriptionJob {
+	if media == nil {
+		s.MediaFormat = defaultFormat
+		return s
+	}
+	s.MediaFormat = media.Format
+	s.MediaSampleRateHertz = media.SampleRateHertz
+	return s
+}
+
 func (s *TranscriptionJob) Validate() error {
 	if s.JobName == "" || s.MediaFileURI == "" || s.LanguageCode == "" {
 		return fmt.Errorf("JobName, MediaFileURI, LanguageCode are required")
 	}
+	if s.VocabularyFilterName != "" && !IsValidVocabularyFilterMethod(s.VocabularyFilterMethod) {
+		return fmt.Errorf("VocabularyFilterMethod must be one of mask, remove, tag")
+	}
 	return nil
 }
 
+// Amazon Transcribe に送信する前のジョブの状態
+const (
+	TranscriptionJobStatusPending           = "Pending"            // 送信中
+	TranscriptionJobStatusPendingSubmission = "PENDING_SUBMISSION" // 同時実行数の上限に達しているため、送信を待っている
+	TranscriptionJobStatusSubmissionFailed  = "SUBMISSION_FAILED"  // 送信を待っている間に開始できなくなった
+)
+
+// Amazon Transcribe のジョブの状態
+const (
+	TranscriptionJobStatusQueued     = "QUEUED"      // 実行中 (同時実行数の上限に数えられる)
+	TranscriptionJobStatusInProgress = "IN_PROGRESS" // 実行中 (同時実行数の上限に数えられる)
+	TranscriptionJobStatusCompleted  = "COMPLETED"
+	TranscriptionJobStatusFailed     = "FAILED"
+)
+
 // TranscriptionJobDB 文字起こしジョブを表します。
 type TranscriptionJobDB struct {
-	JobName      string
-	MediaFileURI string
-	Language     string
-	Status       string
-	CreatedAt    time.Time
+	JobName       string
+	MediaFileURI  string
+	Language      string
+	Status        string
+	CreatedAt     time.Time
+	Media         *MediaInfo // ジョブの開始時にメディアを解析した結果 (解析できなかった場合はnil)
+	Owner         string     // ジョブを開始した呼び出し元 (認証が無効な場合は空)
+	TenantID      string     // ジョブを開始したテナント (テナントを使わない場合は空)
+	OutputKey     string     // 文字起こし結果を出力するS3のキー
+	FailureReason string     // SUBMISSION_FAILED になった理由
+	// PENDING_SUBMISSION の間、送信するジョブの内容 (再起動後に待ち行列へ戻すために記録する)
+	Submission *TranscriptionJob
 }
 
 // NewTranscriptionJobDB 新しいTranscriptionJobを作成します。
-func NewTranscriptionJobDB(jobName, mediaFileUri, language string) *TranscriptionJobDB {
+func NewTranscriptionJobDB(jobName, mediaFileUri, language, owner string) *TranscriptionJobDB {
 	return &TranscriptionJobDB{
 		JobName:      jobName,
 		MediaFileURI: mediaFileUri,
 		Language:     language,
-		Status:       "Pending",
+		Status:       TranscriptionJobStatusPending,
 		CreatedAt:    time.Now(),
+		Owner:        owner,
 	}
 }
 
+// MarkPendingSubmission 同時実行数の上限に達しているため、送信を待っている状態にします
+func (j *TranscriptionJobDB) MarkPendingSubmission(job *TranscriptionJob) {
+	submission := *job
+	j.Status = TranscriptionJobStatusPendingSubmission
+	j.Submission = &submission
+}
+
+// MarkSubmitted Amazon Transcribe がジョブを受け付けた状態にします
+func (j *TranscriptionJobDB) MarkSubmitted(status string) {
+	j.Status = status
+	j.FailureReason = ""
+	j.Submission = nil
+}
+
+// MarkSubmissionFailed 送信を待っていたジョブを開始できなかった状態にします
+func (j *TranscriptionJobDB) MarkSubmissionFailed(reason string) {
+	j.Status = TranscriptionJobStatusSubmissionFailed
+	j.FailureReason = reason
+	j.Submission = nil
+}
+
+// ApplyStatus Amazon Transcribe で確認したジョブの状態を記録します。
+// 完了 (COMPLETED または FAILED) したことを初めて記録した場合は true を返します
+func (j *TranscriptionJobDB) ApplyStatus(status string) bool {
+	if j.Status == status || j.IsUnsubmitted() {
+		return false
+	}
+	j.Status = status
+	return status == TranscriptionJobStatusCompleted || status == TranscriptionJobStatusFailed
+}
+
+// IsUnsubmitted Amazon Transcribe にジョブが無い (送信待ちまたは送信に失敗した) かどうかを返します
+func (j *TranscriptionJobDB) IsUnsubmitted() bool {
+	return j.Status == TranscriptionJobStatusPendingSubmission || j.Status == TranscriptionJobStatusSubmissionFailed
+}
+
+// IsActiveTranscriptionJobStatus 同時実行数の上限に数えられる状態かどうかを返します
+func IsActiveTranscriptionJobStatus(status string) bool {
+	return status == TranscriptionJobStatusQueued || status == TranscriptionJobStatusInProgress
+}
+
+// QueuedTranscriptionJob 同時実行数の上限に達しているため、送信を待っている文字起こしジョブ
+type QueuedTranscriptionJob struct {
+	Job        *TranscriptionJob
+	Record     *TranscriptionJobDB
+	EnqueuedAt time.Time
+}
+
 // TranscriptionJobStatusResponse は、ジョブ名とステータスを表す構造体
 type TranscriptionJobStatusResponse struct {
 	JobName                string
@@ -73,6 +183,7 @@ type TranscriptionJobResponse struct {
 	LanguageCode           string
 	TranscriptionJobStatus string
 	OutputLocation         string
+	VocabularyName         string // ジョブで使用したカスタムボキャブラリ
 }
 
 // NewTranscriptionJobResponse は AWS Transcribe のジョブ詳細情報をドメインモデルに変換します
@@ -80,7 +191,7 @@ func NewTranscriptionJobResponse(job *types.TranscriptionJob) *TranscriptionJobR
 	if job == nil {
 		return nil // ジョブがnilの場合はnilを返す
 	}
-	return &TranscriptionJobResponse{
+	response := &TranscriptionJobResponse{
 		JobName:                aws.ToString(job.TranscriptionJobName),
 		CreationTime:           aws.ToTime(job.CreationTime),
 		CompletionTime:         job.CompletionTime,
@@ -88,6 +199,10 @@ func NewTranscriptionJobResponse(job *types.TranscriptionJob) *TranscriptionJobR
 		TranscriptionJobStatus: string(job.TranscriptionJobStatus),
 		OutputLocation:         aws.ToString(job.Transcript.TranscriptFileUri),
 	}
+	if job.Settings != nil {
+		response.VocabularyName = aws.ToString(job.Settings.VocabularyName)
+	}
+	return response
 }
 
 // TranscriptionJobSummaryResponse カスタムボキャブラリの返却値を表すドメインモデル
@@ -114,17 +229,30 @@ func NewTranscriptionJobSummaryResponse(job types.TranscriptionJobSummary) *Tran
 
 // TranscriptionJobSummariesResponse は複数のジョブの返却値を表すドメインモデル
 type TranscriptionJobSummariesResponse struct {
-	Jobs []*TranscriptionJobSummaryResponse // TranscriptionJobSummaryResponse を配列で保持
+	Jobs      []*TranscriptionJobSummaryResponse // TranscriptionJobSummaryResponse を配列で保持
+	NextToken string                             // 次のページが無い場合は空
 }
 
-// NewTranscriptionJobSummariesResponse は AWS Transcribe のジョブリストをドメインモデルに変換します
-func NewTranscriptionJobSummariesResponse(jobs []types.TranscriptionJobSummary) *TranscriptionJobSummariesResponse {
+// NewTranscriptionJobSummariesResponse は AWS Transcribe のジョブリストの1ページをドメインモデルに変換します
+func NewTranscriptionJobSummariesResponse(jobs []types.TranscriptionJobSummary, nextToken string) *TranscriptionJobSummariesResponse {
 	jobResponses := make([]*TranscriptionJobSummaryResponse, len(jobs))
 	for i, job := range jobs {
 		jobResponses[i] = NewTranscriptionJobSummaryResponse(job)
 	}
 
 	return &TranscriptionJobSummariesResponse{
-		Jobs: jobResponses,
+		Jobs:      jobResponses,
+		NextToken: nextToken,
 	}
 }
+
+// MaxTranscriptionJobPageSize ジョブの一覧の1ページに取得できるジョブの数の上限 (Amazon Transcribe の上限)
+const MaxTranscriptionJobPageSize = 100
+
+// TranscriptionJobListQuery ジョブの一覧を1ページ取得する条件
+type TranscriptionJobListQuery struct {
+	NameContains string // ジョブ名に含む文字列 (テナントの接頭辞など。空の場合は絞り込まない)
+	Status       string // ジョブの状態 (空の場合は絞り込まない)
+	NextToken    string // 前のページの NextToken (空の場合は最初のページ)
+	MaxResults   int    // 1ページのジョブの数 (1 から MaxTranscriptionJobPageSize)
+}
@@ -0,0 +1,163 @@
+package model
+
+import (
+	"fmt"
+	"github.com/google/uuid"
+	"path"
+	"strings"
+	"time"
+)
+
+// 署名付きURLでのアップロード方法
+const (
+	UploadMethodPut  = "PUT"  // 署名付きURLにPUTでファイル本体を送る
+	UploadMethodPost = "POST" // 署名付きのフォームフィールドと一緒にmultipart/form-dataで送る
+)
+
+// uploadContentTypes 文字起こしに使えるメディアのContent-Type
+var uploadContentTypes = map[string]bool{
+	"audio/mpeg":  true,
+	"audio/mp4":   true,
+	"audio/x-m4a": true,
+	"audio/wav":   true,
+	"audio/x-wav": true,
+	"audio/flac":  true,
+	"audio/ogg":   true,
+	"audio/webm":  true,
+	"audio/amr":   true,
+	"video/mp4":   true,
+	"video/webm":  true,
+}
+
+// IsUploadContentType 文字起こしに使えるメディアのContent-Typeかどうかを返します
+func IsUploadContentType(contentType string) bool {
+	return uploadContentTypes[strings.ToLower(contentType)]
+}
+
+// UploadRequest 署名付きURLで直接S3にアップロードするメディアファイルを表すドメインモデル
+type UploadRequest struct {
+	FileName    string
+	ContentType string
+	Size        int64 // PUTの場合は必須。POSTの場合は省略可能 (上限のみを制約する)
+	Method      string
+	MaxBytes    int64 // アップロードできるサイズの上限
+	Key         string
+	Owner       string // オブジェクトのメタデータに記録する呼び出し元 (認証が無効な場合は空)
+}
+
+// NewUploadRequest 新しいUploadRequestを作成するファクトリ関数。キーはkeyPrefixの下に一意に生成します
+func NewUploadRequest(fileName, contentType string, size int64, method string, maxBytes int64, keyPrefix, owner string) *UploadRequest {
+	if method == "" {
+		method = UploadMethodPut
+	}
+	return &UploadRequest{
+		FileName:    fileName,
+		ContentType: contentType,
+		Size:        size,
+		Method:      strings.ToUpper(method),
+		MaxBytes:    maxBytes,
+		Key:         GenerateUploadKey(keyPrefix, fileName),
+		Owner:       owner,
+	}
+}
+
+func (r *UploadRequest) Validate() error {
+	if r.FileName == "" || r.ContentType == "" {
+		return fmt.Errorf("FileName and ContentType are required")
+	}
+	if !IsUploadContentType(r.ContentType) {
+		return fmt.Errorf("ContentType %s is not a supported media type", r.ContentType)
+	}
+	if r.Method != UploadMethodPut && r.Method != UploadMethodPost {
+		return fmt.Errorf("Method must be PUT or POST")
+	}
+	if r.Method == UploadMethodPut && r.Size <= 0 {
+		return fmt.Errorf("Size is required for PUT uploads")
+	}
+	if r.Size < 0 || r.Size > r.MaxBytes {
+		return fmt.Errorf("Size must be between 1 and %d bytes", r.MaxBytes)
+	}
+	return nil
+}
+
+// GenerateUploadKey アップロード先のキーを生成します。ファイル名は拡張子のみを使います
+func GenerateUploadKey(keyPrefix, fileName string) string {
+	return path.Join(keyPrefix, uuid.New().String()+strings.ToLower(path.Ext(fileName)))
+}
+
+// PresignedUpload 署名付きのアップロード先を表すドメインモデル
+type PresignedUpload struct {
+	Method    string
+	URL       string
+	Key       string
+	Headers   map[string]string // PUTの場合に送る必要のあるヘッダー
+	Fields    map[string]string // POSTの場合にフォームに含めるフィールド
+	ExpiresAt time.Time
+}
+
+// IssuedUpload 署名付きURLを発行したアップロード先を表すドメインモデル。
+// アップロードの確認と、制約を満たさないオブジェクトの削除は、発行したキーに対してだけ行います
+type IssuedUpload struct {
+	Key       string
+	Owner     string    // 発行を依頼した利用者 (認証を使わない場合は空)
+	TenantID  string    // 発行を依頼したテナント (テナントを使わない場合は空)
+	ExpiresAt time.Time // 確認を受け付ける期限
+}
+
+// NewIssuedUpload 新しいIssuedUploadを作成するファクトリ関数
+func NewIssuedUpload(key, owner, tenantID string, expiresAt time.Time) *IssuedUpload {
+	return &IssuedUpload{
+		Key:       key,
+		Owner:     owner,
+		TenantID:  tenantID,
+		ExpiresAt: expiresAt,
+	}
+}
+
+// IsExpired 確認を受け付ける期限を過ぎたかどうかを返します
+func (u *IssuedUpload) IsExpired(now time.Time) bool {
+	return !now.Before(u.ExpiresAt)
+}
+
+// IssuedTo 指定した利用者とテナントに発行したアップロード先かどうかを返します
+func (u *IssuedUpload) IssuedTo(owner, tenantID string) bool {
+	return u.Owner == owner && u.TenantID == tenantID
+}
+
+// S3ObjectInfo S3オブジェクトのメタデータを表すドメインモデル
+type S3ObjectInfo struct {
+	BucketName   string
+	Key          string
+	Size         int64
+	ContentType  string
+	LastModified time.Time
+	Metadata     map[string]string // ユーザー定義メタデータ (HeadObjectで取得した場合のみ)
+}
+
+// Owner オブジェクトをアップロードした呼び出し元を返します。記録されていない場合は空です
+func (o *S3ObjectInfo) Owner() string {
+	return o.Metadata[MediaOwnerMetadataKey]
+}
+
+// URI s3:// 形式のURIを返します
+func (o *S3ObjectInfo) URI() string {
+	return fmt.Sprintf("s3://%s/%s", o.BucketName, o.Key)
+}
+
+// ParseS3URI s3://bucket/key 形式のURIをバケット名とキーに分けます
+func ParseS3URI(uri string) (string, string, bool) {
+	if !strings.HasPrefix(uri, "s3://") {
+		return "", "", false
+	}
+	bucketName, key, found := strings.Cut(strings.TrimPrefix(uri, "s3://"), "/")
+	if !found || bucketName == "" || key == "" {
+		return "", "", false
+	}
+	return bucketName, key, true
+}
+
+// S3ObjectPage ページ単位で取得したS3オブジェクトの一覧
+type S3ObjectPage struct {
+	Objects               []*S3ObjectInfo
+	NextContinuationToken string // 次のページが無い場合は空
+}
@@ -0,0 +1,191 @@
+package model
+
+import (
+	"cmTranscribe/internal/domain/domainerr"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"github.com/google/uuid"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// S3のマルチパートアップロードの制約
+const (
+	MinUploadPartSize = 5 * 1024 * 1024 // 最後のパート以外の最小サイズ
+	MaxUploadParts    = 10000           // パート番号の上限
+)
+
+// 再開可能なアップロードのステータス
+const (
+	UploadSessionInProgress = "IN_PROGRESS"
+	UploadSessionCompleting = "COMPLETING" // パートを結合している間 (新しいパートは受け付けない)
+	UploadSessionCompleted  = "COMPLETED"
+	UploadSessionAborting   = "ABORTING" // マルチパートアップロードを中止している間
+)
+
+// sha256HexPattern 16進数のSHA-256
+var sha256HexPattern = regexp.MustCompile(`^[0-9a-fA-F]{64}$`)
+
+// UploadPart 受け取り済みのパート1件分を表すドメインモデル
+type UploadPart struct {
+	Number     int
+	Size       int64
+	Checksum   string // SHA-256 (16進数)
+	ETag       string // S3が返したETag
+	UploadedAt time.Time
+}
+
+// UploadSession S3のマルチパートアップロードで行う再開可能なアップロードを表すドメインモデル
+type UploadSession struct {
+	ID          string
+	BucketName  string
+	Key         string
+	S3UploadID  string // S3のマルチパートアップロードID
+	FileName    string
+	ContentType string
+	Size        int64 // 宣言されたファイルサイズ (省略可能)
+	MaxBytes    int64 // アップロードできるサイズの上限
+	Status      string
+	Parts       map[int]*UploadPart
+	URI         string // 完了後の s3:// 形式のURI
+	Owner       string // アップロードを開始した呼び出し元 (認証が無効な場合は空)
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+// NewUploadSession 新しいUploadSessionを作成するファクトリ関数。キーはkeyPrefixの下に一意に生成します
+func NewUploadSession(bucketName, keyPrefix, fileName, contentType string, size, maxBytes int64, owner string) *UploadSession {
+	now := time.Now()
+	return &UploadSession{
+		ID:          uuid.New().String(),
+		BucketName:  bucketName,
+		Key:         GenerateUploadKey(keyPrefix, fileName),
+		FileName:    fileName,
+		ContentType: contentType,
+		Size:        size,
+		MaxBytes:    maxBytes,
+		Status:      UploadSessionInProgress,
+		Parts:       make(map[int]*UploadPart),
+		Owner:       owner,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+}
+
+func (s *UploadSession) Validate() error {
+	if s.FileName == "" || s.ContentType == "" {
+		return fmt.Errorf("FileName and ContentType are required")
+	}
+	if !IsUploadContentType(s.ContentType) {
+		return fmt.Errorf("ContentType %s is not a supported media type", s.ContentType)
+	}
+	if s.Size < 0 || s.Size > s.MaxBytes {
+		return fmt.Errorf("Size must be between 0 and %d bytes", s.MaxBytes)
+	}
+	return nil
+}
+
+// Clone 受け取り済みのパートを含めたコピーを返します
+func (s *UploadSession) Clone() *UploadSession {
+	clone := *s
+	clone.Parts = make(map[int]*UploadPart, len(s.Parts))
+	for number, part := range s.Parts {
+		p := *part
+		clone.Parts[number] = &p
+	}
+	return &clone
+}
+
+// SortedParts 受け取り済みのパートをパート番号順に返します
+func (s *UploadSession) SortedParts() []*UploadPart {
+	parts := make([]*UploadPart, 0, len(s.Parts))
+	for _, part := range s.Parts {
+		parts = append(parts, part)
+	}
+	sort.Slice(parts, func(i, j int) bool { return parts[i].Number < parts[j].Number })
+	return parts
+}
+
+// ReceivedBytes 受け取り済みのパートの合計サイズを返します
+func (s *UploadSession) ReceivedBytes() int64 {
+	var total int64
+	for _, part := range s.Parts {
+		total += part.Size
+	}
+	return total
+}
+
+// IsExpired 最後に更新されてから expiry 以上経ったかどうかを返します
+func (s *UploadSession) IsExpired(now time.Time, expiry time.Duration) bool {
+	return !now.Before(s.UpdatedAt.Add(expiry))
+}
+
+// CheckStatus アップロードのステータスが status であることを確認します
+func (s *UploadSession) CheckStatus(status string) error {
+	if s.Status != status {
+		return domainerr.New(domainerr.Conflict, "upload_not_in_progress", "upload %s is already %s", s.ID, strings.ToLower(s.Status))
+	}
+	return nil
+}
+
+// ValidatePart パートを受け取れるかどうかを検証します。同じ番号のパートは置き換えとして扱います
+func (s *UploadSession) ValidatePart(number int, size int64) error {
+	if err := s.CheckStatus(UploadSessionInProgress); err != nil {
+		return err
+	}
+	if number < 1 || number > MaxUploadParts {
+		return domainerr.New(domainerr.Validation, "invalid_part_number", "part number must be between 1 and %d", MaxUploadParts)
+	}
+	if size <= 0 {
+		return domainerr.New(domainerr.Validation, "empty_part", "part must not be empty")
+	}
+	total := s.ReceivedBytes() + size
+	if existing, exists := s.Parts[number]; exists {
+		total -= existing.Size
+	}
+	if total > s.MaxBytes {
+		return domainerr.New(domainerr.Unprocessable, "upload_too_large", "upload would exceed %d bytes", s.MaxBytes)
+	}
+	return nil
+}
+
+// ValidateComplete パートが1番から欠けずに揃っており、マルチパートアップロードを完了できるかどうかを検証します。
+// ステータスは結合を始める前にリポジトリで切り替えて確認します
+func (s *UploadSession) ValidateComplete() error {
+	parts := s.SortedParts()
+	if len(parts) == 0 {
+		return domainerr.New(domainerr.Unprocessable, "upload_parts_missing", "no parts have been uploaded")
+	}
+	for i, part := range parts {
+		if part.Number != i+1 {
+			return domainerr.New(domainerr.Unprocessable, "upload_parts_missing", "part %d is missing", i+1)
+		}
+		if i < len(parts)-1 && part.Size < MinUploadPartSize {
+			return domainerr.New(domainerr.Unprocessable, "upload_part_too_small", "part %d is smaller than %d bytes (only the last part may be smaller)", part.Number, MinUploadPartSize)
+		}
+	}
+	if s.Size > 0 && s.ReceivedBytes() != s.Size {
+		return domainerr.New(domainerr.Unprocessable, "upload_size_mismatch", "received %d bytes but %d bytes were declared", s.ReceivedBytes(), s.Size)
+	}
+	return nil
+}
+
+// ValidateChecksumSHA256 16進数のSHA-256として正しい形式かどうかを検証します
+func ValidateChecksumSHA256(checksum string) error {
+	if !sha256HexPattern.MatchString(checksum) {
+		return domainerr.New(domainerr.Validation, "invalid_checksum", "checksum must be a hex-encoded SHA-256")
+	}
+	return nil
+}
+
+// ChecksumBase64 16進数のSHA-256を、S3のチェックサムに指定するBase64に変換します。形式が正しくない場合は空文字を返します
+func ChecksumBase64(checksum string) string {
+	sum, err := hex.DecodeString(checksum)
+	if err != nil {
+		return ""
+	}
+	return base64.StdEncoding.EncodeToString(sum)
+}
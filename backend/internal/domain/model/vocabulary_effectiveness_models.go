@@ -0,0 +1,113 @@
+package model
+
+import "strings"
+
+// maxMatchTokens 1つの語彙として照合する連続した単語数の上限
+const maxMatchTokens = 8
+
+// EntryEffectiveness 語彙1件分が文字起こし結果に出現した状況を表すドメインモデル
+type EntryEffectiveness struct {
+	Entry         VocabularyEntry
+	Matches       int             // 出現回数
+	ConfidenceSum float64         // 出現したときの信頼度の合計
+	Jobs          map[string]bool // 出現したジョブ
+}
+
+// AverageConfidence 出現したときの平均信頼度 (出現していない場合は0)
+func (e *EntryEffectiveness) AverageConfidence() float64 {
+	if e.Matches == 0 {
+		return 0
+	}
+	return e.ConfidenceSum / float64(e.Matches)
+}
+
+// EffectivenessAnalyzer ボキャブラリの語彙が文字起こし結果にどれだけ出現したかを集計します。
+// 語彙はDisplayAs（指定がある場合）とPhraseの両方の表記で照合します
+type EffectivenessAnalyzer struct {
+	LanguageCode string
+	results      []*EntryEffectiveness
+	terms        map[string]*EntryEffectiveness // 正規化した表記 → 語彙
+}
+
+// NewEffectivenessAnalyzer 新しいEffectivenessAnalyzerを作成するファクトリ関数
+func NewEffectivenessAnalyzer(languageCode string, entries []VocabularyEntry) *EffectivenessAnalyzer {
+	analyzer := &EffectivenessAnalyzer{
+		LanguageCode: languageCode,
+		terms:        make(map[string]*EntryEffectiveness),
+	}
+	for _, entry := range entries {
+		result := &EntryEffectiveness{Entry: entry, Jobs: make(map[string]bool)}
+		analyzer.results = append(analyzer.results, result)
+		for _, term := range []string{entry.DisplayAs, entry.Phrase} {
+			key := analyzer.normalize(strings.ReplaceAll(term, "-", " "))
+			if key == "" {
+				continue
+			}
+			// 同じ表記の語彙が複数ある場合は先に登録された語彙に数える
+			if _, exists := analyzer.terms[key]; !exists {
+				analyzer.terms[key] = result
+			}
+		}
+	}
+	return analyzer
+}
+
+// AddTranscript 文字起こし結果の単語の並びから語彙を探し、最も長く一致したものを数えます
+func (a *EffectivenessAnalyzer) AddTranscript(jobName string, transcript *Transcript) {
+	var items []TranscriptItem
+	for _, item := range transcript.Items {
+		if item.IsPronunciation() {
+			items = append(items, item)
+		}
+	}
+
+	for i := 0; i < len(items); {
+		var matched *EntryEffectiveness
+		length := 0
+		tokens := make([]string, 0, maxMatchTokens)
+		for n := 1; n <= maxMatchTokens && i+n <= len(items); n++ {
+			tokens = append(tokens, items[i+n-1].Content)
+			if result, exists := a.terms[a.normalize(JoinTokens(a.LanguageCode, tokens))]; exists {
+				matched, length = result, n
+			}
+		}
+		if matched == nil {
+			i++
+			continue
+		}
+
+		sum := 0.0
+		for _, item := range items[i : i+length] {
+			sum += item.Confidence
+		}
+		matched.Matches++
+		matched.ConfidenceSum += sum / float64(length)
+		matched.Jobs[jobName] = true
+		i += length
+	}
+}
+
+// Results 語彙ごとの集計結果をボキャブラリの並び順で返します
+func (a *EffectivenessAnalyzer) Results() []*EntryEffectiveness {
+	return a.results
+}
+
+// NeverMatched 一度も出現しなかった語彙を返します
+func (a *EffectivenessAnalyzer) NeverMatched() []*EntryEffectiveness {
+	var results []*EntryEffectiveness
+	for _, result := range a.results {
+		if result.Matches == 0 {
+			results = append(results, result)
+		}
+	}
+	return results
+}
+
+// normalize 照合用に表記をそろえます（大文字小文字を区別せず、空白を入れない言語では空白を取り除く）
+func (a *EffectivenessAnalyzer) normalize(text string) string {
+	fields := strings.Fields(strings.ToLower(text))
+	if IsUnspacedLanguage(a.LanguageCode) {
+		return strings.Join(fields, "")
+	}
+	return strings.Join(fields, " ")
+}
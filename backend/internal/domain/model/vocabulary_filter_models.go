@@ -0,0 +1,67 @@
+package model
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// 語彙フィルタの適用方法 (Amazon TranscribeのVocabularyFilterMethodと同じ値)
+const (
+	VocabularyFilterMethodMask   = "mask"
+	VocabularyFilterMethodRemove = "remove"
+	VocabularyFilterMethodTag    = "tag"
+)
+
+// VocabularyFilter 語彙フィルタを表すドメインモデル
+type VocabularyFilter struct {
+	FilterName   string // フィルタの名前
+	LanguageCode string // 言語コード (作成時のみ使用)
+	FileUri      string // 単語リストがあるURI
+}
+
+// NewVocabularyFilter 新しいVocabularyFilterを作成するファクトリ関数
+func NewVocabularyFilter(name, language, fileUri string) *VocabularyFilter {
+	return &VocabularyFilter{
+		FilterName:   name,
+		LanguageCode: language,
+		FileUri:      fileUri,
+	}
+}
+
+func (f *VocabularyFilter) Validate() error {
+	if f.FilterName == "" || f.FileUri == "" {
+		return fmt.Errorf("FilterName and FileUri are required")
+	}
+	return ValidateResourceName(f.FilterName)
+}
+
+// VocabularyFilterResponse 語彙フィルタの返却値を表すドメインモデル
+type VocabularyFilterResponse struct {
+	FilterName       string    // フィルタの名前
+	LanguageCode     string    // 言語コード
+	DownloadUri      string    // 単語リストのダウンロードURI (一覧取得時は空)
+	LastModifiedTime time.Time // 最終更新日時
+}
+
+// ConvertWordsToContent 単語リストを1行1単語のファイル内容に変換する
+func ConvertWordsToContent(words []string) [][]string {
+	var content [][]string
+	for _, word := range words {
+		word = strings.TrimSpace(word)
+		if word == "" {
+			continue
+		}
+		content = append(content, []string{word})
+	}
+	return content
+}
+
+// IsValidVocabularyFilterMethod 語彙フィルタの適用方法として有効かどうかを返します
+func IsValidVocabularyFilterMethod(method string) bool {
+	switch method {
+	case VocabularyFilterMethodMask, VocabularyFilterMethodRemove, VocabularyFilterMethodTag:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,124 @@
+package model
+
+import (
+	"cmTranscribe/internal/app/dto"
+	"cmTranscribe/internal/domain/domainerr"
+	"fmt"
+	"strings"
+)
+
+// 語彙の部分更新の操作
+const (
+	VocabularyOpAdd    = "add"
+	VocabularyOpRemove = "remove"
+	VocabularyOpModify = "modify"
+)
+
+// VocabularyOperation Phraseをキーとした語彙1件分の部分更新操作を表すドメインモデル
+type VocabularyOperation struct {
+	Op     string
+	Phrase string          // 対象のPhrase（addの場合はEntry.Phraseと同じ）
+	Entry  VocabularyEntry // add, modifyの場合の新しい内容
+}
+
+// NewVocabularyOperations は[]dto.VocabularyOperationを[]VocabularyOperationに変換する
+func NewVocabularyOperations(operations []dto.VocabularyOperation) []VocabularyOperation {
+	result := make([]VocabularyOperation, 0, len(operations))
+	for _, operation := range operations {
+		op := VocabularyOperation{
+			Op:     operation.Op,
+			Phrase: operation.Phrase,
+		}
+		if operation.Vocabulary != nil {
+			op.Entry = NewVocabularyEntries([]dto.Vocabulary{*operation.Vocabulary})[0]
+		}
+		// addはPhraseの指定を省略できる
+		if op.Op == VocabularyOpAdd && op.Phrase == "" {
+			op.Phrase = op.Entry.Phrase
+		}
+		result = append(result, op)
+	}
+	return result
+}
+
+func (o *VocabularyOperation) Validate() error {
+	switch o.Op {
+	case VocabularyOpAdd, VocabularyOpModify:
+		if o.Entry.Phrase == "" {
+			return fmt.Errorf("vocabulary.phrase is required for %s", o.Op)
+		}
+	case VocabularyOpRemove:
+	default:
+		return fmt.Errorf("unsupported op: %q", o.Op)
+	}
+	if o.Phrase == "" {
+		return fmt.Errorf("phrase is required for %s", o.Op)
+	}
+	return nil
+}
+
+// MergeVocabularyEntries 現在の語彙リストに部分更新操作を適用します。
+// 存在しないPhraseの変更・削除、既存Phraseの追加、同じPhraseへの複数操作は競合として扱います。
+func MergeVocabularyEntries(current []VocabularyEntry, operations []VocabularyOperation) ([]VocabularyEntry, error) {
+	merged := make([]VocabularyEntry, len(current))
+	copy(merged, current)
+	index := make(map[string]int, len(merged))
+	for i, entry := range merged {
+		index[entry.Phrase] = i
+	}
+	removed := make(map[string]bool)
+	touched := make(map[string]bool)
+
+	var conflicts []string
+	for _, operation := range operations {
+		if touched[operation.Phrase] {
+			conflicts = append(conflicts, fmt.Sprintf("%q is targeted by multiple operations", operation.Phrase))
+			continue
+		}
+		touched[operation.Phrase] = true
+
+		i, exists := index[operation.Phrase]
+		switch operation.Op {
+		case VocabularyOpAdd:
+			if exists {
+				conflicts = append(conflicts, fmt.Sprintf("%q already exists", operation.Phrase))
+				continue
+			}
+			index[operation.Phrase] = len(merged)
+			merged = append(merged, operation.Entry)
+		case VocabularyOpRemove:
+			if !exists {
+				conflicts = append(conflicts, fmt.Sprintf("%q does not exist", operation.Phrase))
+				continue
+			}
+			removed[operation.Phrase] = true
+		case VocabularyOpModify:
+			if !exists {
+				conflicts = append(conflicts, fmt.Sprintf("%q does not exist", operation.Phrase))
+				continue
+			}
+			// Phraseの変更先が既存の語彙と重複しないか確認
+			if renamed := operation.Entry.Phrase; renamed != operation.Phrase {
+				if _, taken := index[renamed]; taken || touched[renamed] {
+					conflicts = append(conflicts, fmt.Sprintf("%q cannot be renamed to existing phrase %q", operation.Phrase, renamed))
+					continue
+				}
+				touched[renamed] = true
+				index[renamed] = i
+			}
+			merged[i] = operation.Entry
+		}
+	}
+	if len(conflicts) > 0 {
+		return nil, domainerr.New(domainerr.Conflict, "vocabulary_patch_conflict", "%s", strings.Join(conflicts, "; "))
+	}
+
+	result := make([]VocabularyEntry, 0, len(merged))
+	for _, entry := range merged {
+		if removed[entry.Phrase] {
+			continue
+		}
+		result = append(result, entry)
+	}
+	return result, nil
+}
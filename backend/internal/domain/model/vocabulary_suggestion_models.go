@@ -0,0 +1,223 @@
+package model
+
+import (
+	"fmt"
+	"github.com/google/uuid"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// TranscriptCorrection レビュアーが文字起こし結果に対して行った修正を表すドメインモデル
+type TranscriptCorrection struct {
+	ID        string
+	JobName   string // 修正したジョブ
+	Original  string // 誤って認識された文字列
+	Corrected string // 正しい文字列
+	Reviewer  string
+	CreatedAt time.Time
+}
+
+// NewTranscriptCorrection 新しいTranscriptCorrectionを作成するファクトリ関数
+func NewTranscriptCorrection(jobName, original, corrected, reviewer string) *TranscriptCorrection {
+	return &TranscriptCorrection{
+		ID:        uuid.New().String(),
+		JobName:   jobName,
+		Original:  original,
+		Corrected: corrected,
+		Reviewer:  reviewer,
+		CreatedAt: time.Now(),
+	}
+}
+
+func (c *TranscriptCorrection) Validate() error {
+	if c.JobName == "" || c.Original == "" || c.Corrected == "" {
+		return fmt.Errorf("JobName, Original and Corrected are required")
+	}
+	return nil
+}
+
+// IsProperNoun 修正後の文字列が固有名詞らしいかどうかを判定します。
+// 大文字で始まる単語、またはカタカナ・英字の表記を固有名詞とみなします。
+func (c *TranscriptCorrection) IsProperNoun() bool {
+	for _, r := range c.Corrected {
+		switch {
+		case unicode.IsUpper(r), unicode.In(r, unicode.Katakana):
+			return true
+		case unicode.IsLetter(r):
+			return false
+		}
+	}
+	return false
+}
+
+// 候補の出典
+const (
+	SuggestionSourceLowConfidence = "low_confidence"
+	SuggestionSourceCorrection    = "correction"
+)
+
+// correctionWeight レビュアーの修正1件を低信頼度の出現何回分として扱うか
+const correctionWeight = 3
+
+// VocabularySuggestion ボキャブラリへの追加候補を表すドメインモデル
+type VocabularySuggestion struct {
+	Phrase          string
+	DisplayAs       string
+	Occurrences     int
+	ConfidenceSum   float64
+	Corrections     int
+	Jobs            map[string]bool
+	Sources         map[string]bool
+	MisrecognizedAs map[string]int // 修正前の文字列ごとの件数
+}
+
+// AverageConfidence 低信頼度で出現したときの平均信頼度 (修正のみの場合は0)
+func (s *VocabularySuggestion) AverageConfidence() float64 {
+	if s.Occurrences == 0 {
+		return 0
+	}
+	return s.ConfidenceSum / float64(s.Occurrences)
+}
+
+// Score 出現頻度と信頼度の低さから算出するランキング用のスコア
+func (s *VocabularySuggestion) Score() float64 {
+	return float64(s.Occurrences)*(1-s.AverageConfidence()) + float64(s.Corrections*correctionWeight)
+}
+
+// SuggestionCollector 文字起こし結果と修正履歴からボキャブラリの候補を集計します
+type SuggestionCollector struct {
+	LanguageCode  string
+	MaxConfidence float64 // この値未満の信頼度の単語を候補とする
+	MaxRunLength  int     // 連続する低信頼度の単語を1つの候補にまとめる最大数
+	suggestions   map[string]*VocabularySuggestion
+}
+
+// NewSuggestionCollector 新しいSuggestionCollectorを作成するファクトリ関数
+func NewSuggestionCollector(languageCode string, maxConfidence float64) *SuggestionCollector {
+	return &SuggestionCollector{
+		LanguageCode:  languageCode,
+		MaxConfidence: maxConfidence,
+		MaxRunLength:  4,
+		suggestions:   make(map[string]*VocabularySuggestion),
+	}
+}
+
+// AddTranscript 文字起こし結果の低信頼度の単語（連続する場合はまとめた句）を候補に加えます
+func (c *SuggestionCollector) AddTranscript(jobName string, transcript *Transcript) {
+	var run []TranscriptItem
+	flush := func() {
+		if len(run) == 0 {
+			return
+		}
+		tokens := make([]string, 0, len(run))
+		sum := 0.0
+		for _, item := range run {
+			tokens = append(tokens, item.Content)
+			sum += item.Confidence
+		}
+		suggestion := c.get(JoinTokens(c.LanguageCode, tokens))
+		if suggestion != nil {
+			suggestion.Occurrences++
+			suggestion.ConfidenceSum += sum / float64(len(run))
+			suggestion.Jobs[jobName] = true
+			suggestion.Sources[SuggestionSourceLowConfidence] = true
+		}
+		run = nil
+	}
+
+	for _, item := range transcript.Items {
+		if !item.IsPronunciation() || item.Confidence >= c.MaxConfidence || len(run) >= c.MaxRunLength {
+			flush()
+		}
+		if item.IsPronunciation() && item.Confidence < c.MaxConfidence {
+			run = append(run, item)
+		}
+	}
+	flush()
+}
+
+// AddCorrection レビュアーが固有名詞に修正した文字列を候補に加えます
+func (c *SuggestionCollector) AddCorrection(correction *TranscriptCorrection) {
+	if !correction.IsProperNoun() {
+		return
+	}
+	suggestion := c.get(correction.Corrected)
+	if suggestion == nil {
+		return
+	}
+	suggestion.Corrections++
+	suggestion.Jobs[correction.JobName] = true
+	suggestion.Sources[SuggestionSourceCorrection] = true
+	suggestion.MisrecognizedAs[correction.Original]++
+	// 記号などを取り除いたPhraseと異なる場合は、修正後の表記をDisplayAsにする
+	if suggestion.Phrase != correction.Corrected {
+		suggestion.DisplayAs = correction.Corrected
+	}
+}
+
+// Ranked 既存の語彙を除いた候補をスコアの高い順に返します
+func (c *SuggestionCollector) Ranked(existing map[string]bool, limit int) []*VocabularySuggestion {
+	result := make([]*VocabularySuggestion, 0, len(c.suggestions))
+	for phrase, suggestion := range c.suggestions {
+		if existing[phrase] {
+			continue
+		}
+		result = append(result, suggestion)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Score() != result[j].Score() {
+			return result[i].Score() > result[j].Score()
+		}
+		return result[i].Phrase < result[j].Phrase
+	})
+	if limit > 0 && len(result) > limit {
+		result = result[:limit]
+	}
+	return result
+}
+
+// get Phraseに対応する候補を取得（なければ作成）します。候補にできない文字列の場合はnilを返します
+func (c *SuggestionCollector) get(text string) *VocabularySuggestion {
+	phrase := NormalizePhrase(text)
+	if !isSuggestablePhrase(phrase) {
+		return nil
+	}
+	suggestion, exists := c.suggestions[phrase]
+	if !exists {
+		suggestion = &VocabularySuggestion{
+			Phrase:          phrase,
+			Jobs:            make(map[string]bool),
+			Sources:         make(map[string]bool),
+			MisrecognizedAs: make(map[string]int),
+		}
+		c.suggestions[phrase] = suggestion
+	}
+	return suggestion
+}
+
+// NormalizePhrase Amazon TranscribeのPhraseとして使えるよう、空白をハイフンに置き換え、サポートされない文字を取り除きます
+func NormalizePhrase(text string) string {
+	var builder strings.Builder
+	for _, r := range strings.Join(strings.Fields(text), "-") {
+		if unicode.IsLetter(r) || unicode.IsNumber(r) || r == '-' || r == '\'' || r == '.' {
+			builder.WriteRune(r)
+		}
+	}
+	return strings.Trim(builder.String(), "-")
+}
+
+// isSuggestablePhrase 1文字だけの語や数字のみの語は候補にしない
+func isSuggestablePhrase(phrase string) bool {
+	runes := []rune(phrase)
+	if len(runes) < 2 {
+		return false
+	}
+	for _, r := range runes {
+		if unicode.IsLetter(r) {
+			return true
+		}
+	}
+	return false
+}
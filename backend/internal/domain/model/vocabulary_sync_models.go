@@ -0,0 +1,231 @@
+package model
+
+import (
+	"bytes"
+	"cmTranscribe/internal/shared/constant"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// frontMatterDelimiter ボキャブラリ定義ファイルのフロントマターの区切り行
+const frontMatterDelimiter = "---"
+
+// VocabularyDefinition ファイルで宣言されたボキャブラリのあるべき状態を表すドメインモデル
+type VocabularyDefinition struct {
+	Name         string
+	LanguageCode string
+	Source       string // 定義を読み込んだファイル
+	Entries      []VocabularyEntry
+}
+
+func (d *VocabularyDefinition) Validate() error {
+	if d.Name == "" || d.LanguageCode == "" {
+		return fmt.Errorf("name and language are required in the front matter of %s", d.Source)
+	}
+	if len(d.Entries) == 0 {
+		return fmt.Errorf("%s has no vocabulary entries", d.Source)
+	}
+	return nil
+}
+
+// ParseVocabularyDefinition フロントマター付きのTSVファイルをパースします。
+//
+//	---
+//	name: MyVocabulary01
+//	language: ja-JP
+//	---
+//	Phrase	IPA	SoundsLike	DisplayAs
+//	...
+func ParseVocabularyDefinition(source string, content []byte) (*VocabularyDefinition, error) {
+	definition := &VocabularyDefinition{Source: source}
+
+	rest := content
+	readLine := func() (string, bool) {
+		if len(rest) == 0 {
+			return "", false
+		}
+		var line []byte
+		if i := bytes.IndexByte(rest, '\n'); i >= 0 {
+			line, rest = rest[:i], rest[i+1:]
+		} else {
+			line, rest = rest, nil
+		}
+		return strings.TrimSpace(string(line)), true
+	}
+
+	if line, ok := readLine(); !ok || line != frontMatterDelimiter {
+		return nil, fmt.Errorf("%s must start with a front matter", source)
+	}
+	closed := false
+	for line, ok := readLine(); ok; line, ok = readLine() {
+		if line == frontMatterDelimiter {
+			closed = true
+			break
+		}
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		key, value, found := strings.Cut(line, ":")
+		if !found {
+			return nil, fmt.Errorf("invalid front matter line in %s: %q", source, line)
+		}
+		switch strings.TrimSpace(key) {
+		case "name":
+			definition.Name = strings.TrimSpace(value)
+		case "language", "language_code":
+			definition.LanguageCode = strings.TrimSpace(value)
+		default:
+			return nil, fmt.Errorf("unknown front matter key in %s: %q", source, key)
+		}
+	}
+	if !closed {
+		return nil, fmt.Errorf("front matter of %s is not closed", source)
+	}
+
+	entries, err := ParseVocabularyEntries(bytes.NewReader(rest))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", source, err)
+	}
+	definition.Entries = entries
+	return definition, nil
+}
+
+// ParseVocabularyEntries Amazon Transcribeの語彙リスト (テーブル形式のTSV、またはヘッダーの無い1行1フレーズのリスト) をパースします
+func ParseVocabularyEntries(r io.Reader) ([]VocabularyEntry, error) {
+	reader := csv.NewReader(r)
+	reader.Comma = '\t'
+	// フレーズリスト形式のファイルはヘッダーが無く、1行1フレーズになる
+	reader.FieldsPerRecord = -1
+	// 語彙にダブルクォートが含まれていてもそのまま扱う
+	reader.LazyQuotes = true
+
+	var entries []VocabularyEntry
+	for line := 0; ; line++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse CSV: %w", err)
+		}
+		// ヘッダーをスキップ
+		if line == 0 && record[0] == constant.VocabularyCsvHeader[0] {
+			continue
+		}
+
+		// 足りない列は空文字として扱う
+		for len(record) < len(constant.VocabularyCsvHeader) {
+			record = append(record, "")
+		}
+		entries = append(entries, VocabularyEntry{
+			Phrase:     record[0],
+			IPA:        record[1],
+			SoundsLike: record[2],
+			DisplayAs:  record[3],
+		})
+	}
+	return entries, nil
+}
+
+// 同期で行う操作
+const (
+	SyncActionCreate = "create"
+	SyncActionUpdate = "update"
+	SyncActionDelete = "delete"
+)
+
+// VocabularySyncAction 同期で行う1件分の操作を表すドメインモデル
+type VocabularySyncAction struct {
+	Action       string
+	Name         string
+	LanguageCode string                // 作成・更新後の言語コード (削除の場合は現在の言語コード)
+	Definition   *VocabularyDefinition // 作成・更新の場合のあるべき状態
+	Diff         []VocabularyEntryDiff // 更新の場合の語彙の差分
+}
+
+// VocabularySyncPlan 定義ファイルとAmazon Transcribeの差分から作成した同期の計画
+type VocabularySyncPlan struct {
+	Actions   []VocabularySyncAction
+	Unchanged []string // 差分の無いボキャブラリ
+}
+
+// IsEmpty 行う操作が無いかどうかを返します
+func (p *VocabularySyncPlan) IsEmpty() bool {
+	return len(p.Actions) == 0
+}
+
+// Count 指定した操作の件数を返します
+func (p *VocabularySyncPlan) Count(action string) int {
+	count := 0
+	for _, a := range p.Actions {
+		if a.Action == action {
+			count++
+		}
+	}
+	return count
+}
+
+// RemoteVocabulary Amazon Transcribeに登録されているボキャブラリの現在の状態
+type RemoteVocabulary struct {
+	Name         string
+	LanguageCode string
+	Entries      []VocabularyEntry
+}
+
+// PlanVocabularySync 定義とAmazon Transcribeの現在の状態を比較して同期の計画を作成します。
+// managedPrefixを指定した場合、定義の名前はその接頭辞で始まる必要があります。
+// pruneがtrueの場合、managedPrefixで始まる名前のうち定義の無いボキャブラリを削除する操作を含めます
+func PlanVocabularySync(definitions []*VocabularyDefinition, remotes []*RemoteVocabulary, prune bool, managedPrefix string) (*VocabularySyncPlan, error) {
+	// 接頭辞の無い削除は同じアカウントの他のボキャブラリをすべて削除してしまう
+	if prune && managedPrefix == "" {
+		return nil, fmt.Errorf("prune requires a managed prefix")
+	}
+	defined := make(map[string]*VocabularyDefinition, len(definitions))
+	for _, definition := range definitions {
+		if !strings.HasPrefix(definition.Name, managedPrefix) {
+			return nil, fmt.Errorf("vocabulary %s in %s does not start with the managed prefix %q", definition.Name, definition.Source, managedPrefix)
+		}
+		if other, exists := defined[definition.Name]; exists {
+			return nil, fmt.Errorf("vocabulary %s is defined in both %s and %s", definition.Name, other.Source, definition.Source)
+		}
+		defined[definition.Name] = definition
+	}
+	current := make(map[string]*RemoteVocabulary, len(remotes))
+	for _, remote := range remotes {
+		current[remote.Name] = remote
+	}
+
+	plan := &VocabularySyncPlan{}
+	names := make([]string, 0, len(defined))
+	for name := range defined {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		definition := defined[name]
+		remote, exists := current[name]
+		if !exists {
+			plan.Actions = append(plan.Actions, VocabularySyncAction{Action: SyncActionCreate, Name: name, LanguageCode: definition.LanguageCode, Definition: definition})
+			continue
+		}
+		diff := DiffVocabularyEntries(remote.Entries, definition.Entries)
+		if len(diff) == 0 && remote.LanguageCode == definition.LanguageCode {
+			plan.Unchanged = append(plan.Unchanged, name)
+			continue
+		}
+		plan.Actions = append(plan.Actions, VocabularySyncAction{Action: SyncActionUpdate, Name: name, LanguageCode: definition.LanguageCode, Definition: definition, Diff: diff})
+	}
+
+	if prune {
+		sort.Slice(remotes, func(i, j int) bool { return remotes[i].Name < remotes[j].Name })
+		for _, remote := range remotes {
+			if _, exists := defined[remote.Name]; !exists && strings.HasPrefix(remote.Name, managedPrefix) {
+				plan.Actions = append(plan.Actions, VocabularySyncAction{Action: SyncActionDelete, Name: remote.Name, LanguageCode: remote.LanguageCode})
+			}
+		}
+	}
+	return plan, nil
+}
@@ -0,0 +1,157 @@
+package model
+
+import (
+	"cmTranscribe/internal/app/dto"
+	"fmt"
+	"github.com/google/uuid"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// VocabularyEntry カスタムボキャブラリの1行（Phrase, IPA, SoundsLike, DisplayAs）を表すドメインモデル
+type VocabularyEntry struct {
+	Phrase     string
+	IPA        string
+	SoundsLike string
+	DisplayAs  string
+}
+
+// NewVocabularyEntries は[]dto.Vocabularyを[]VocabularyEntryに変換する
+func NewVocabularyEntries(vocabularies []dto.Vocabulary) []VocabularyEntry {
+	entries := make([]VocabularyEntry, 0, len(vocabularies))
+	for _, vocabulary := range vocabularies {
+		entries = append(entries, VocabularyEntry{
+			Phrase:     vocabulary.Phrase,
+			IPA:        vocabulary.IPA,
+			SoundsLike: vocabulary.SoundsLike,
+			DisplayAs:  vocabulary.DisplayAs,
+		})
+	}
+	return entries
+}
+
+// VocabularyVersion アップロードされたボキャブラリファイル1つ分の履歴を表すドメインモデル
+type VocabularyVersion struct {
+	ID             string            // ユニークな識別子
+	VocabularyName string            // ボキャブラリーの名前
+	Version        int               // 1から始まる連番
+	LanguageCode   string            // 言語コード
+	FileUri        string            // アップロードされたTSVのS3 URI (フレーズリスト形式の場合は空)
+	Author         string            // 変更を行ったユーザー
+	EntryCount     int               // 語彙数
+	EntryDelta     int               // 直前のバージョンからの語彙数の増減
+	RolledBackFrom int               // ロールバックで作成された場合、その元となったバージョン
+	Entries        []VocabularyEntry // アップロード時点の語彙のスナップショット
+	CreatedAt      time.Time
+}
+
+// NewVocabularyVersion 新しいVocabularyVersionを作成するファクトリ関数
+func NewVocabularyVersion(name, language, fileUri, author string, entries []VocabularyEntry) *VocabularyVersion {
+	return &VocabularyVersion{
+		ID:             uuid.New().String(),
+		VocabularyName: name,
+		LanguageCode:   language,
+		FileUri:        fileUri,
+		Author:         author,
+		EntryCount:     len(entries),
+		Entries:        entries,
+		CreatedAt:      time.Now(),
+	}
+}
+
+func (v *VocabularyVersion) Validate() error {
+	if v.VocabularyName == "" || v.LanguageCode == "" {
+		return fmt.Errorf("VocabularyName and LanguageCode are required")
+	}
+	if v.FileUri == "" && len(v.Entries) == 0 {
+		return fmt.Errorf("either FileUri or Entries is required")
+	}
+	return nil
+}
+
+// Mode 登録形式 (table または list) を返します
+func (v *VocabularyVersion) Mode() string {
+	if v.FileUri == "" {
+		return VocabularyModePhraseList
+	}
+	return VocabularyModeTable
+}
+
+// FollowOn 直前のバージョンを元にバージョン番号と語彙数の増減を設定します
+func (v *VocabularyVersion) FollowOn(previous *VocabularyVersion) {
+	if previous == nil {
+		v.Version = 1
+		v.EntryDelta = v.EntryCount
+		return
+	}
+	v.Version = previous.Version + 1
+	v.EntryDelta = v.EntryCount - previous.EntryCount
+}
+
+// VocabularyETag バージョン番号からETagを返します。バージョン履歴が無い場合のバージョン番号は0です
+func VocabularyETag(version int) string {
+	return strconv.Quote(strconv.Itoa(version))
+}
+
+// MatchesVocabularyETag If-Match ヘッダーの値が現在のバージョンと一致するかどうかを返します。
+// "*" はどのバージョンにも一致し、カンマで区切った複数のETagはいずれかが一致すれば一致とします
+func MatchesVocabularyETag(ifMatch string, version int) bool {
+	current := VocabularyETag(version)
+	for _, tag := range strings.Split(ifMatch, ",") {
+		tag = strings.TrimSpace(tag)
+		if tag == "*" || strings.TrimPrefix(tag, "W/") == current {
+			return true
+		}
+	}
+	return false
+}
+
+// 差分の種類
+const (
+	EntryChangeAdded    = "added"
+	EntryChangeRemoved  = "removed"
+	EntryChangeModified = "modified"
+)
+
+// VocabularyEntryDiff 2つのバージョン間での語彙1件分の差分を表すドメインモデル
+type VocabularyEntryDiff struct {
+	Phrase string
+	Change string
+	Before *VocabularyEntry
+	After  *VocabularyEntry
+}
+
+// DiffVocabularyEntries Phraseをキーとして2つの語彙リストの差分を計算します
+func DiffVocabularyEntries(from, to []VocabularyEntry) []VocabularyEntryDiff {
+	before := make(map[string]VocabularyEntry, len(from))
+	for _, entry := range from {
+		before[entry.Phrase] = entry
+	}
+	after := make(map[string]VocabularyEntry, len(to))
+	for _, entry := range to {
+		after[entry.Phrase] = entry
+	}
+
+	var diffs []VocabularyEntryDiff
+	// 削除・変更された語彙（fromの並び順を維持）
+	for _, entry := range from {
+		oldEntry := entry
+		newEntry, exists := after[entry.Phrase]
+		if !exists {
+			diffs = append(diffs, VocabularyEntryDiff{Phrase: entry.Phrase, Change: EntryChangeRemoved, Before: &oldEntry})
+			continue
+		}
+		if newEntry != oldEntry {
+			diffs = append(diffs, VocabularyEntryDiff{Phrase: entry.Phrase, Change: EntryChangeModified, Before: &oldEntry, After: &newEntry})
+		}
+	}
+	// 追加された語彙（toの並び順を維持）
+	for _, entry := range to {
+		newEntry := entry
+		if _, exists := before[entry.Phrase]; !exists {
+			diffs = append(diffs, VocabularyEntryDiff{Phrase: entry.Phrase, Change: EntryChangeAdded, After: &newEntry})
+		}
+	}
+	return diffs
+}
@@ -0,0 +1,18 @@
+package repository
+
+import (
+	"cmTranscribe/internal/domain/model"
+	"time"
+)
+
+// CustomVocabularyRepository カスタムボキャブラリのリポジトリインターフェースです。
+// 取得したボキャブラリは保存されているものの複製で、変更は Save または ApplyState で反映します
+type CustomVocabularyRepository interface {
+	Save(vocabulary *model.CustomVocabularyDB) error
+	FindByName(name string) (*model.CustomVocabularyDB, error)
+	FindAll() ([]*model.CustomVocabularyDB, error)
+	// ApplyState 作成・更新を依頼した日時が updatedAt のままの場合だけ、Amazon Transcribeから取得した状態を反映します。
+	// その後に再び更新を依頼された場合は反映せずに false を返します
+	ApplyState(name string, updatedAt time.Time, state, failureReason string) (bool, error)
+	Delete(name string) error
+}
@@ -0,0 +1,10 @@
+package repository
+
+import "cmTranscribe/internal/domain/model"
+
+// IssuedUploadRepository 署名付きURLを発行したアップロード先のリポジトリインターフェースです。
+type IssuedUploadRepository interface {
+	Save(upload *model.IssuedUpload) error
+	FindByKey(key string) (*model.IssuedUpload, error)
+	Delete(key string) error
+}
@@ -0,0 +1,9 @@
+package repository
+
+import "cmTranscribe/internal/domain/model"
+
+// TenantRepository テナントのリポジトリインターフェースです。
+type TenantRepository interface {
+	FindByID(id string) (*model.Tenant, error)
+	FindAll() ([]*model.Tenant, error)
+}
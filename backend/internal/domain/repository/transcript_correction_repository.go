@@ -0,0 +1,9 @@
+package repository
+
+import "cmTranscribe/internal/domain/model"
+
+// TranscriptCorrectionRepository 文字起こし結果の修正履歴のリポジトリインターフェースです。
+type TranscriptCorrectionRepository interface {
+	Save(correction *model.TranscriptCorrection) error
+	FindAll() ([]*model.TranscriptCorrection, error)
+}
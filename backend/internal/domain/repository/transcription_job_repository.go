@@ -6,4 +6,6 @@ import "cmTranscribe/internal/domain/model"
 type TranscriptionJobRepository interface {
 	Save(job *model.TranscriptionJobDB) error
 	FindByID(id string) (*model.TranscriptionJobDB, error)
+	FindByMediaFileURI(uri string) ([]*model.TranscriptionJobDB, error)
+	FindAll() ([]*model.TranscriptionJobDB, error)
 }
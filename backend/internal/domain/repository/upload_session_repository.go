@@ -0,0 +1,16 @@
+package repository
+
+import (
+	"cmTranscribe/internal/domain/model"
+	"time"
+)
+
+// UploadSessionRepository 再開可能なアップロードのリポジトリインターフェースです。
+type UploadSessionRepository interface {
+	Save(session *model.UploadSession) error
+	SavePart(id string, part *model.UploadPart) error
+	Transition(id, from, to string) (*model.UploadSession, error)
+	FindByID(id string) (*model.UploadSession, error)
+	FindExpired(now time.Time, expiry time.Duration) ([]*model.UploadSession, error)
+	Delete(id string) error
+}
@@ -0,0 +1,10 @@
+package repository
+
+import "cmTranscribe/internal/domain/model"
+
+// VocabularyVersionRepository カスタムボキャブラリのバージョン履歴のリポジトリインターフェースです。
+type VocabularyVersionRepository interface {
+	Save(version *model.VocabularyVersion) error
+	FindByName(name string) ([]*model.VocabularyVersion, error)
+	FindByNameAndVersion(name string, version int) (*model.VocabularyVersion, error)
+}
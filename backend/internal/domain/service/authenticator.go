@@ -0,0 +1,16 @@
+package service
+
+import (
+	"cmTranscribe/internal/domain/model"
+	"context"
+)
+
+// Authenticator リクエストの認証情報 (APIキーやJWT) を検証し、呼び出し元を返します
+type Authenticator interface {
+	Authenticate(ctx context.Context, credential string) (*model.Principal, error)
+}
+
+// NewAuthenticator ファクトリ関数
+func NewAuthenticator(impl Authenticator) Authenticator {
+	return impl
+}
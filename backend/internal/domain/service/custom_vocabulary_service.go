@@ -10,6 +10,8 @@ type CustomVocabularyService interface {
 	CreateCustomVocabulary(ctx context.Context, vocabulary model.CustomVocabulary) error
 	UpdateCustomVocabulary(ctx context.Context, vocabulary model.CustomVocabulary) error
 	GetCustomVocabularyByName(ctx context.Context, name string) (*model.CustomVocabularyResponse, error)
+	ListCustomVocabularies(ctx context.Context) ([]*model.CustomVocabularyResponse, error)
+	DeleteCustomVocabulary(ctx context.Context, name string) error
 }
 
 // NewCustomVocabularyService ファクトリ関数
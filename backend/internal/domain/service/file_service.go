@@ -2,12 +2,14 @@ package service
 
 import (
 	"cmTranscribe/internal/domain/model"
-	"os"
+	"io"
 )
 
 type FileService interface {
-	CreateCSV(csvFile model.CSVFile) (string, *os.File, error)
-	Cleanup(filePath string) error
+	// EncodeTSV TSVとしてエンコードした内容を順に読み出すio.ReadCloserを返します。読み終わる前にCloseすると書き込みを中断します
+	EncodeTSV(tsvFile model.TSVFile) io.ReadCloser
+	// DigestTSV TSVとしてエンコードした内容のSHA-256を16進数で返します
+	DigestTSV(tsvFile model.TSVFile) (string, error)
 }
 
 // NewFileService ファクトリ関数
@@ -0,0 +1,16 @@
+package service
+
+import (
+	"cmTranscribe/internal/domain/model"
+	"io"
+)
+
+// MediaProber メディアファイルのヘッダーからフォーマット、長さ、サンプルレートなどを読み取ります
+type MediaProber interface {
+	Probe(r io.ReaderAt, size int64) (*model.MediaInfo, error)
+}
+
+// NewMediaProber ファクトリ関数
+func NewMediaProber(impl MediaProber) MediaProber {
+	return impl
+}
@@ -0,0 +1,13 @@
+package service
+
+// ReadingGenerator 語彙のPhraseから読み（SoundsLike）を生成するインターフェース
+type ReadingGenerator interface {
+	// GenerateSoundsLike Phraseの読みをハイフン区切りのカタカナで返します。
+	// overridesに含まれる語（大文字小文字を区別しない）は変換ルールより優先されます。
+	GenerateSoundsLike(phrase string, overrides map[string]string) (string, error)
+}
+
+// NewReadingGenerator ファクトリ関数
+func NewReadingGenerator(impl ReadingGenerator) ReadingGenerator {
+	return impl
+}
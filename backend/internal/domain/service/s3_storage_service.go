@@ -3,12 +3,26 @@ package service
 import (
 	"cmTranscribe/internal/domain/model"
 	"context"
+	"io"
+	"time"
 )
 
 type S3StorageService interface {
 	UploadToS3(ctx context.Context, s3File model.S3File) (string, error)
+	PutObject(ctx context.Context, object model.S3Object) (string, error)
+	PresignUpload(ctx context.Context, bucketName string, upload model.UploadRequest, expires time.Duration) (*model.PresignedUpload, error)
+	HeadObject(ctx context.Context, bucketName, key string) (*model.S3ObjectInfo, error)
+	OpenObjectReader(ctx context.Context, bucketName, key string) (io.ReaderAt, int64, error)
+	ListObjects(ctx context.Context, bucketName, prefix, continuationToken string, maxKeys int) (*model.S3ObjectPage, error)
+	CopyObject(ctx context.Context, bucketName, sourceKey, destinationKey string) error
+	DeleteObject(ctx context.Context, bucketName, key string) error
+	CreateMultipartUpload(ctx context.Context, bucketName, key, contentType string, metadata map[string]string) (string, error)
+	UploadPart(ctx context.Context, session model.UploadSession, part model.UploadPart, body io.Reader) (string, error)
+	CompleteMultipartUpload(ctx context.Context, session model.UploadSession) (string, error)
+	AbortMultipartUpload(ctx context.Context, session model.UploadSession) error
+	PresignDownload(ctx context.Context, bucketName, key string, expires time.Duration) (string, error)
 	GetTranscriptionContent(ctx context.Context, signedURL string) (string, error)
-	GeneratePresignedURL(ctx context.Context, jobName string) (string, error)
+	GeneratePresignedURL(ctx context.Context, bucketName, key string) (string, error)
 }
 
 // NewS3StorageService ファクトリ関数
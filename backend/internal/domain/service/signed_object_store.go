@@ -0,0 +1,20 @@
+package service
+
+import (
+	"cmTranscribe/internal/domain/model"
+	"context"
+	"io"
+	"net/url"
+)
+
+// SignedObjectStore は、S3 を使わずにサーバー自身が署名付きURLでオブジェクトを配信・受け付けるストレージです
+type SignedObjectStore interface {
+	VerifySignedRequest(method string, params url.Values) (*model.SignedObjectRequest, error)
+	OpenObject(ctx context.Context, bucketName, key string) (io.ReadSeekCloser, *model.S3ObjectInfo, error)
+	PutObject(ctx context.Context, object model.S3Object) (string, error)
+}
+
+// NewSignedObjectStore ファクトリ関数
+func NewSignedObjectStore(impl SignedObjectStore) SignedObjectStore {
+	return impl
+}
@@ -7,8 +7,11 @@ import (
 
 type TranscriptionJobService interface {
 	StartTranscriptionJob(ctx context.Context, input *model.TranscriptionJob) (*model.TranscriptionJobStatusResponse, error)
-	GetTranscriptionJobList(ctx context.Context) (*model.TranscriptionJobSummariesResponse, error)
+	// GetTranscriptionJobList 条件に合うジョブの一覧を新しい順に1ページ分返します
+	GetTranscriptionJobList(ctx context.Context, query model.TranscriptionJobListQuery) (*model.TranscriptionJobSummariesResponse, error)
 	GetTranscriptionJob(ctx context.Context, jobName string) (*model.TranscriptionJobResponse, error)
+	// CountActiveTranscriptionJobs アカウントで実行中 (QUEUED または IN_PROGRESS) のジョブの数を返します
+	CountActiveTranscriptionJobs(ctx context.Context) (int, error)
 }
 
 // NewTranscriptionJobService ファクトリ関数
@@ -0,0 +1,20 @@
+package service
+
+import (
+	"cmTranscribe/internal/domain/model"
+	"context"
+)
+
+// VocabularyFilterService 語彙フィルタに関するビジネスロジックを定義するインターフェース
+type VocabularyFilterService interface {
+	CreateVocabularyFilter(ctx context.Context, filter model.VocabularyFilter) error
+	UpdateVocabularyFilter(ctx context.Context, filter model.VocabularyFilter) error
+	GetVocabularyFilter(ctx context.Context, name string) (*model.VocabularyFilterResponse, error)
+	ListVocabularyFilters(ctx context.Context) ([]*model.VocabularyFilterResponse, error)
+	DeleteVocabularyFilter(ctx context.Context, name string) error
+}
+
+// NewVocabularyFilterService ファクトリ関数
+func NewVocabularyFilterService(impl VocabularyFilterService) VocabularyFilterService {
+	return impl
+}
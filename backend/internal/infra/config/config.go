@@ -1,22 +1,93 @@
 package config
 
 import (
+	"cmTranscribe/internal/shared/logger"
 	"fmt"
 	"github.com/joho/godotenv"
-	"log"
 	"os"
+	"path"
+	"strconv"
+	"strings"
+	"time"
 )
 
 // Config アプリケーションの設定を保持します。
 type Config struct {
 	Port               string
 	AWSRegion          string
+	AWSEndpointURL     string // すべてのAWSサービスのエンドポイント (LocalStackなど。空の場合はAWS)
 	S3BucketName       string
 	S3PrefixVocabulary string
 	S3PrefixUploadFile string
-	MediaFormat        string
+	MediaFormat        string // メディアを解析できなかった場合に指定するフォーマット (空の場合はAmazon Transcribeが判定する)
+	// VocabularySyncInterval カスタムボキャブラリの状態を同期する間隔
+	VocabularySyncInterval time.Duration
+	// S3PrefixVocabularyFilter 語彙フィルタの単語リストをアップロードするプレフィックス
+	S3PrefixVocabularyFilter string
+	ReadingDictionaryFile    string        // SoundsLikeを自動生成する際の読みの上書き辞書 (TSV)
+	UploadMaxBytes           int64         // アップロードできるファイルサイズの上限 (サーバー経由、署名付きURL、再開可能なアップロードのすべてに適用する)
+	UploadURLExpiry          time.Duration // アップロード用の署名付きURLの有効期限
+	UploadPartMaxBytes       int64         // 再開可能なアップロードで1回に送れるパートのサイズの上限
+	UploadAllowedFormats     []string      // サーバー経由のアップロードで受け付けるメディアフォーマット
+	StorageBackend           string        // ファイルの保存先 (s3 または local)
+	StorageLocalRoot         string        // ローカルストレージのルートディレクトリ
+	StoragePublicURL         string        // ローカルストレージの署名付きURLに使うサーバーのURL
+	StorageSigningSecret     string        // ローカルストレージの署名付きURLの署名に使う秘密鍵 (空の場合は起動ごとに生成する)
+	TranscribeEngine         string        // 文字起こしとカスタムボキャブラリの実行先 (aws または fake)
+	FakeTranscribeJobTime    time.Duration // fakeエンジンでジョブが完了するまでの時間
+	FakeVocabularyReadyTime  time.Duration // fakeエンジンでボキャブラリがREADYになるまでの時間
+	FakeTranscriptFixture    string        // fakeエンジンが文字起こし結果に使うAmazon Transcribeの出力JSON
+	S3EndpointURL            string        // S3のエンドポイント (MinIOなど。空の場合はAWSEndpointURL)
+	S3PublicEndpointURL      string        // ブラウザに渡す署名付きURLに使うS3のエンドポイント (空の場合はS3EndpointURL)
+	S3UsePathStyle           bool          // バケット名をパスに含める形式でS3にアクセスする
+	TranscribeEndpointURL    string        // Amazon Transcribeのエンドポイント (空の場合はAWSEndpointURL)
+	AWSAccessKeyID           string        // 静的な認証情報 (空の場合はAWS SDKの標準の方法で取得する)
+	AWSSecretAccessKey       string
+	AWSSessionToken          string
+	LogLevel                 logger.Level // 出力するログの最低レベル (debug, info, warn, error)
+	AuthMode                 string       // APIの認証方式 (none, api_key または jwt)
+	AuthAPIKeys              string       // api_key で受け付けるキー ("<subject>[@<tenant>][:<roles>]=<key>" のカンマ区切り)
+	AuthJWKSFile             string       // jwt の署名の検証に使うJWKSファイル
+	AuthJWTIssuer            string       // jwt の iss と一致する必要がある値 (空の場合は検証しない)
+	AuthJWTAudience          string       // jwt の aud に含まれる必要がある値 (空の場合は検証しない)
+	AuthJWTRolesClaim        string       // jwt のロールを読み取るクレーム
+	AuthJWTTenantClaim       string       // jwt のテナントを読み取るクレーム
+	AuthAdminRole            string       // すべての呼び出し元のジョブを参照できる管理者のロール
+	TenantsFile              string       // テナントの設定ファイル (JSON)。空の場合はテナントを使わない
+
+	RateLimitRPS             float64       // 呼び出し元ごと・ルートごとに1秒あたりに受け付けるリクエストの数 (0の場合は制限しない)
+	RateLimitBurst           int           // 連続して受け付けるリクエストの数 (0の場合は RateLimitRPS の切り上げ)
+	RateLimitScope           string        // レート制限のバケットを分ける単位 (tenant または key)
+	RateLimitRoutes          string        // ルートごとのレート制限 ("[<METHOD> ]<path>=<rps>[:<burst>]" のカンマ区切り)
+	TranscribeMaxConcurrent  int           // 同時に実行する文字起こしジョブの上限 (0の場合は上限を設けない)
+	TranscribeMaxQueued      int           // 送信を待たせる文字起こしジョブの上限
+	TranscribeSubmitInterval time.Duration // 実行中のジョブの数を確認し、待っているジョブを送信する間隔
+	MetricsPath              string        // Prometheus 形式のメトリクスを公開するパス (空の場合は公開しない)
+	TranscriptFetchWorkers   int           // ボキャブラリの提案やレポートで同時に取得する文字起こし結果の数
+	TranscriptCacheSize      int           // メモリに保持する完了済みジョブの文字起こし結果の数 (0の場合は保持しない)
+	UploadSessionExpiry      time.Duration // 更新の無くなった再開可能なアップロードを破棄するまでの時間
+	TranscriptionJobsFile    string        // 文字起こしジョブの記録を保存するファイル (空の場合はメモリ上にのみ保持する)
 }
 
+// ストレージのバックエンド
+const (
+	StorageBackendS3    = "s3"
+	StorageBackendLocal = "local"
+)
+
+// 文字起こしエンジン
+const (
+	TranscribeEngineAWS  = "aws"
+	TranscribeEngineFake = "fake"
+)
+
+// APIの認証方式
+const (
+	AuthModeNone   = "none"
+	AuthModeAPIKey = "api_key"
+	AuthModeJWT    = "jwt"
+)
+
 // AppConfig アプリケーション全体で使用される設定を保持します。
 var AppConfig *Config
 
@@ -26,23 +97,187 @@ func LoadConfig() error {
 	err := godotenv.Load()
 	if err != nil {
 		// .env ファイルが見つからない場合やロードに失敗した場合、アプリケーションを終了するかどうかを判断
-		log.Printf("Error loading .env file: %v\n", err)
+		logger.Default().Error("Error loading .env file", "error", err)
 		return err
 	}
 
 	AppConfig = &Config{
 		Port:               getEnv("PORT", "8080"),
 		AWSRegion:          getEnv("AWS_REGION", "ap-northeast-1"),
+		AWSEndpointURL:     getEnv("AWS_ENDPOINT_URL", ""),
 		S3BucketName:       getEnv("S3_BUCKET_NAME", ""),
 		S3PrefixVocabulary: getEnv("S3_PREFIX_VOCABULARY", ""),
 		S3PrefixUploadFile: getEnv("S3_PREFIX_UPLOAD_FILE", ""),
-		MediaFormat:        getEnv("MEDIA_FORMAT", "mp3"),
+		MediaFormat:        getEnv("MEDIA_FORMAT", ""),
+
+		S3PrefixVocabularyFilter: getEnv("S3_PREFIX_VOCABULARY_FILTER", getEnv("S3_PREFIX_VOCABULARY", "")),
+		ReadingDictionaryFile:    getEnv("READING_DICTIONARY_FILE", ""),
+		StorageBackend:           strings.ToLower(getEnv("STORAGE_BACKEND", StorageBackendS3)),
+		StorageLocalRoot:         getEnv("STORAGE_LOCAL_ROOT", ""),
+		StoragePublicURL:         getEnv("STORAGE_PUBLIC_URL", ""),
+		StorageSigningSecret:     getEnv("STORAGE_SIGNING_SECRET", ""),
+		TranscribeEngine:         strings.ToLower(getEnv("TRANSCRIBE_ENGINE", TranscribeEngineAWS)),
+		FakeTranscriptFixture:    getEnv("FAKE_TRANSCRIPT_FIXTURE", ""),
+		S3EndpointURL:            getEnv("AWS_ENDPOINT_URL_S3", ""),
+		S3PublicEndpointURL:      getEnv("S3_PUBLIC_ENDPOINT_URL", ""),
+		TranscribeEndpointURL:    getEnv("AWS_ENDPOINT_URL_TRANSCRIBE", ""),
+		AWSAccessKeyID:           getEnv("AWS_ACCESS_KEY_ID", ""),
+		AWSSecretAccessKey:       getEnv("AWS_SECRET_ACCESS_KEY", ""),
+		AWSSessionToken:          getEnv("AWS_SESSION_TOKEN", ""),
+		AuthMode:                 strings.ToLower(getEnv("AUTH_MODE", AuthModeNone)),
+		AuthAPIKeys:              getEnv("AUTH_API_KEYS", ""),
+		AuthJWKSFile:             getEnv("AUTH_JWKS_FILE", ""),
+		AuthJWTIssuer:            getEnv("AUTH_JWT_ISSUER", ""),
+		AuthJWTAudience:          getEnv("AUTH_JWT_AUDIENCE", ""),
+		AuthJWTRolesClaim:        getEnv("AUTH_JWT_ROLES_CLAIM", "roles"),
+		AuthJWTTenantClaim:       getEnv("AUTH_JWT_TENANT_CLAIM", "tenant"),
+		AuthAdminRole:            getEnv("AUTH_ADMIN_ROLE", "admin"),
+		TenantsFile:              getEnv("TENANTS_FILE", ""),
+		RateLimitScope:           strings.ToLower(getEnv("RATE_LIMIT_SCOPE", "tenant")),
+		RateLimitRoutes:          getEnv("RATE_LIMIT_ROUTES", ""),
+		MetricsPath:              getEnv("METRICS_PATH", ""),
+		TranscriptionJobsFile:    getEnv("TRANSCRIPTION_JOBS_FILE", ""),
+	}
+	if AppConfig.StorageLocalRoot == "" {
+		AppConfig.StorageLocalRoot = "./data/storage"
+	}
+	if AppConfig.StoragePublicURL == "" {
+		AppConfig.StoragePublicURL = "http://localhost:" + AppConfig.Port
+	}
+
+	syncInterval, err := time.ParseDuration(getEnv("VOCABULARY_SYNC_INTERVAL", "30s"))
+	if err != nil || syncInterval <= 0 {
+		return fmt.Errorf("VOCABULARY_SYNC_INTERVAL is invalid: %s", getEnv("VOCABULARY_SYNC_INTERVAL", ""))
+	}
+	AppConfig.VocabularySyncInterval = syncInterval
+
+	uploadMaxBytes, err := strconv.ParseInt(getEnv("UPLOAD_MAX_BYTES", "2147483648"), 10, 64)
+	if err != nil || uploadMaxBytes <= 0 {
+		return fmt.Errorf("UPLOAD_MAX_BYTES is invalid: %s", getEnv("UPLOAD_MAX_BYTES", ""))
+	}
+	AppConfig.UploadMaxBytes = uploadMaxBytes
+
+	uploadURLExpiry, err := time.ParseDuration(getEnv("UPLOAD_URL_EXPIRY", "15m"))
+	if err != nil || uploadURLExpiry <= 0 {
+		return fmt.Errorf("UPLOAD_URL_EXPIRY is invalid: %s", getEnv("UPLOAD_URL_EXPIRY", ""))
+	}
+	AppConfig.UploadURLExpiry = uploadURLExpiry
+
+	uploadPartMaxBytes, err := strconv.ParseInt(getEnv("UPLOAD_PART_MAX_BYTES", "67108864"), 10, 64)
+	if err != nil || uploadPartMaxBytes < 5*1024*1024 {
+		return fmt.Errorf("UPLOAD_PART_MAX_BYTES must be at least 5MiB: %s", getEnv("UPLOAD_PART_MAX_BYTES", ""))
+	}
+	AppConfig.UploadPartMaxBytes = uploadPartMaxBytes
+
+	for _, format := range strings.Split(getEnv("UPLOAD_ALLOWED_FORMATS", "mp3,mp4,wav,flac,ogg,webm,m4a"), ",") {
+		if format = strings.ToLower(strings.TrimSpace(format)); format != "" {
+			AppConfig.UploadAllowedFormats = append(AppConfig.UploadAllowedFormats, format)
+		}
+	}
+
+	s3UsePathStyle, err := strconv.ParseBool(getEnv("S3_USE_PATH_STYLE", "false"))
+	if err != nil {
+		return fmt.Errorf("S3_USE_PATH_STYLE is invalid: %v", err)
+	}
+	AppConfig.S3UsePathStyle = s3UsePathStyle
+
+	fakeJobTime, err := time.ParseDuration(getEnv("FAKE_TRANSCRIBE_JOB_TIME", "10s"))
+	if err != nil {
+		return fmt.Errorf("FAKE_TRANSCRIBE_JOB_TIME is invalid: %v", err)
+	}
+	AppConfig.FakeTranscribeJobTime = fakeJobTime
+
+	fakeVocabularyReadyTime, err := time.ParseDuration(getEnv("FAKE_VOCABULARY_READY_TIME", "5s"))
+	if err != nil {
+		return fmt.Errorf("FAKE_VOCABULARY_READY_TIME is invalid: %v", err)
+	}
+	AppConfig.FakeVocabularyReadyTime = fakeVocabularyReadyTime
+
+	rateLimitRPS, err := strconv.ParseFloat(getEnv("RATE_LIMIT_RPS", "0"), 64)
+	if err != nil || rateLimitRPS < 0 {
+		return fmt.Errorf("RATE_LIMIT_RPS is invalid: %s", getEnv("RATE_LIMIT_RPS", ""))
+	}
+	AppConfig.RateLimitRPS = rateLimitRPS
+
+	rateLimitBurst, err := strconv.Atoi(getEnv("RATE_LIMIT_BURST", "0"))
+	if err != nil || rateLimitBurst < 0 {
+		return fmt.Errorf("RATE_LIMIT_BURST is invalid: %s", getEnv("RATE_LIMIT_BURST", ""))
+	}
+	AppConfig.RateLimitBurst = rateLimitBurst
+
+	transcribeMaxConcurrent, err := strconv.Atoi(getEnv("TRANSCRIBE_MAX_CONCURRENT_JOBS", "0"))
+	if err != nil || transcribeMaxConcurrent < 0 {
+		return fmt.Errorf("TRANSCRIBE_MAX_CONCURRENT_JOBS is invalid: %s", getEnv("TRANSCRIBE_MAX_CONCURRENT_JOBS", ""))
+	}
+	AppConfig.TranscribeMaxConcurrent = transcribeMaxConcurrent
+
+	transcribeMaxQueued, err := strconv.Atoi(getEnv("TRANSCRIBE_MAX_QUEUED_JOBS", "1000"))
+	if err != nil || transcribeMaxQueued <= 0 {
+		return fmt.Errorf("TRANSCRIBE_MAX_QUEUED_JOBS is invalid: %s", getEnv("TRANSCRIBE_MAX_QUEUED_JOBS", ""))
+	}
+	AppConfig.TranscribeMaxQueued = transcribeMaxQueued
+
+	transcribeSubmitInterval, err := time.ParseDuration(getEnv("TRANSCRIBE_SUBMISSION_INTERVAL", "10s"))
+	if err != nil || transcribeSubmitInterval <= 0 {
+		return fmt.Errorf("TRANSCRIBE_SUBMISSION_INTERVAL is invalid: %s", getEnv("TRANSCRIBE_SUBMISSION_INTERVAL", ""))
+	}
+	AppConfig.TranscribeSubmitInterval = transcribeSubmitInterval
+
+	transcriptFetchWorkers, err := strconv.Atoi(getEnv("TRANSCRIPT_FETCH_WORKERS", "8"))
+	if err != nil || transcriptFetchWorkers <= 0 {
+		return fmt.Errorf("TRANSCRIPT_FETCH_WORKERS is invalid: %s", getEnv("TRANSCRIPT_FETCH_WORKERS", ""))
+	}
+	AppConfig.TranscriptFetchWorkers = transcriptFetchWorkers
+
+	transcriptCacheSize, err := strconv.Atoi(getEnv("TRANSCRIPT_CACHE_SIZE", "200"))
+	if err != nil || transcriptCacheSize < 0 {
+		return fmt.Errorf("TRANSCRIPT_CACHE_SIZE is invalid: %s", getEnv("TRANSCRIPT_CACHE_SIZE", ""))
+	}
+	AppConfig.TranscriptCacheSize = transcriptCacheSize
+
+	uploadSessionExpiry, err := time.ParseDuration(getEnv("UPLOAD_SESSION_EXPIRY", "24h"))
+	if err != nil || uploadSessionExpiry <= 0 {
+		return fmt.Errorf("UPLOAD_SESSION_EXPIRY is invalid: %s", getEnv("UPLOAD_SESSION_EXPIRY", ""))
+	}
+	AppConfig.UploadSessionExpiry = uploadSessionExpiry
+
+	logLevel, err := logger.ParseLevel(getEnv("LOG_LEVEL", "info"))
+	if err != nil {
+		return fmt.Errorf("LOG_LEVEL is invalid: %v", err)
+	}
+	AppConfig.LogLevel = logLevel
+
+	if AppConfig.MetricsPath != "" && !validMetricsPath(AppConfig.MetricsPath) {
+		return fmt.Errorf("METRICS_PATH must start with /, must not be / and must not overlap /api: %s", AppConfig.MetricsPath)
+	}
+	if AppConfig.StorageBackend != StorageBackendS3 && AppConfig.StorageBackend != StorageBackendLocal {
+		return fmt.Errorf("STORAGE_BACKEND must be s3 or local: %s", AppConfig.StorageBackend)
+	}
+	if AppConfig.TranscribeEngine != TranscribeEngineAWS && AppConfig.TranscribeEngine != TranscribeEngineFake {
+		return fmt.Errorf("TRANSCRIBE_ENGINE must be aws or fake: %s", AppConfig.TranscribeEngine)
+	}
+	switch AppConfig.AuthMode {
+	case AuthModeNone:
+	case AuthModeAPIKey:
+		if AppConfig.AuthAPIKeys == "" {
+			return fmt.Errorf("AUTH_API_KEYS is required when AUTH_MODE is api_key")
+		}
+	case AuthModeJWT:
+		if AppConfig.AuthJWKSFile == "" {
+			return fmt.Errorf("AUTH_JWKS_FILE is required when AUTH_MODE is jwt")
+		}
+	default:
+		return fmt.Errorf("AUTH_MODE must be none, api_key or jwt: %s", AppConfig.AuthMode)
 	}
 
 	// 必須の設定項目が不足している場合、エラーを返す
 	if AppConfig.S3BucketName == "" {
 		return fmt.Errorf("S3_BUCKET_NAME is required but not set")
 	}
+	// アップロードされたオブジェクトの削除やメディアの一覧はこのプレフィックスの下に限るため、バケット全体を対象にしない
+	if strings.Trim(path.Clean("/"+AppConfig.S3PrefixUploadFile), "/") == "" {
+		return fmt.Errorf("S3_PREFIX_UPLOAD_FILE is required but not set")
+	}
 
 	return nil
 }
@@ -55,3 +290,12 @@ func getEnv(key, defaultValue string) string {
 	}
 	return value
 }
+
+// validMetricsPath は、メトリクスのパスが認証の対象外にしても API のルートを含まないかどうかを返します。
+// "/" や "/api"、その途中までのパス ("/a" など) と /api/ の下のパスは使えません
+func validMetricsPath(metricsPath string) bool {
+	if !strings.HasPrefix(metricsPath, "/") || path.Clean(metricsPath) != metricsPath {
+		return false
+	}
+	return metricsPath != "/" && !strings.HasPrefix("/api", metricsPath) && !strings.HasPrefix(metricsPath, "/api/")
+}
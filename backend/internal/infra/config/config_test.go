@@ -0,0 +1,30 @@
+package config
+
+import "testing"
+
+func TestValidMetricsPath(t *testing.T) {
+	tests := []struct {
+		path string
+		want bool
+	}{
+		{path: "/metrics", want: true},
+		{path: "/internal/metrics", want: true},
+		{path: "/apimetrics", want: true},
+		{path: "metrics", want: false},
+		{path: "/", want: false},
+		{path: "/a", want: false},
+		{path: "/ap", want: false},
+		{path: "/api", want: false},
+		{path: "/api/", want: false},
+		{path: "/api/metrics", want: false},
+		{path: "/metrics/", want: false},
+		{path: "/x/../api", want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			if got := validMetricsPath(tt.path); got != tt.want {
+				t.Errorf("validMetricsPath(%q) = %v, want %v", tt.path, got, tt.want)
+			}
+		})
+	}
+}
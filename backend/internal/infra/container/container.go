@@ -2,12 +2,18 @@ package container
 
 import (
 	applicationService "cmTranscribe/internal/app/service"
+	"cmTranscribe/internal/domain/repository"
 	domainService "cmTranscribe/internal/domain/service"
 	"cmTranscribe/internal/infra/config"
 	"cmTranscribe/internal/infra/persistence"
 	infraService "cmTranscribe/internal/infra/service"
+	"cmTranscribe/internal/shared/logger"
+	"cmTranscribe/internal/shared/metrics"
+	"cmTranscribe/internal/shared/middleware"
 	"context"
 	"fmt"
+	"log"
+	"os"
 )
 
 // AppContainer は依存関係を保持する構造体です
@@ -15,6 +21,18 @@ type AppContainer struct {
 	TranscriptionJobService *applicationService.TranscriptionJobService
 	CustomVocabularyService *applicationService.CustomVocabularyService
 	S3UploadService         *applicationService.S3UploadService
+	VocabularyStateSyncer   *applicationService.VocabularyStateSyncer
+	SubmissionGovernor      *applicationService.TranscriptionSubmissionGovernor
+	SuggestionService       *applicationService.VocabularySuggestionService
+	VocabularyFilterService *applicationService.VocabularyFilterService
+	ReportService           *applicationService.VocabularyReportService
+	SyncService             *applicationService.VocabularySyncService
+	ResumableUploadService  *applicationService.ResumableUploadService
+	MediaLibraryService     *applicationService.MediaLibraryService
+	StorageObjectService    *applicationService.StorageObjectService // ローカルストレージを使う場合のみ
+	Authenticator           domainService.Authenticator              // 認証が無効な場合は nil
+	TenantRepo              repository.TenantRepository              // テナントを使わない場合は nil
+	RateLimiter             *middleware.RateLimiter                  // レート制限が無効な場合は nil
 }
 
 // NewAppContainer はアプリケーション全体の依存関係を初期化します
@@ -24,25 +42,129 @@ func NewAppContainer(ctx context.Context) (*AppContainer, error) {
 		return nil, err
 	}
 
+	// 構造化ロガーの初期化 (標準の log パッケージの出力もJSONにする)
+	logger.SetDefault(logger.New(os.Stderr, config.AppConfig.LogLevel))
+	log.SetFlags(0)
+	log.SetOutput(logger.Default().Writer())
+
 	// リポジトリの初期化
-	transcriptionRepo, err := persistence.NewTranscriptionJobRepository()
+	transcriptionRepo, err := persistence.NewTranscriptionJobRepository(config.AppConfig.TranscriptionJobsFile)
 	if err != nil {
 		return nil, fmt.Errorf("failed to initialize transcription repository: %w", err)
 	}
+	vocabularyVersionRepo, err := persistence.NewVocabularyVersionRepository()
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize vocabulary version repository: %w", err)
+	}
+	customVocabularyRepo, err := persistence.NewCustomVocabularyRepository()
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize custom vocabulary repository: %w", err)
+	}
+	correctionRepo, err := persistence.NewTranscriptCorrectionRepository()
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize transcript correction repository: %w", err)
+	}
+	uploadSessionRepo, err := persistence.NewUploadSessionRepository()
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize upload session repository: %w", err)
+	}
+	issuedUploadRepo, err := persistence.NewIssuedUploadRepository()
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize issued upload repository: %w", err)
+	}
+	var tenantRepo repository.TenantRepository
+	if config.AppConfig.TenantsFile != "" {
+		tenantRepo, err = persistence.NewTenantRepository(config.AppConfig.TenantsFile, config.AppConfig.S3PrefixUploadFile, config.AppConfig.S3PrefixVocabulary, config.AppConfig.S3PrefixVocabularyFilter)
+		if err != nil {
+			return nil, fmt.Errorf("failed to initialize tenant repository: %w", err)
+		}
+	}
 
-	// 外部サービスの初期化
-	transcribeInfraService, err := infraService.NewTranscribeService(ctx, config.AppConfig.AWSRegion)
+	// AWSクライアントで共有する設定の読み込み
+	awsSettings := infraService.AWSSettings{
+		Region:             config.AppConfig.AWSRegion,
+		EndpointURL:        config.AppConfig.AWSEndpointURL,
+		S3EndpointURL:      config.AppConfig.S3EndpointURL,
+		S3PublicURL:        config.AppConfig.S3PublicEndpointURL,
+		S3UsePathStyle:     config.AppConfig.S3UsePathStyle,
+		TranscribeEndpoint: config.AppConfig.TranscribeEndpointURL,
+		AccessKeyID:        config.AppConfig.AWSAccessKeyID,
+		SecretAccessKey:    config.AppConfig.AWSSecretAccessKey,
+		SessionToken:       config.AppConfig.AWSSessionToken,
+	}
+	awsConfig, err := infraService.LoadAWSConfig(ctx, awsSettings)
 	if err != nil {
-		return nil, fmt.Errorf("failed to initialize TranscribeInfraService: %w", err)
+		return nil, err
 	}
+
+	// 外部サービスの初期化
 	fileInfraService := infraService.NewFileService()
-	s3StorageInfraService, err := infraService.NewS3StorageService(ctx, config.AppConfig.AWSRegion)
+	var s3StorageInfraService domainService.S3StorageService
+	var signedObjectInfraStore domainService.SignedObjectStore
+	switch config.AppConfig.StorageBackend {
+	case config.StorageBackendLocal:
+		localStorageInfraService, err := infraService.NewLocalStorageService(config.AppConfig.StorageLocalRoot, config.AppConfig.StoragePublicURL, config.AppConfig.StorageSigningSecret)
+		if err != nil {
+			return nil, fmt.Errorf("failed to initialize LocalStorageService: %w", err)
+		}
+		s3StorageInfraService = localStorageInfraService
+		signedObjectInfraStore = localStorageInfraService
+	default:
+		s3StorageInfraService = infraService.NewS3StorageService(awsConfig, awsSettings)
+	}
+	var transcribeInfraService domainService.TranscriptionJobService
+	var customVocabularyInfraService domainService.CustomVocabularyService
+	var vocabularyFilterInfraService domainService.VocabularyFilterService
+	switch config.AppConfig.TranscribeEngine {
+	case config.TranscribeEngineFake:
+		// 文字起こし、カスタムボキャブラリ、語彙フィルタをAWSを使わずにプロセス内で再現する
+		fakeTranscribeEngine, err := infraService.NewFakeTranscribeEngine(s3StorageInfraService, config.AppConfig.S3BucketName, config.AppConfig.FakeTranscribeJobTime, config.AppConfig.FakeVocabularyReadyTime, config.AppConfig.FakeTranscriptFixture)
+		if err != nil {
+			return nil, fmt.Errorf("failed to initialize FakeTranscribeEngine: %w", err)
+		}
+		transcribeInfraService = fakeTranscribeEngine
+		customVocabularyInfraService = fakeTranscribeEngine
+		vocabularyFilterInfraService = fakeTranscribeEngine
+	default:
+		transcribeInfraService = infraService.NewTranscribeService(awsConfig, awsSettings)
+		customVocabularyInfraService = infraService.NewCustomVocabularyService(awsConfig, awsSettings)
+		vocabularyFilterInfraService = infraService.NewVocabularyFilterService(awsConfig, awsSettings)
+	}
+	readingInfraGenerator, err := infraService.NewKatakanaReadingGenerator(config.AppConfig.ReadingDictionaryFile)
 	if err != nil {
-		return nil, fmt.Errorf("failed to initialize S3StorageService: %w", err)
+		return nil, fmt.Errorf("failed to initialize ReadingGenerator: %w", err)
+	}
+	mediaInfraProber := infraService.NewMediaProber()
+	var authInfraAuthenticator domainService.Authenticator
+	switch config.AppConfig.AuthMode {
+	case config.AuthModeAPIKey:
+		apiKeyAuthenticator, err := infraService.NewAPIKeyAuthenticator(config.AppConfig.AuthAPIKeys, config.AppConfig.AuthAdminRole)
+		if err != nil {
+			return nil, fmt.Errorf("failed to initialize APIKeyAuthenticator: %w", err)
+		}
+		authInfraAuthenticator = apiKeyAuthenticator
+	case config.AuthModeJWT:
+		jwtAuthenticator, err := infraService.NewJWTAuthenticator(infraService.JWTSettings{
+			JWKSFile:    config.AppConfig.AuthJWKSFile,
+			Issuer:      config.AppConfig.AuthJWTIssuer,
+			Audience:    config.AppConfig.AuthJWTAudience,
+			RolesClaim:  config.AppConfig.AuthJWTRolesClaim,
+			TenantClaim: config.AppConfig.AuthJWTTenantClaim,
+			AdminRole:   config.AppConfig.AuthAdminRole,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to initialize JWTAuthenticator: %w", err)
+		}
+		authInfraAuthenticator = jwtAuthenticator
 	}
-	customVocabularyInfraService, err := infraService.NewCustomVocabularyService(ctx, config.AppConfig.AWSRegion)
+
+	// レート制限の初期化 (制限するルートが無い場合は使わない)
+	rateLimiter, err := middleware.NewRateLimiter(config.AppConfig.RateLimitScope, middleware.RateLimit{PerSecond: config.AppConfig.RateLimitRPS, Burst: config.AppConfig.RateLimitBurst}, config.AppConfig.RateLimitRoutes)
 	if err != nil {
-		return nil, fmt.Errorf("failed to initialize CustomVocabularyService: %w", err)
+		return nil, fmt.Errorf("failed to initialize rate limiter: %w", err)
+	}
+	if !rateLimiter.Enabled() {
+		rateLimiter = nil
 	}
 
 	// ドメインサービスの初期化
@@ -50,15 +172,48 @@ func NewAppContainer(ctx context.Context) (*AppContainer, error) {
 	customVocabularyService := domainService.NewCustomVocabularyService(customVocabularyInfraService)
 	fileService := domainService.NewFileService(fileInfraService)
 	s3StorageService := domainService.NewS3StorageService(s3StorageInfraService)
+	vocabularyFilterService := domainService.NewVocabularyFilterService(vocabularyFilterInfraService)
+	readingGenerator := domainService.NewReadingGenerator(readingInfraGenerator)
+	mediaProber := domainService.NewMediaProber(mediaInfraProber)
+	var authenticator domainService.Authenticator
+	if authInfraAuthenticator != nil {
+		authenticator = domainService.NewAuthenticator(authInfraAuthenticator)
+	}
 
 	// アプリケーションサービスの初期化
-	transcriptionJobAppService := applicationService.NewTranscriptionJobService(transcriptionRepo, transcriptionJobService, s3StorageService)
-	customVocabularyAppService := applicationService.NewCustomVocabularyService(customVocabularyService, fileService, s3StorageService)
-	s3UploadAppService := applicationService.NewS3UploadService(s3StorageService)
+	submissionGovernor := applicationService.NewTranscriptionSubmissionGovernor(transcriptionRepo, transcriptionJobService, config.AppConfig.TranscribeMaxConcurrent, config.AppConfig.TranscribeMaxQueued, config.AppConfig.TranscribeSubmitInterval)
+	metrics.SubmissionQueueLength.SetFunc(func() float64 { return float64(len(submissionGovernor.Queued())) })
+	transcriptionJobAppService := applicationService.NewTranscriptionJobService(transcriptionRepo, transcriptionJobService, s3StorageService, customVocabularyService, mediaProber, submissionGovernor)
+	vocabularyStateSyncer := applicationService.NewVocabularyStateSyncer(customVocabularyRepo, customVocabularyService, config.AppConfig.VocabularySyncInterval)
+	customVocabularyAppService := applicationService.NewCustomVocabularyService(customVocabularyService, fileService, s3StorageService, vocabularyVersionRepo, customVocabularyRepo, vocabularyStateSyncer, readingGenerator)
+	s3UploadAppService := applicationService.NewS3UploadService(s3StorageService, mediaProber, issuedUploadRepo)
+	transcriptLoader := applicationService.NewTranscriptLoader(s3StorageService, transcriptionRepo, config.AppConfig.TranscriptFetchWorkers, config.AppConfig.TranscriptCacheSize)
+	suggestionAppService := applicationService.NewVocabularySuggestionService(correctionRepo, transcriptionRepo, transcriptionJobService, transcriptLoader, customVocabularyAppService)
+	vocabularyFilterAppService := applicationService.NewVocabularyFilterService(vocabularyFilterService, fileService, s3StorageService)
+	reportAppService := applicationService.NewVocabularyReportService(transcriptionRepo, transcriptionJobService, transcriptLoader, customVocabularyAppService)
+	syncAppService := applicationService.NewVocabularySyncService(customVocabularyAppService)
+	resumableUploadAppService := applicationService.NewResumableUploadService(uploadSessionRepo, s3StorageService, mediaProber)
+	mediaLibraryAppService := applicationService.NewMediaLibraryService(s3StorageService, transcriptionRepo)
+	var storageObjectAppService *applicationService.StorageObjectService
+	if signedObjectInfraStore != nil {
+		storageObjectAppService = applicationService.NewStorageObjectService(domainService.NewSignedObjectStore(signedObjectInfraStore))
+	}
 
 	return &AppContainer{
 		TranscriptionJobService: transcriptionJobAppService,
 		CustomVocabularyService: customVocabularyAppService,
 		S3UploadService:         s3UploadAppService,
+		VocabularyStateSyncer:   vocabularyStateSyncer,
+		SubmissionGovernor:      submissionGovernor,
+		SuggestionService:       suggestionAppService,
+		VocabularyFilterService: vocabularyFilterAppService,
+		ReportService:           reportAppService,
+		SyncService:             syncAppService,
+		ResumableUploadService:  resumableUploadAppService,
+		MediaLibraryService:     mediaLibraryAppService,
+		StorageObjectService:    storageObjectAppService,
+		Authenticator:           authenticator,
+		TenantRepo:              tenantRepo,
+		RateLimiter:             rateLimiter,
 	}, nil
 }
@@ -0,0 +1,86 @@
+package persistence
+
+import (
+	"cmTranscribe/internal/domain/domainerr"
+	"cmTranscribe/internal/domain/model"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// CustomVocabularyRepository カスタムボキャブラリを管理するためのリポジトリです。
+type CustomVocabularyRepository struct {
+	mu           sync.RWMutex
+	vocabularies map[string]*model.CustomVocabularyDB
+}
+
+// NewCustomVocabularyRepository 新しいCustomVocabularyRepositoryを作成します。
+func NewCustomVocabularyRepository() (*CustomVocabularyRepository, error) {
+	return &CustomVocabularyRepository{
+		vocabularies: make(map[string]*model.CustomVocabularyDB),
+	}, nil
+}
+
+// Save カスタムボキャブラリを保存します。
+func (r *CustomVocabularyRepository) Save(vocabulary *model.CustomVocabularyDB) error {
+	if vocabulary == nil {
+		return fmt.Errorf("failed to save custom vocabulary: vocabulary is nil")
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored := *vocabulary
+	r.vocabularies[vocabulary.Name] = &stored
+	return nil
+}
+
+// FindByName 名前でカスタムボキャブラリを検索します。
+func (r *CustomVocabularyRepository) FindByName(name string) (*model.CustomVocabularyDB, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	vocabulary, exists := r.vocabularies[name]
+	if !exists {
+		return nil, domainerr.New(domainerr.NotFound, "custom_vocabulary_not_found", "custom vocabulary %s does not exist", name)
+	}
+	found := *vocabulary
+	return &found, nil
+}
+
+// FindAll すべてのカスタムボキャブラリを取得します。
+func (r *CustomVocabularyRepository) FindAll() ([]*model.CustomVocabularyDB, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	result := make([]*model.CustomVocabularyDB, 0, len(r.vocabularies))
+	for _, vocabulary := range r.vocabularies {
+		found := *vocabulary
+		result = append(result, &found)
+	}
+	return result, nil
+}
+
+// ApplyState 作成・更新を依頼した日時が変わっていない場合だけ、状態を反映します。
+func (r *CustomVocabularyRepository) ApplyState(name string, updatedAt time.Time, state, failureReason string) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	vocabulary, exists := r.vocabularies[name]
+	if !exists {
+		return false, domainerr.New(domainerr.NotFound, "custom_vocabulary_not_found", "custom vocabulary %s does not exist", name)
+	}
+	if !vocabulary.UpdatedAt.Equal(updatedAt) {
+		return false, nil
+	}
+	vocabulary.ApplyState(state, failureReason)
+	return true, nil
+}
+
+// Delete 名前でカスタムボキャブラリを削除します。存在しない場合は何もしません。
+func (r *CustomVocabularyRepository) Delete(name string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.vocabularies, name)
+	return nil
+}
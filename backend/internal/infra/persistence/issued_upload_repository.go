@@ -0,0 +1,67 @@
+package persistence
+
+import (
+	"cmTranscribe/internal/domain/domainerr"
+	"cmTranscribe/internal/domain/model"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// IssuedUploadRepository 署名付きURLを発行したアップロード先を管理するためのリポジトリです。
+// 確認を受け付ける期限を過ぎたものは、保存のたびに削除します。
+type IssuedUploadRepository struct {
+	mu      sync.Mutex
+	uploads map[string]*model.IssuedUpload
+}
+
+// NewIssuedUploadRepository 新しいIssuedUploadRepositoryを作成します。
+func NewIssuedUploadRepository() (*IssuedUploadRepository, error) {
+	return &IssuedUploadRepository{
+		uploads: make(map[string]*model.IssuedUpload),
+	}, nil
+}
+
+// Save アップロード先を保存します。
+func (r *IssuedUploadRepository) Save(upload *model.IssuedUpload) error {
+	if upload == nil {
+		return fmt.Errorf("failed to save issued upload: upload is nil")
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	for key, issued := range r.uploads {
+		if issued.IsExpired(now) {
+			delete(r.uploads, key)
+		}
+	}
+	u := *upload
+	r.uploads[upload.Key] = &u
+	return nil
+}
+
+// FindByKey キーでアップロード先を検索します。期限を過ぎたものは見つからないものとして扱います。
+func (r *IssuedUploadRepository) FindByKey(key string) (*model.IssuedUpload, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	upload, exists := r.uploads[key]
+	if !exists || upload.IsExpired(time.Now()) {
+		return nil, domainerr.New(domainerr.NotFound, "upload_not_found", "upload %s was not issued", key)
+	}
+	u := *upload
+	return &u, nil
+}
+
+// Delete アップロード先を削除します。
+func (r *IssuedUploadRepository) Delete(key string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.uploads[key]; !exists {
+		return domainerr.New(domainerr.NotFound, "upload_not_found", "upload %s was not issued", key)
+	}
+	delete(r.uploads, key)
+	return nil
+}
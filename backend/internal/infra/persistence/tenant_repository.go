@@ -0,0 +1,92 @@
+package persistence
+
+import (
+	"cmTranscribe/internal/domain/domainerr"
+	"cmTranscribe/internal/domain/model"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+)
+
+// TenantRepository 設定ファイルから読み込んだテナントを管理するためのリポジトリです。
+// テナントは起動時に読み込み、実行中には変更しません。
+type TenantRepository struct {
+	tenants map[string]*model.Tenant
+}
+
+// tenantFileEntry テナントの設定ファイル (JSONの配列) の1件分
+type tenantFileEntry struct {
+	ID                     string `json:"id"`
+	NamePrefix             string `json:"name_prefix"`
+	UploadPrefix           string `json:"upload_prefix"`
+	VocabularyPrefix       string `json:"vocabulary_prefix"`
+	VocabularyFilterPrefix string `json:"vocabulary_filter_prefix"`
+	TranscriptPrefix       string `json:"transcript_prefix"`
+	Quota                  struct {
+		MaxActiveJobs   int   `json:"max_active_jobs"`
+		MaxVocabularies int   `json:"max_vocabularies"`
+		MaxUploadBytes  int64 `json:"max_upload_bytes"`
+	} `json:"quota"`
+}
+
+// NewTenantRepository 設定ファイルからテナントを読み込んでTenantRepositoryを作成します。
+// 省略されたS3のプレフィックスには、既定のプレフィックスをテナントごとのディレクトリの下に置いたものを使います
+func NewTenantRepository(filePath, uploadPrefix, vocabularyPrefix, vocabularyFilterPrefix string) (*TenantRepository, error) {
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read tenants file: %w", err)
+	}
+	var entries []tenantFileEntry
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return nil, fmt.Errorf("failed to parse tenants file: %w", err)
+	}
+
+	repository := &TenantRepository{tenants: make(map[string]*model.Tenant)}
+	for _, entry := range entries {
+		quota := model.TenantQuota{
+			MaxActiveJobs:   entry.Quota.MaxActiveJobs,
+			MaxVocabularies: entry.Quota.MaxVocabularies,
+			MaxUploadBytes:  entry.Quota.MaxUploadBytes,
+		}
+		tenant := model.NewTenant(entry.ID, entry.NamePrefix, entry.UploadPrefix, entry.VocabularyPrefix, entry.VocabularyFilterPrefix, quota)
+		tenant.TranscriptPrefix = entry.TranscriptPrefix
+		tenant.WithDefaultPrefixes(uploadPrefix, vocabularyPrefix, vocabularyFilterPrefix)
+		if err := tenant.Validate(); err != nil {
+			return nil, err
+		}
+		if _, exists := repository.tenants[tenant.ID]; exists {
+			return nil, fmt.Errorf("tenant %s is defined more than once", tenant.ID)
+		}
+		// 他のテナントのリソースと区別できなくなる設定は受け付けない
+		for _, other := range repository.tenants {
+			if tenant.Overlaps(other) {
+				return nil, fmt.Errorf("name or S3 prefixes of tenant %s overlap with tenant %s", tenant.ID, other.ID)
+			}
+		}
+		repository.tenants[tenant.ID] = tenant
+	}
+	if len(repository.tenants) == 0 {
+		return nil, fmt.Errorf("no tenants are defined in %s", filePath)
+	}
+	return repository, nil
+}
+
+// FindByID IDでテナントを検索します。
+func (r *TenantRepository) FindByID(id string) (*model.Tenant, error) {
+	tenant, exists := r.tenants[id]
+	if !exists {
+		return nil, domainerr.New(domainerr.NotFound, "tenant_not_found", "tenant %s does not exist", id)
+	}
+	return tenant, nil
+}
+
+// FindAll すべてのテナントをIDの順に取得します。
+func (r *TenantRepository) FindAll() ([]*model.Tenant, error) {
+	result := make([]*model.Tenant, 0, len(r.tenants))
+	for _, tenant := range r.tenants {
+		result = append(result, tenant)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].ID < result[j].ID })
+	return result, nil
+}
@@ -0,0 +1,40 @@
+package persistence
+
+import (
+	"cmTranscribe/internal/domain/model"
+	"fmt"
+	"sync"
+)
+
+// TranscriptCorrectionRepository 文字起こし結果の修正履歴を管理するためのリポジトリです。
+type TranscriptCorrectionRepository struct {
+	mu          sync.RWMutex
+	corrections []*model.TranscriptCorrection
+}
+
+// NewTranscriptCorrectionRepository 新しいTranscriptCorrectionRepositoryを作成します。
+func NewTranscriptCorrectionRepository() (*TranscriptCorrectionRepository, error) {
+	return &TranscriptCorrectionRepository{}, nil
+}
+
+// Save 修正履歴を保存します。
+func (r *TranscriptCorrectionRepository) Save(correction *model.TranscriptCorrection) error {
+	if correction == nil {
+		return fmt.Errorf("failed to save transcript correction: correction is nil")
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.corrections = append(r.corrections, correction)
+	return nil
+}
+
+// FindAll すべての修正履歴を取得します。
+func (r *TranscriptCorrectionRepository) FindAll() ([]*model.TranscriptCorrection, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	result := make([]*model.TranscriptCorrection, len(r.corrections))
+	copy(result, r.corrections)
+	return result, nil
+}
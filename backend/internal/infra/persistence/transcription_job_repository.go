@@ -1,20 +1,50 @@
 package persistence
 
 import (
+	"cmTranscribe/internal/domain/domainerr"
 	"cmTranscribe/internal/domain/model"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
 )
 
 // TranscriptionJobRepository 文字起こしジョブを管理するためのリポジトリです。
+// 状態の確認やメディアの名前の変更が並行して記録を更新するため、保存・取得時はコピーを扱います。
+// ファイルを指定した場合は保存のたびに全件を書き出し、再起動後も記録を引き継ぎます。
 type TranscriptionJobRepository struct {
-	jobs map[string]*model.TranscriptionJobDB
+	mu       sync.RWMutex
+	jobs     map[string]*model.TranscriptionJobDB
+	filePath string // 記録を書き出すファイル (空の場合はメモリ上にのみ保持する)
 }
 
-// NewTranscriptionJobRepository 新しいTranscriptionJobRepositoryを作成します。
-func NewTranscriptionJobRepository() (*TranscriptionJobRepository, error) {
-	return &TranscriptionJobRepository{
-		jobs: make(map[string]*model.TranscriptionJobDB),
-	}, nil
+// NewTranscriptionJobRepository 新しいTranscriptionJobRepositoryを作成します。filePath が存在する場合は記録を読み込みます
+func NewTranscriptionJobRepository(filePath string) (*TranscriptionJobRepository, error) {
+	repository := &TranscriptionJobRepository{
+		jobs:     make(map[string]*model.TranscriptionJobDB),
+		filePath: filePath,
+	}
+	if filePath == "" {
+		return repository, nil
+	}
+	data, err := os.ReadFile(filePath)
+	if errors.Is(err, os.ErrNotExist) {
+		return repository, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read transcription jobs file: %w", err)
+	}
+	var jobs []*model.TranscriptionJobDB
+	if err := json.Unmarshal(data, &jobs); err != nil {
+		return nil, fmt.Errorf("failed to parse transcription jobs file: %w", err)
+	}
+	for _, job := range jobs {
+		repository.jobs[job.JobName] = job
+	}
+	return repository, nil
 }
 
 // Save 文字起こしジョブを保存します。
@@ -22,15 +52,97 @@ func (r *TranscriptionJobRepository) Save(job *model.TranscriptionJobDB) error {
 	if job == nil {
 		return fmt.Errorf("failed to save transcription job: job is nil")
 	}
-	r.jobs[job.JobName] = job
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	j := *job
+	previous, existed := r.jobs[job.JobName]
+	r.jobs[job.JobName] = &j
+	if err := r.persist(); err != nil {
+		// 書き出せなかった記録はメモリ上にも残さない
+		if existed {
+			r.jobs[job.JobName] = previous
+		} else {
+			delete(r.jobs, job.JobName)
+		}
+		return err
+	}
+	return nil
+}
+
+// persist すべての記録をファイルに書き出します。途中で失敗しても元のファイルが壊れないよう、一時ファイルを置き換えます。
+// 呼び出し元で mu をロックします
+func (r *TranscriptionJobRepository) persist() error {
+	if r.filePath == "" {
+		return nil
+	}
+	jobs := make([]*model.TranscriptionJobDB, 0, len(r.jobs))
+	for _, job := range r.jobs {
+		jobs = append(jobs, job)
+	}
+	sort.Slice(jobs, func(i, j int) bool { return jobs[i].CreatedAt.Before(jobs[j].CreatedAt) })
+	data, err := json.Marshal(jobs)
+	if err != nil {
+		return fmt.Errorf("failed to encode transcription jobs: %w", err)
+	}
+
+	file, err := os.CreateTemp(filepath.Dir(r.filePath), filepath.Base(r.filePath)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to write transcription jobs file: %w", err)
+	}
+	_, err = file.Write(data)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(file.Name(), r.filePath)
+	}
+	if err != nil {
+		_ = os.Remove(file.Name())
+		return fmt.Errorf("failed to write transcription jobs file: %w", err)
+	}
 	return nil
 }
 
 // FindByID IDで文字起こしジョブを検索します。
 func (r *TranscriptionJobRepository) FindByID(id string) (*model.TranscriptionJobDB, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
 	job, exists := r.jobs[id]
 	if !exists {
-		return nil, fmt.Errorf("transcription job with JobName %s not found", id)
+		return nil, domainerr.New(domainerr.NotFound, "transcription_job_not_found", "transcription job %s does not exist", id)
+	}
+	j := *job
+	return &j, nil
+}
+
+// FindByMediaFileURI メディアファイルのURIを参照している文字起こしジョブを作成日時の順に取得します。
+func (r *TranscriptionJobRepository) FindByMediaFileURI(uri string) ([]*model.TranscriptionJobDB, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var result []*model.TranscriptionJobDB
+	for _, job := range r.jobs {
+		if job.MediaFileURI == uri {
+			j := *job
+			result = append(result, &j)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].CreatedAt.Before(result[j].CreatedAt) })
+	return result, nil
+}
+
+// FindAll すべての文字起こしジョブを作成日時の順に取得します。
+func (r *TranscriptionJobRepository) FindAll() ([]*model.TranscriptionJobDB, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	result := make([]*model.TranscriptionJobDB, 0, len(r.jobs))
+	for _, job := range r.jobs {
+		j := *job
+		result = append(result, &j)
 	}
-	return job, nil
+	sort.Slice(result, func(i, j int) bool { return result[i].CreatedAt.Before(result[j].CreatedAt) })
+	return result, nil
 }
@@ -0,0 +1,112 @@
+package persistence
+
+import (
+	"cmTranscribe/internal/domain/domainerr"
+	"cmTranscribe/internal/domain/model"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// UploadSessionRepository 再開可能なアップロードを管理するためのリポジトリです。
+// パートは並行して届くため、取得時はコピーを返します。
+type UploadSessionRepository struct {
+	mu       sync.RWMutex
+	sessions map[string]*model.UploadSession
+}
+
+// NewUploadSessionRepository 新しいUploadSessionRepositoryを作成します。
+func NewUploadSessionRepository() (*UploadSessionRepository, error) {
+	return &UploadSessionRepository{
+		sessions: make(map[string]*model.UploadSession),
+	}, nil
+}
+
+// Save アップロードを保存します。
+func (r *UploadSessionRepository) Save(session *model.UploadSession) error {
+	if session == nil {
+		return fmt.Errorf("failed to save upload session: session is nil")
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.sessions[session.ID] = session.Clone()
+	return nil
+}
+
+// SavePart 受け取ったパートを追加します。同じ番号のパートは置き換えます。
+// 並行して届いたパートでサイズの上限を超えたり、完了・中止の後にパートが追加されたりしないよう、ステータスとサイズをロックしたまま検証します。
+func (r *UploadSessionRepository) SavePart(id string, part *model.UploadPart) error {
+	if part == nil {
+		return fmt.Errorf("failed to save upload part: part is nil")
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	session, exists := r.sessions[id]
+	if !exists {
+		return domainerr.New(domainerr.NotFound, "upload_not_found", "upload %s does not exist", id)
+	}
+	if err := session.ValidatePart(part.Number, part.Size); err != nil {
+		return err
+	}
+	p := *part
+	session.Parts[part.Number] = &p
+	session.UpdatedAt = time.Now()
+	return nil
+}
+
+// Transition アップロードのステータスが from の場合だけ to に切り替え、切り替えた後のコピーを返します。
+func (r *UploadSessionRepository) Transition(id, from, to string) (*model.UploadSession, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	session, exists := r.sessions[id]
+	if !exists {
+		return nil, domainerr.New(domainerr.NotFound, "upload_not_found", "upload %s does not exist", id)
+	}
+	if err := session.CheckStatus(from); err != nil {
+		return nil, err
+	}
+	session.Status = to
+	session.UpdatedAt = time.Now()
+	return session.Clone(), nil
+}
+
+// FindExpired 最後に更新されてから expiry 以上経ったアップロードを取得します。
+func (r *UploadSessionRepository) FindExpired(now time.Time, expiry time.Duration) ([]*model.UploadSession, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var expired []*model.UploadSession
+	for _, session := range r.sessions {
+		if session.IsExpired(now, expiry) {
+			expired = append(expired, session.Clone())
+		}
+	}
+	return expired, nil
+}
+
+// FindByID IDでアップロードを検索します。
+func (r *UploadSessionRepository) FindByID(id string) (*model.UploadSession, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	session, exists := r.sessions[id]
+	if !exists {
+		return nil, domainerr.New(domainerr.NotFound, "upload_not_found", "upload %s does not exist", id)
+	}
+	return session.Clone(), nil
+}
+
+// Delete アップロードを削除します。
+func (r *UploadSessionRepository) Delete(id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.sessions[id]; !exists {
+		return domainerr.New(domainerr.NotFound, "upload_not_found", "upload %s does not exist", id)
+	}
+	delete(r.sessions, id)
+	return nil
+}
@@ -0,0 +1,63 @@
+package persistence
+
+import (
+	"cmTranscribe/internal/domain/domainerr"
+	"cmTranscribe/internal/domain/model"
+	"fmt"
+	"sync"
+)
+
+// VocabularyVersionRepository カスタムボキャブラリのバージョン履歴を管理するためのリポジトリです。
+type VocabularyVersionRepository struct {
+	mu       sync.RWMutex
+	versions map[string][]*model.VocabularyVersion
+}
+
+// NewVocabularyVersionRepository 新しいVocabularyVersionRepositoryを作成します。
+func NewVocabularyVersionRepository() (*VocabularyVersionRepository, error) {
+	return &VocabularyVersionRepository{
+		versions: make(map[string][]*model.VocabularyVersion),
+	}, nil
+}
+
+// Save バージョンを採番して保存します。
+func (r *VocabularyVersionRepository) Save(version *model.VocabularyVersion) error {
+	if version == nil {
+		return fmt.Errorf("failed to save vocabulary version: version is nil")
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	history := r.versions[version.VocabularyName]
+	var previous *model.VocabularyVersion
+	if len(history) > 0 {
+		previous = history[len(history)-1]
+	}
+	version.FollowOn(previous)
+	r.versions[version.VocabularyName] = append(history, version)
+	return nil
+}
+
+// FindByName ボキャブラリ名でバージョン履歴を古い順に取得します。
+func (r *VocabularyVersionRepository) FindByName(name string) ([]*model.VocabularyVersion, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	history := r.versions[name]
+	result := make([]*model.VocabularyVersion, len(history))
+	copy(result, history)
+	return result, nil
+}
+
+// FindByNameAndVersion ボキャブラリ名とバージョン番号で履歴を検索します。
+func (r *VocabularyVersionRepository) FindByNameAndVersion(name string, version int) (*model.VocabularyVersion, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, v := range r.versions[name] {
+		if v.Version == version {
+			return v, nil
+		}
+	}
+	return nil, domainerr.New(domainerr.NotFound, "vocabulary_version_not_found", "version %d of vocabulary %s does not exist", version, name)
+}
@@ -0,0 +1,58 @@
+package service
+
+import (
+	"cmTranscribe/internal/domain/domainerr"
+	"cmTranscribe/internal/domain/model"
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"strings"
+)
+
+// APIKeyAuthenticator は、設定した静的なAPIキーで呼び出し元を認証します
+type APIKeyAuthenticator struct {
+	principals map[[sha256.Size]byte]*model.Principal // キーのハッシュと呼び出し元の対応
+}
+
+// NewAPIKeyAuthenticator は、"<subject>[@<tenant>][:<role>|<role>...]=<key>" をカンマで区切った設定からAPIKeyAuthenticatorを作成します。
+// 例: "alice:admin=3f9a...,bob@team-a=8c1e..."
+func NewAPIKeyAuthenticator(entries, adminRole string) (*APIKeyAuthenticator, error) {
+	authenticator := &APIKeyAuthenticator{principals: map[[sha256.Size]byte]*model.Principal{}}
+	for _, entry := range strings.Split(entries, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		identity, key, ok := strings.Cut(entry, "=")
+		if !ok || identity == "" || key == "" {
+			return nil, fmt.Errorf("API key entry must be <subject>[@<tenant>][:<roles>]=<key>")
+		}
+		subject, roleList, _ := strings.Cut(identity, ":")
+		subject, tenant, _ := strings.Cut(subject, "@")
+		var roles []string
+		for _, role := range strings.Split(roleList, "|") {
+			if role = strings.TrimSpace(role); role != "" {
+				roles = append(roles, role)
+			}
+		}
+		digest := sha256.Sum256([]byte(key))
+		if _, exists := authenticator.principals[digest]; exists {
+			return nil, fmt.Errorf("API key of %s is already assigned to another subject", subject)
+		}
+		authenticator.principals[digest] = model.NewPrincipal(strings.TrimSpace(subject), roles, adminRole).WithTenant(strings.TrimSpace(tenant))
+	}
+	if len(authenticator.principals) == 0 {
+		return nil, fmt.Errorf("no API keys are configured")
+	}
+	return authenticator, nil
+}
+
+// Authenticate は、APIキーに対応する呼び出し元を返します。
+// キーそのものではなくハッシュで照合するため、比較にかかる時間からキーを推測されることはありません
+func (a *APIKeyAuthenticator) Authenticate(ctx context.Context, credential string) (*model.Principal, error) {
+	principal, ok := a.principals[sha256.Sum256([]byte(credential))]
+	if !ok {
+		return nil, domainerr.New(domainerr.Unauthorized, "invalid_credentials", "API key is invalid")
+	}
+	return principal, nil
+}
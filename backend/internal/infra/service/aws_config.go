@@ -0,0 +1,69 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/transcribe"
+)
+
+// AWSSettings AWSクライアントの接続先と認証情報の設定。LocalStackやMinIOに接続する場合に使います
+type AWSSettings struct {
+	Region             string
+	EndpointURL        string // すべてのサービスのエンドポイント (空の場合はAWS)
+	S3EndpointURL      string // S3のエンドポイント (空の場合はEndpointURL)
+	S3PublicURL        string // ブラウザに渡すアップロード用の署名付きURLのエンドポイント (空の場合はS3のエンドポイント)
+	S3UsePathStyle     bool   // バケット名をホスト名ではなくパスに含める (MinIOなど)
+	TranscribeEndpoint string // Amazon Transcribeのエンドポイント (空の場合はEndpointURL)
+	AccessKeyID        string // 静的な認証情報 (空の場合は環境変数や共有設定ファイルから取得する)
+	SecretAccessKey    string
+	SessionToken       string
+}
+
+// LoadAWSConfig は、すべてのAWSクライアントで共有する設定を読み込みます。
+// 静的な認証情報が指定された場合は、共有設定ファイルのプロファイルなどより優先して使います
+func LoadAWSConfig(ctx context.Context, settings AWSSettings) (aws.Config, error) {
+	options := []func(*config.LoadOptions) error{config.WithRegion(settings.Region)}
+	if settings.AccessKeyID != "" && settings.SecretAccessKey != "" {
+		credentials := aws.Credentials{
+			AccessKeyID:     settings.AccessKeyID,
+			SecretAccessKey: settings.SecretAccessKey,
+			SessionToken:    settings.SessionToken,
+			Source:          "StaticCredentials",
+		}
+		options = append(options, config.WithCredentialsProvider(aws.CredentialsProviderFunc(func(context.Context) (aws.Credentials, error) {
+			return credentials, nil
+		})))
+	}
+
+	cfg, err := config.LoadDefaultConfig(ctx, options...)
+	if err != nil {
+		return aws.Config{}, fmt.Errorf("failed to load AWS config: %w", err)
+	}
+	if settings.EndpointURL != "" {
+		cfg.BaseEndpoint = aws.String(settings.EndpointURL)
+	}
+	cfg.APIOptions = append(cfg.APIOptions, addAWSCallLogging)
+	return cfg, nil
+}
+
+// newS3Client は、エンドポイントとアドレス指定の方式を反映したS3クライアントを作成します
+func newS3Client(cfg aws.Config, settings AWSSettings, endpointURL string) *s3.Client {
+	return s3.NewFromConfig(cfg, func(options *s3.Options) {
+		if endpointURL != "" {
+			options.BaseEndpoint = aws.String(endpointURL)
+		}
+		options.UsePathStyle = settings.S3UsePathStyle
+	})
+}
+
+// newTranscribeClient は、エンドポイントを反映したAmazon Transcribeのクライアントを作成します
+func newTranscribeClient(cfg aws.Config, settings AWSSettings) *transcribe.Client {
+	return transcribe.NewFromConfig(cfg, func(options *transcribe.Options) {
+		if settings.TranscribeEndpoint != "" {
+			options.BaseEndpoint = aws.String(settings.TranscribeEndpoint)
+		}
+	})
+}
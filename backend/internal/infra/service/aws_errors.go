@@ -0,0 +1,74 @@
+package service
+
+import (
+	"cmTranscribe/internal/domain/domainerr"
+	"context"
+	"errors"
+	"fmt"
+	"github.com/aws/smithy-go"
+	"strings"
+)
+
+// AWSのエラーコードとドメインエラーの種類の対応
+var (
+	awsNotFoundCodes = map[string]bool{
+		"NotFoundException": true,
+		"NotFound":          true,
+		"NoSuchKey":         true,
+		"NoSuchUpload":      true,
+	}
+	awsThrottledCodes = map[string]bool{
+		"LimitExceededException":   true,
+		"ThrottlingException":      true,
+		"Throttling":               true,
+		"TooManyRequestsException": true,
+		"RequestLimitExceeded":     true,
+		"SlowDown":                 true,
+	}
+	awsAccessDeniedCodes = map[string]bool{
+		"AccessDenied":                true,
+		"AccessDeniedException":       true,
+		"UnrecognizedClientException": true,
+		"InvalidAccessKeyId":          true,
+		"InvalidClientTokenId":        true,
+		"InvalidSignatureException":   true,
+		"SignatureDoesNotMatch":       true,
+		"ExpiredToken":                true,
+		"ExpiredTokenException":       true,
+	}
+)
+
+// translateAWSError AWS APIのエラーをドメインエラーに変換します。
+// resource はエラーコードとメッセージに使う対象の名前 (例: "custom vocabulary")、name はその識別子、
+// operation は変換できないエラーのメッセージ (例: "failed to get custom vocabulary") です。
+// AWSの認証情報の問題は呼び出し元の認証とは無関係なため、上流サービスのエラーとして扱います
+func translateAWSError(err error, resource, name, operation string) error {
+	// キャンセルや、読み込み中のボディが返したドメインエラーはそのまま伝える
+	if _, ok := domainerr.As(err); ok || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return fmt.Errorf("%s: %w", operation, err)
+	}
+
+	codePrefix := strings.ReplaceAll(resource, " ", "_")
+	var apiErr smithy.APIError
+	if !errors.As(err, &apiErr) {
+		return domainerr.Wrap(domainerr.Upstream, "upstream_unavailable", err, "%s", operation)
+	}
+
+	code := apiErr.ErrorCode()
+	switch {
+	// Transcribe は存在しないリソースを BadRequestException として返す
+	case awsNotFoundCodes[code], code == "BadRequestException" && strings.Contains(apiErr.ErrorMessage(), "couldn't be found"):
+		return domainerr.Wrap(domainerr.NotFound, codePrefix+"_not_found", err, "%s %s does not exist", resource, name)
+	case code == "ConflictException":
+		// 名前の重複と、処理中のリソースの更新のどちらも ConflictException として返される
+		return domainerr.Wrap(domainerr.Conflict, codePrefix+"_conflict", err, "%s: %s", operation, apiErr.ErrorMessage())
+	case code == "BadRequestException":
+		return domainerr.Wrap(domainerr.Validation, codePrefix+"_rejected", err, "%s: %s", operation, apiErr.ErrorMessage())
+	case awsThrottledCodes[code]:
+		return domainerr.Wrap(domainerr.Throttled, "upstream_throttled", err, "%s: AWS is throttling requests, retry later", operation)
+	case awsAccessDeniedCodes[code]:
+		return domainerr.Wrap(domainerr.Upstream, "upstream_access_denied", err, "%s: AWS rejected the server credentials", operation)
+	default:
+		return domainerr.Wrap(domainerr.Upstream, "upstream_error", err, "%s", operation)
+	}
+}
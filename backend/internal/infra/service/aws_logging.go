@@ -0,0 +1,73 @@
+package service
+
+import (
+	"cmTranscribe/internal/shared/logger"
+	"cmTranscribe/internal/shared/metrics"
+	"context"
+	"errors"
+	awsmiddleware "github.com/aws/aws-sdk-go-v2/aws/middleware"
+	"github.com/aws/smithy-go"
+	"github.com/aws/smithy-go/middleware"
+	smithyhttp "github.com/aws/smithy-go/transport/http"
+	"time"
+)
+
+// addAWSCallLogging は、AWS APIの呼び出しごとに操作名、レイテンシ、AWSのリクエストIDをログに出力し、メトリクスに記録するミドルウェアを追加します。
+// リトライも1回の呼び出しとして出力します。署名付きURLの生成はリクエストを送らないため出力しません
+func addAWSCallLogging(stack *middleware.Stack) error {
+	return stack.Deserialize.Add(middleware.DeserializeMiddlewareFunc("LogAWSCall", logAWSCall), middleware.Before)
+}
+
+func logAWSCall(ctx context.Context, in middleware.DeserializeInput, next middleware.DeserializeHandler) (
+	out middleware.DeserializeOutput, metadata middleware.Metadata, err error,
+) {
+	start := time.Now()
+	out, metadata, err = next.HandleDeserialize(ctx, in)
+
+	serviceID, operation := awsmiddleware.GetServiceID(ctx), awsmiddleware.GetOperationName(ctx)
+	metrics.AWSCalls.Inc(serviceID, operation)
+	metrics.ObserveSince(metrics.AWSCallDuration, start, serviceID, operation)
+	if err != nil {
+		metrics.AWSCallErrors.Inc(serviceID, operation, awsErrorCode(err))
+	}
+
+	keyvals := []interface{}{
+		"service", serviceID,
+		"operation", operation,
+		"duration_ms", float64(time.Since(start).Microseconds()) / 1000,
+		"aws_request_id", awsRequestID(out, metadata),
+	}
+	if resp, ok := out.RawResponse.(*smithyhttp.Response); ok {
+		keyvals = append(keyvals, "status", resp.StatusCode)
+	}
+	if err != nil {
+		logger.FromContext(ctx).Warn("AWS call failed", append(keyvals, "error", err)...)
+	} else {
+		logger.FromContext(ctx).Info("AWS call", keyvals...)
+	}
+	return out, metadata, err
+}
+
+// awsErrorCode は、メトリクスのラベルに使うAWSのエラーコードを返します。AWSのエラーではない場合 (通信の失敗など) は "unknown" です
+func awsErrorCode(err error) string {
+	var apiErr smithy.APIError
+	if errors.As(err, &apiErr) && apiErr.ErrorCode() != "" {
+		return apiErr.ErrorCode()
+	}
+	return "unknown"
+}
+
+// awsRequestID は、レスポンスからAWSのリクエストIDを取り出します
+func awsRequestID(out middleware.DeserializeOutput, metadata middleware.Metadata) string {
+	if requestID, ok := awsmiddleware.GetRequestIDMetadata(metadata); ok {
+		return requestID
+	}
+	if resp, ok := out.RawResponse.(*smithyhttp.Response); ok {
+		for _, header := range []string{"X-Amzn-Requestid", "X-Amz-Request-Id", "X-Amz-Requestid"} {
+			if value := resp.Header.Get(header); value != "" {
+				return value
+			}
+		}
+	}
+	return ""
+}
@@ -5,13 +5,9 @@ import (
 	"context"
 	"fmt"
 	"github.com/aws/aws-sdk-go-v2/aws"
-	"github.com/aws/aws-sdk-go-v2/config"
 	"github.com/aws/aws-sdk-go-v2/service/s3"
 	"github.com/aws/aws-sdk-go-v2/service/transcribe"
 	"github.com/aws/aws-sdk-go-v2/service/transcribe/types"
-	"github.com/aws/smithy-go"
-	"github.com/pkg/errors"
-	"log"
 )
 
 type CustomVocabularyService struct {
@@ -20,33 +16,28 @@ type CustomVocabularyService struct {
 }
 
 // NewCustomVocabularyService 新しいAWSCustomVocabularyServiceを作成します
-func NewCustomVocabularyService(ctx context.Context, region string) (*CustomVocabularyService, error) {
-	// デフォルト設定とリージョンを使用してAWS設定を読み込み
-	cfg, err := config.LoadDefaultConfig(ctx, config.WithRegion(region))
-	if err != nil {
-		return nil, err
-	}
-
+func NewCustomVocabularyService(cfg aws.Config, settings AWSSettings) *CustomVocabularyService {
 	return &CustomVocabularyService{
-		client:   transcribe.NewFromConfig(cfg),
-		s3Client: s3.NewFromConfig(cfg),
-	}, nil
+		client:   newTranscribeClient(cfg, settings),
+		s3Client: newS3Client(cfg, settings, settings.S3EndpointURL),
+	}
 }
 
 func (s *CustomVocabularyService) CreateCustomVocabulary(ctx context.Context, vocabulary model.CustomVocabulary) error {
 	input := &transcribe.CreateVocabularyInput{
-		LanguageCode:      types.LanguageCode(vocabulary.LanguageCode),
-		VocabularyName:    aws.String(vocabulary.VocabularyName),
-		VocabularyFileUri: aws.String(vocabulary.FileUri),
+		LanguageCode:   types.LanguageCode(vocabulary.LanguageCode),
+		VocabularyName: aws.String(vocabulary.VocabularyName),
+	}
+	// フレーズリスト形式の場合はS3のファイルを使わない
+	if vocabulary.IsPhraseList() {
+		input.Phrases = vocabulary.Phrases
+	} else {
+		input.VocabularyFileUri = aws.String(vocabulary.FileUri)
 	}
 
 	_, err := s.client.CreateVocabulary(ctx, input)
 	if err != nil {
-		var awsErr smithy.APIError
-		if errors.As(err, &awsErr) && awsErr.ErrorCode() == "ConflictException" {
-			return fmt.Errorf("conflict: custom vocabulary name already exists")
-		}
-		return fmt.Errorf("failed to create custom vocabulary: %v", err)
+		return translateAWSError(err, "custom vocabulary", vocabulary.VocabularyName, "failed to create custom vocabulary")
 	}
 
 	return nil
@@ -55,18 +46,20 @@ func (s *CustomVocabularyService) CreateCustomVocabulary(ctx context.Context, vo
 // UpdateCustomVocabulary updates an existing custom vocabulary
 func (s *CustomVocabularyService) UpdateCustomVocabulary(ctx context.Context, vocabulary model.CustomVocabulary) error {
 	input := &transcribe.UpdateVocabularyInput{
-		LanguageCode:      types.LanguageCode(vocabulary.LanguageCode),
-		VocabularyName:    aws.String(vocabulary.VocabularyName),
-		VocabularyFileUri: aws.String(vocabulary.FileUri),
+		LanguageCode:   types.LanguageCode(vocabulary.LanguageCode),
+		VocabularyName: aws.String(vocabulary.VocabularyName),
+	}
+	// フレーズリスト形式の場合はS3のファイルを使わない
+	if vocabulary.IsPhraseList() {
+		input.Phrases = vocabulary.Phrases
+	} else {
+		input.VocabularyFileUri = aws.String(vocabulary.FileUri)
 	}
 
 	_, err := s.client.UpdateVocabulary(ctx, input)
 	if err != nil {
-		var awsErr smithy.APIError
-		if errors.As(err, &awsErr) && awsErr.ErrorCode() == "ConflictException" {
-			return fmt.Errorf("conflict: custom vocabulary name already exists")
-		}
-		return fmt.Errorf("failed to update custom vocabulary: %v", err)
+		// 処理中のボキャブラリの更新は ConflictException として返される
+		return translateAWSError(err, "custom vocabulary", vocabulary.VocabularyName, "failed to update custom vocabulary")
 	}
 
 	return nil
@@ -98,14 +91,7 @@ func (s *CustomVocabularyService) GetCustomVocabularyByName(ctx context.Context,
 	// API リクエスト
 	result, err := s.client.GetVocabulary(ctx, input)
 	if err != nil {
-		// AWS SDK v2のエラーハンドリング
-		var apiErr smithy.APIError
-		if errors.As(err, &apiErr) {
-			log.Printf("Failed to get custom vocabulary: %s (code: %s)", apiErr.ErrorMessage(), apiErr.ErrorCode())
-		} else {
-			log.Printf("Failed to get custom vocabulary: %v", err)
-		}
-		return nil, fmt.Errorf("failed to get custom vocabulary: %v", err)
+		return nil, translateAWSError(err, "custom vocabulary", name, "failed to get custom vocabulary")
 	}
 
 	// 取得した結果をドメインモデルに変換
@@ -115,7 +101,43 @@ func (s *CustomVocabularyService) GetCustomVocabularyByName(ctx context.Context,
 		FileUri:                    aws.ToString(result.DownloadUri),
 		VocabularyState:            string(result.VocabularyState),
 		VocabularyLastModifiedTime: aws.ToTime(result.LastModifiedTime),
+		FailureReason:              aws.ToString(result.FailureReason),
 	}
 
 	return vocabulary, nil
 }
+
+// ListCustomVocabularies カスタムボキャブラリの一覧を取得します (語彙リストのURIは含みません)
+func (s *CustomVocabularyService) ListCustomVocabularies(ctx context.Context) ([]*model.CustomVocabularyResponse, error) {
+	var vocabularies []*model.CustomVocabularyResponse
+	paginator := transcribe.NewListVocabulariesPaginator(s.client, &transcribe.ListVocabulariesInput{
+		MaxResults: aws.Int32(100),
+	})
+	for paginator.HasMorePages() {
+		output, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, translateAWSError(err, "custom vocabulary", "", "failed to list custom vocabularies")
+		}
+		for _, vocabulary := range output.Vocabularies {
+			vocabularies = append(vocabularies, model.NewCustomVocabularyResponse(
+				aws.ToString(vocabulary.VocabularyName),
+				string(vocabulary.LanguageCode),
+				"",
+				string(vocabulary.VocabularyState),
+				aws.ToTime(vocabulary.LastModifiedTime),
+			))
+		}
+	}
+	return vocabularies, nil
+}
+
+// DeleteCustomVocabulary カスタムボキャブラリを削除します
+func (s *CustomVocabularyService) DeleteCustomVocabulary(ctx context.Context, name string) error {
+	_, err := s.client.DeleteVocabulary(ctx, &transcribe.DeleteVocabularyInput{
+		VocabularyName: aws.String(name),
+	})
+	if err != nil {
+		return translateAWSError(err, "custom vocabulary", name, "failed to delete custom vocabulary")
+	}
+	return nil
+}
@@ -0,0 +1,865 @@
+package service
+
+import (
+	"bytes"
+	"cmTranscribe/internal/domain/domainerr"
+	"cmTranscribe/internal/domain/model"
+	domainService "cmTranscribe/internal/domain/service"
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/transcribe/types"
+	"hash/fnv"
+	"io"
+	"os"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+// fakeVocabularyDownloadExpiry 語彙ファイルのダウンロードURLの有効期限
+const fakeVocabularyDownloadExpiry = 15 * time.Minute
+
+// fakeTranscriptPunctuation 句読点として出力する文字
+const fakeTranscriptPunctuation = ".,!?。、！？"
+
+// fakeJob 実行中または完了した文字起こしジョブ
+type fakeJob struct {
+	job            model.TranscriptionJob
+	status         types.TranscriptionJobStatus
+	createdAt      time.Time
+	completedAt    *time.Time
+	failureReason  string
+	outputLocation string
+	completing     bool // ロックの外で文字起こし結果を出力している
+}
+
+// fakeVocabulary 作成したカスタムボキャブラリ
+type fakeVocabulary struct {
+	vocabulary    model.CustomVocabulary
+	state         types.VocabularyState
+	modifiedAt    time.Time
+	failureReason string
+	entries       []model.VocabularyEntry // READYになった時点の語彙
+	loading       bool                    // ロックの外で語彙ファイルを読み込んでいる
+}
+
+// fakeVocabularyFilter 作成した語彙フィルタ
+type fakeVocabularyFilter struct {
+	filter     model.VocabularyFilter
+	words      map[string]bool // 小文字にしたフィルタの単語
+	modifiedAt time.Time
+}
+
+// fakeJobCompletion ロックの外で文字起こし結果を出力するジョブと、出力に使う語彙と語彙フィルタ
+type fakeJobCompletion struct {
+	job         *fakeJob
+	input       model.TranscriptionJob
+	phrases     []fakeTranscriptPhrase
+	filterWords map[string]bool
+}
+
+// fakeVocabularyLoad ロックの外で語彙ファイルを読み込むボキャブラリ
+type fakeVocabularyLoad struct {
+	vocabulary *fakeVocabulary
+	input      model.CustomVocabulary
+}
+
+// fakeTranscriptPhrase 文字起こし結果で1語として扱うボキャブラリの語彙
+type fakeTranscriptPhrase struct {
+	text      string // 文字起こしのテキスト中で一致させる文字列
+	displayAs string // 出力する文字列
+}
+
+// FakeTranscribeEngine は AWS を使わずに Amazon Transcribe の動作を再現するインプロセスの実装です。
+// ドメインの TranscriptionJobService、CustomVocabularyService、VocabularyFilterService を実装し、開発やテストで認証情報なしに使えます。
+//   - ジョブは jobDuration の半分で QUEUED から IN_PROGRESS になり、jobDuration が経過すると
+//     文字起こし結果の JSON を出力バケットの <ジョブ名>.json に書き込んで COMPLETED になります。
+//     メディアが存在しない場合は FAILED になります。
+//   - 文字起こしの内容は、メディアと同じキーで拡張子を .txt にしたテキストファイルがあればその内容、
+//     無ければ fixture (Amazon Transcribe の出力 JSON)、どちらも無ければ固定の文から生成します。
+//   - ボキャブラリは vocabularyDelay が経過すると PENDING から READY (語彙ファイルを読めない場合は FAILED) になります。
+//   - 語彙フィルタは作成時に単語リストを読み込み、ジョブの文字起こし結果で一致した単語を mask、remove、tag します。
+//
+// 状態は取得のたびに経過時間から進めるため、バックグラウンドの処理はありません。
+// ストレージの読み書きはロックを持たずに行い、その間の状態は completing や loading で示します
+type FakeTranscribeEngine struct {
+	storage         domainService.S3StorageService
+	outputBucket    string
+	jobDuration     time.Duration
+	vocabularyDelay time.Duration
+	fixture         []byte
+
+	mu           sync.Mutex
+	jobs         map[string]*fakeJob
+	vocabularies map[string]*fakeVocabulary
+	filters      map[string]*fakeVocabularyFilter
+}
+
+// NewFakeTranscribeEngine は FakeTranscribeEngine のインスタンスを生成します。fixtureFile は省略できます
+func NewFakeTranscribeEngine(storage domainService.S3StorageService, outputBucket string, jobDuration, vocabularyDelay time.Duration, fixtureFile string) (*FakeTranscribeEngine, error) {
+	engine := &FakeTranscribeEngine{
+		storage:         storage,
+		outputBucket:    outputBucket,
+		jobDuration:     jobDuration,
+		vocabularyDelay: vocabularyDelay,
+		jobs:            make(map[string]*fakeJob),
+		vocabularies:    make(map[string]*fakeVocabulary),
+		filters:         make(map[string]*fakeVocabularyFilter),
+	}
+	if fixtureFile != "" {
+		fixture, err := os.ReadFile(fixtureFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read transcript fixture: %w", err)
+		}
+		if _, err := model.ParseTranscript(fixture); err != nil {
+			return nil, fmt.Errorf("transcript fixture is invalid: %w", err)
+		}
+		engine.fixture = fixture
+	}
+	return engine, nil
+}
+
+// StartTranscriptionJob ジョブを QUEUED として登録します。同じ名前のジョブがある場合は ConflictException と同じエラーを返します
+func (e *FakeTranscribeEngine) StartTranscriptionJob(ctx context.Context, input *model.TranscriptionJob) (*model.TranscriptionJobStatusResponse, error) {
+	e.advance(ctx)
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if _, exists := e.jobs[input.JobName]; exists {
+		return nil, domainerr.New(domainerr.Conflict, "transcription_job_conflict", "transcription job %s already exists", input.JobName)
+	}
+	if input.CustomVocabularyName != "" {
+		vocabulary, exists := e.vocabularies[input.CustomVocabularyName]
+		if !exists || vocabulary.state != types.VocabularyStateReady {
+			return nil, domainerr.New(domainerr.Validation, "transcription_job_rejected", "failed to start transcription job: the requested vocabulary %s couldn't be found or isn't ready", input.CustomVocabularyName)
+		}
+	}
+	if input.VocabularyFilterName != "" {
+		if _, exists := e.filters[input.VocabularyFilterName]; !exists {
+			return nil, domainerr.New(domainerr.Validation, "transcription_job_rejected", "failed to start transcription job: the requested vocabulary filter %s couldn't be found", input.VocabularyFilterName)
+		}
+	}
+
+	job := &fakeJob{
+		job:       *input,
+		status:    types.TranscriptionJobStatusQueued,
+		createdAt: time.Now(),
+	}
+	e.jobs[input.JobName] = job
+	return model.NewTranscriptionJobStatusResponse(input.JobName, string(job.status)), nil
+}
+
+// GetTranscriptionJob ジョブの状態を経過時間に応じて進めてから返します
+func (e *FakeTranscribeEngine) GetTranscriptionJob(ctx context.Context, jobName string) (*model.TranscriptionJobResponse, error) {
+	e.advance(ctx)
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	job, exists := e.jobs[jobName]
+	if !exists {
+		return nil, domainerr.New(domainerr.NotFound, "transcription_job_not_found", "transcription job %s does not exist", jobName)
+	}
+	return model.NewTranscriptionJobResponse(job.toTranscriptionJob()), nil
+}
+
+// GetTranscriptionJobList 条件に合うジョブの一覧を新しい順に1ページ分返します。NextToken は次のページの先頭の位置です
+func (e *FakeTranscribeEngine) GetTranscriptionJobList(ctx context.Context, query model.TranscriptionJobListQuery) (*model.TranscriptionJobSummariesResponse, error) {
+	e.advance(ctx)
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	offset := 0
+	if query.NextToken != "" {
+		parsed, err := strconv.Atoi(query.NextToken)
+		if err != nil || parsed < 0 {
+			return nil, domainerr.New(domainerr.Validation, "transcription_job_rejected", "failed to list transcription jobs: the NextToken is invalid")
+		}
+		offset = parsed
+	}
+	maxResults := query.MaxResults
+	if maxResults <= 0 || maxResults > model.MaxTranscriptionJobPageSize {
+		maxResults = model.MaxTranscriptionJobPageSize
+	}
+
+	jobs := make([]*fakeJob, 0, len(e.jobs))
+	for _, job := range e.jobs {
+		if strings.Contains(job.job.JobName, query.NameContains) && (query.Status == "" || string(job.status) == query.Status) {
+			jobs = append(jobs, job)
+		}
+	}
+	sort.Slice(jobs, func(i, j int) bool {
+		return jobs[i].createdAt.After(jobs[j].createdAt)
+	})
+
+	nextToken := ""
+	if offset > len(jobs) {
+		offset = len(jobs)
+	}
+	if end := offset + maxResults; end < len(jobs) {
+		jobs, nextToken = jobs[offset:end], strconv.Itoa(end)
+	} else {
+		jobs = jobs[offset:]
+	}
+
+	summaries := make([]types.TranscriptionJobSummary, 0, len(jobs))
+	for _, job := range jobs {
+		summaries = append(summaries, types.TranscriptionJobSummary{
+			TranscriptionJobName:   aws.String(job.job.JobName),
+			CreationTime:           aws.Time(job.createdAt),
+			CompletionTime:         job.completedAt,
+			LanguageCode:           types.LanguageCode(job.job.LanguageCode),
+			TranscriptionJobStatus: job.status,
+			OutputLocationType:     types.OutputLocationTypeCustomerBucket,
+			FailureReason:          optionalString(job.failureReason),
+		})
+	}
+	return model.NewTranscriptionJobSummariesResponse(summaries, nextToken), nil
+}
+
+// CountActiveTranscriptionJobs QUEUED または IN_PROGRESS のジョブの数を返します
+func (e *FakeTranscribeEngine) CountActiveTranscriptionJobs(ctx context.Context) (int, error) {
+	e.advance(ctx)
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	count := 0
+	for _, job := range e.jobs {
+		if job.status == types.TranscriptionJobStatusQueued || job.status == types.TranscriptionJobStatusInProgress {
+			count++
+		}
+	}
+	return count, nil
+}
+
+// CreateCustomVocabulary ボキャブラリを PENDING として登録します。同じ名前のボキャブラリがある場合は ConflictException と同じエラーを返します
+func (e *FakeTranscribeEngine) CreateCustomVocabulary(ctx context.Context, vocabulary model.CustomVocabulary) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if _, exists := e.vocabularies[vocabulary.VocabularyName]; exists {
+		return domainerr.New(domainerr.Conflict, "custom_vocabulary_conflict", "custom vocabulary %s already exists", vocabulary.VocabularyName)
+	}
+	e.vocabularies[vocabulary.VocabularyName] = &fakeVocabulary{
+		vocabulary: vocabulary,
+		state:      types.VocabularyStatePending,
+		modifiedAt: time.Now(),
+	}
+	return nil
+}
+
+// UpdateCustomVocabulary ボキャブラリの内容を置き換えて PENDING に戻します。処理中のボキャブラリは更新できません
+func (e *FakeTranscribeEngine) UpdateCustomVocabulary(ctx context.Context, vocabulary model.CustomVocabulary) error {
+	e.advance(ctx)
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	current, exists := e.vocabularies[vocabulary.VocabularyName]
+	if !exists {
+		return domainerr.New(domainerr.NotFound, "custom_vocabulary_not_found", "custom vocabulary %s does not exist", vocabulary.VocabularyName)
+	}
+	if current.state == types.VocabularyStatePending {
+		return domainerr.New(domainerr.Conflict, "custom_vocabulary_conflict", "custom vocabulary %s is still being processed", vocabulary.VocabularyName)
+	}
+
+	current.vocabulary = vocabulary
+	current.state = types.VocabularyStatePending
+	current.modifiedAt = time.Now()
+	current.failureReason = ""
+	return nil
+}
+
+// GetCustomVocabularyByName ボキャブラリの状態を経過時間に応じて進めてから返します
+func (e *FakeTranscribeEngine) GetCustomVocabularyByName(ctx context.Context, name string) (*model.CustomVocabularyResponse, error) {
+	e.advance(ctx)
+	e.mu.Lock()
+	vocabulary, exists := e.vocabularies[name]
+	if !exists {
+		e.mu.Unlock()
+		return nil, domainerr.New(domainerr.NotFound, "custom_vocabulary_not_found", "custom vocabulary %s does not exist", name)
+	}
+	response := model.NewCustomVocabularyResponse(name, vocabulary.vocabulary.LanguageCode, "", string(vocabulary.state), vocabulary.modifiedAt)
+	response.FailureReason = vocabulary.failureReason
+	current := vocabulary.vocabulary
+	e.mu.Unlock()
+
+	// Amazon Transcribe と同じく、語彙ファイルはダウンロード用のURLで返す
+	if bucketName, key, ok := model.ParseS3URI(current.FileUri); ok && !current.IsPhraseList() {
+		uri, err := e.storage.PresignDownload(ctx, bucketName, key, fakeVocabularyDownloadExpiry)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get custom vocabulary: %w", err)
+		}
+		response.FileUri = uri
+	}
+	return response, nil
+}
+
+// ListCustomVocabularies ボキャブラリの一覧を名前の順に返します (語彙リストのURIは含みません)
+func (e *FakeTranscribeEngine) ListCustomVocabularies(ctx context.Context) ([]*model.CustomVocabularyResponse, error) {
+	e.advance(ctx)
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	names := make([]string, 0, len(e.vocabularies))
+	for name := range e.vocabularies {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	vocabularies := make([]*model.CustomVocabularyResponse, 0, len(names))
+	for _, name := range names {
+		vocabulary := e.vocabularies[name]
+		vocabularies = append(vocabularies, model.NewCustomVocabularyResponse(name, vocabulary.vocabulary.LanguageCode, "", string(vocabulary.state), vocabulary.modifiedAt))
+	}
+	return vocabularies, nil
+}
+
+// DeleteCustomVocabulary ボキャブラリを削除します
+func (e *FakeTranscribeEngine) DeleteCustomVocabulary(ctx context.Context, name string) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if _, exists := e.vocabularies[name]; !exists {
+		return domainerr.New(domainerr.NotFound, "custom_vocabulary_not_found", "custom vocabulary %s does not exist", name)
+	}
+	delete(e.vocabularies, name)
+	return nil
+}
+
+// CreateVocabularyFilter 単語リストを読み込んで語彙フィルタを登録します。同じ名前の語彙フィルタがある場合は ConflictException と同じエラーを返します
+func (e *FakeTranscribeEngine) CreateVocabularyFilter(ctx context.Context, filter model.VocabularyFilter) error {
+	e.mu.Lock()
+	_, exists := e.filters[filter.FilterName]
+	e.mu.Unlock()
+	if exists {
+		return domainerr.New(domainerr.Conflict, "vocabulary_filter_conflict", "vocabulary filter %s already exists", filter.FilterName)
+	}
+
+	words, err := e.loadFilterWords(ctx, filter)
+	if err != nil {
+		return err
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	// 単語リストの読み込み中に同じ名前で作成された
+	if _, exists := e.filters[filter.FilterName]; exists {
+		return domainerr.New(domainerr.Conflict, "vocabulary_filter_conflict", "vocabulary filter %s already exists", filter.FilterName)
+	}
+	e.filters[filter.FilterName] = &fakeVocabularyFilter{filter: filter, words: words, modifiedAt: time.Now()}
+	return nil
+}
+
+// UpdateVocabularyFilter 単語リストを読み込み直して語彙フィルタの単語を置き換えます
+func (e *FakeTranscribeEngine) UpdateVocabularyFilter(ctx context.Context, filter model.VocabularyFilter) error {
+	e.mu.Lock()
+	current, exists := e.filters[filter.FilterName]
+	e.mu.Unlock()
+	if !exists {
+		return domainerr.New(domainerr.NotFound, "vocabulary_filter_not_found", "vocabulary filter %s does not exist", filter.FilterName)
+	}
+
+	words, err := e.loadFilterWords(ctx, filter)
+	if err != nil {
+		return err
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	// 単語リストの読み込み中に削除された
+	if e.filters[filter.FilterName] != current {
+		return domainerr.New(domainerr.NotFound, "vocabulary_filter_not_found", "vocabulary filter %s does not exist", filter.FilterName)
+	}
+	// 言語コードは作成時のものを引き継ぐ
+	filter.LanguageCode = current.filter.LanguageCode
+	e.filters[filter.FilterName] = &fakeVocabularyFilter{filter: filter, words: words, modifiedAt: time.Now()}
+	return nil
+}
+
+// GetVocabularyFilter 語彙フィルタを返します。単語リストはダウンロード用のURLで返します
+func (e *FakeTranscribeEngine) GetVocabularyFilter(ctx context.Context, name string) (*model.VocabularyFilterResponse, error) {
+	e.mu.Lock()
+	filter, exists := e.filters[name]
+	e.mu.Unlock()
+	if !exists {
+		return nil, domainerr.New(domainerr.NotFound, "vocabulary_filter_not_found", "vocabulary filter %s does not exist", name)
+	}
+
+	response := &model.VocabularyFilterResponse{
+		FilterName:       name,
+		LanguageCode:     filter.filter.LanguageCode,
+		LastModifiedTime: filter.modifiedAt,
+	}
+	if bucketName, key, ok := model.ParseS3URI(filter.filter.FileUri); ok {
+		uri, err := e.storage.PresignDownload(ctx, bucketName, key, fakeVocabularyDownloadExpiry)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get vocabulary filter: %w", err)
+		}
+		response.DownloadUri = uri
+	}
+	return response, nil
+}
+
+// ListVocabularyFilters 語彙フィルタの一覧を名前の順に返します (単語リストのURIは含みません)
+func (e *FakeTranscribeEngine) ListVocabularyFilters(ctx context.Context) ([]*model.VocabularyFilterResponse, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	names := make([]string, 0, len(e.filters))
+	for name := range e.filters {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	filters := make([]*model.VocabularyFilterResponse, 0, len(names))
+	for _, name := range names {
+		filter := e.filters[name]
+		filters = append(filters, &model.VocabularyFilterResponse{
+			FilterName:       name,
+			LanguageCode:     filter.filter.LanguageCode,
+			LastModifiedTime: filter.modifiedAt,
+		})
+	}
+	return filters, nil
+}
+
+// DeleteVocabularyFilter 語彙フィルタを削除します
+func (e *FakeTranscribeEngine) DeleteVocabularyFilter(ctx context.Context, name string) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if _, exists := e.filters[name]; !exists {
+		return domainerr.New(domainerr.NotFound, "vocabulary_filter_not_found", "vocabulary filter %s does not exist", name)
+	}
+	delete(e.filters, name)
+	return nil
+}
+
+// advance 経過時間に応じてジョブとボキャブラリの状態を進めます。
+// 完了させるジョブの文字起こし結果の出力と、READYにするボキャブラリの語彙ファイルの読み込みはロックを持たずに行います
+func (e *FakeTranscribeEngine) advance(ctx context.Context) {
+	e.mu.Lock()
+	var completions []fakeJobCompletion
+	for _, job := range e.jobs {
+		if e.advanceJob(job) {
+			job.completing = true
+			completions = append(completions, fakeJobCompletion{
+				job:         job,
+				input:       job.job,
+				phrases:     e.vocabularyPhrases(job.job.CustomVocabularyName),
+				filterWords: e.filterWords(job.job.VocabularyFilterName),
+			})
+		}
+	}
+	var loads []fakeVocabularyLoad
+	for _, vocabulary := range e.vocabularies {
+		if vocabulary.state == types.VocabularyStatePending && !vocabulary.loading && time.Since(vocabulary.modifiedAt) >= e.vocabularyDelay {
+			vocabulary.loading = true
+			loads = append(loads, fakeVocabularyLoad{vocabulary: vocabulary, input: vocabulary.vocabulary})
+		}
+	}
+	e.mu.Unlock()
+
+	for _, completion := range completions {
+		outputLocation, err := e.writeTranscript(ctx, completion)
+		e.mu.Lock()
+		completion.job.complete(outputLocation, err)
+		e.mu.Unlock()
+	}
+	for _, load := range loads {
+		entries, err := e.loadVocabularyEntries(ctx, load.input)
+		e.mu.Lock()
+		load.vocabulary.ready(entries, err)
+		e.mu.Unlock()
+	}
+}
+
+// advanceJob 経過時間に応じてジョブの状態を進め、文字起こし結果を出力して完了させる時点かどうかを返します
+func (e *FakeTranscribeEngine) advanceJob(job *fakeJob) bool {
+	if job.completing || job.status == types.TranscriptionJobStatusCompleted || job.status == types.TranscriptionJobStatusFailed {
+		return false
+	}
+	elapsed := time.Since(job.createdAt)
+	if elapsed < e.jobDuration {
+		if elapsed >= e.jobDuration/2 {
+			job.status = types.TranscriptionJobStatusInProgress
+		}
+		return false
+	}
+	return true
+}
+
+// complete 文字起こし結果の出力結果からジョブを COMPLETED または FAILED にします
+func (j *fakeJob) complete(outputLocation string, err error) {
+	completedAt := time.Now()
+	j.completing = false
+	j.completedAt = &completedAt
+	if err != nil {
+		j.status = types.TranscriptionJobStatusFailed
+		j.failureReason = err.Error()
+		return
+	}
+	j.status = types.TranscriptionJobStatusCompleted
+	j.outputLocation = outputLocation
+}
+
+// ready 語彙ファイルの読み込み結果からボキャブラリを READY または FAILED にします
+func (v *fakeVocabulary) ready(entries []model.VocabularyEntry, err error) {
+	v.loading = false
+	v.modifiedAt = time.Now()
+	if err != nil {
+		v.state = types.VocabularyStateFailed
+		v.failureReason = err.Error()
+		return
+	}
+	v.state = types.VocabularyStateReady
+	v.entries = entries
+}
+
+// loadVocabularyEntries ボキャブラリの語彙をフレーズリストまたは語彙ファイルから読み込みます
+func (e *FakeTranscribeEngine) loadVocabularyEntries(ctx context.Context, vocabulary model.CustomVocabulary) ([]model.VocabularyEntry, error) {
+	if vocabulary.IsPhraseList() {
+		entries := make([]model.VocabularyEntry, 0, len(vocabulary.Phrases))
+		for _, phrase := range vocabulary.Phrases {
+			entries = append(entries, model.VocabularyEntry{Phrase: phrase})
+		}
+		return entries, nil
+	}
+
+	bucketName, key, ok := model.ParseS3URI(vocabulary.FileUri)
+	if !ok {
+		return nil, fmt.Errorf("the vocabulary file URI %s isn't valid", vocabulary.FileUri)
+	}
+	content, err := e.readObject(ctx, bucketName, key)
+	if err != nil {
+		return nil, fmt.Errorf("the vocabulary file couldn't be read: %w", err)
+	}
+	entries, err := model.ParseVocabularyEntries(bytes.NewReader(content))
+	if err != nil {
+		return nil, fmt.Errorf("the vocabulary file isn't valid: %w", err)
+	}
+	if len(entries) == 0 {
+		return nil, fmt.Errorf("the vocabulary file doesn't contain any phrases")
+	}
+	return entries, nil
+}
+
+// loadFilterWords 語彙フィルタの単語リスト (1行1単語) を読み込みます。
+// Amazon Transcribe と同じく、読み込めない単語リストは BadRequestException と同じエラーにします
+func (e *FakeTranscribeEngine) loadFilterWords(ctx context.Context, filter model.VocabularyFilter) (map[string]bool, error) {
+	reject := func(format string, args ...interface{}) error {
+		return domainerr.New(domainerr.Validation, "vocabulary_filter_rejected", "failed to save vocabulary filter: "+format, args...)
+	}
+	bucketName, key, ok := model.ParseS3URI(filter.FileUri)
+	if !ok {
+		return nil, reject("the vocabulary filter file URI %s isn't valid", filter.FileUri)
+	}
+	content, err := e.readObject(ctx, bucketName, key)
+	if err != nil {
+		return nil, reject("the vocabulary filter file couldn't be read: %v", err)
+	}
+	words := make(map[string]bool)
+	for _, line := range strings.Split(string(content), "\n") {
+		if word := strings.TrimSpace(line); word != "" {
+			words[strings.ToLower(word)] = true
+		}
+	}
+	if len(words) == 0 {
+		return nil, reject("the vocabulary filter file doesn't contain any words")
+	}
+	return words, nil
+}
+
+// writeTranscript 文字起こし結果の JSON を出力バケットに書き込み、出力先を返します
+func (e *FakeTranscribeEngine) writeTranscript(ctx context.Context, completion fakeJobCompletion) (string, error) {
+	job := completion.input
+	bucketName, key, ok := model.ParseS3URI(job.MediaFileURI)
+	if !ok {
+		return "", fmt.Errorf("the URI that you provided isn't valid: %s", job.MediaFileURI)
+	}
+	if _, err := e.storage.HeadObject(ctx, bucketName, key); err != nil {
+		return "", fmt.Errorf("the media file couldn't be found: %s", job.MediaFileURI)
+	}
+
+	content, err := e.buildTranscript(ctx, completion, bucketName, key)
+	if err != nil {
+		return "", err
+	}
+	outputKey := job.OutputKey
+	if outputKey == "" {
+		outputKey = model.TranscriptKey("", job.JobName)
+	}
+	output := model.NewS3Object(e.outputBucket, outputKey, bytes.NewReader(content), "application/json")
+	uri, err := e.storage.PutObject(ctx, *output)
+	if err != nil {
+		return "", fmt.Errorf("failed to write transcript: %w", err)
+	}
+	return uri, nil
+}
+
+// buildTranscript メディアの横のテキストファイル、fixture、固定の文の順に文字起こし結果の内容を決めて JSON を生成します
+func (e *FakeTranscribeEngine) buildTranscript(ctx context.Context, completion fakeJobCompletion, bucketName, mediaKey string) ([]byte, error) {
+	job := completion.input
+	sidecarKey := strings.TrimSuffix(mediaKey, path.Ext(mediaKey)) + ".txt"
+	if text, err := e.readObject(ctx, bucketName, sidecarKey); err == nil {
+		return transcriptFromText(completion, string(text))
+	}
+
+	if e.fixture != nil {
+		var output map[string]interface{}
+		if err := json.Unmarshal(e.fixture, &output); err != nil {
+			return nil, fmt.Errorf("transcript fixture is invalid: %w", err)
+		}
+		output["jobName"] = job.JobName
+		return json.Marshal(output)
+	}
+
+	text := "This is a transcript generated by the fake transcription engine."
+	if model.IsUnspacedLanguage(job.LanguageCode) {
+		text = "これはテスト用の文字起こしエンジンが生成した文字起こし結果です。"
+	}
+	return transcriptFromText(completion, text)
+}
+
+// transcriptFromText テキストを単語と句読点に分け、Amazon Transcribe と同じ形式の JSON を生成します。
+// 信頼度は単語から決まる 0.55〜0.99 の値で、ジョブのボキャブラリの語彙は 0.99 になり DisplayAs で出力されます。
+// 語彙フィルタの単語は、ジョブの適用方法に従って *** に置き換えるか、取り除くか、vocabulary_filter_match を付けます
+func transcriptFromText(completion fakeJobCompletion, text string) ([]byte, error) {
+	type alternative struct {
+		Confidence string `json:"confidence"`
+		Content    string `json:"content"`
+	}
+	type item struct {
+		ID                    int           `json:"id"`
+		StartTime             string        `json:"start_time,omitempty"`
+		EndTime               string        `json:"end_time,omitempty"`
+		Alternatives          []alternative `json:"alternatives"`
+		Type                  string        `json:"type"`
+		VocabularyFilterMatch bool          `json:"vocabulary_filter_match,omitempty"`
+	}
+
+	job := completion.input
+	unspaced := model.IsUnspacedLanguage(job.LanguageCode)
+
+	var items []item
+	var transcript strings.Builder
+	position := 0.0
+	for _, token := range tokenizeFakeTranscript(text, completion.phrases) {
+		filtered := completion.filterWords[strings.ToLower(token.content)] && !token.punctuation
+		if filtered {
+			switch job.VocabularyFilterMethod {
+			case model.VocabularyFilterMethodRemove:
+				continue
+			case model.VocabularyFilterMethodMask:
+				token.content = "***"
+			}
+		}
+
+		if token.punctuation {
+			items = append(items, item{
+				ID:           len(items),
+				Alternatives: []alternative{{Confidence: "0.0", Content: token.content}},
+				Type:         "punctuation",
+			})
+			transcript.WriteString(token.content)
+			continue
+		}
+
+		confidence := fakeConfidence(token.content)
+		if token.vocabulary {
+			confidence = 0.99
+		}
+		// 1文字あたり約0.08秒として発話の時間を割り当てる
+		duration := 0.15 + 0.08*float64(utf8.RuneCountInString(token.content))
+		items = append(items, item{
+			ID:                    len(items),
+			StartTime:             strconv.FormatFloat(position, 'f', 3, 64),
+			EndTime:               strconv.FormatFloat(position+duration, 'f', 3, 64),
+			Alternatives:          []alternative{{Confidence: strconv.FormatFloat(confidence, 'f', 3, 64), Content: token.content}},
+			Type:                  "pronunciation",
+			VocabularyFilterMatch: filtered && job.VocabularyFilterMethod == model.VocabularyFilterMethodTag,
+		})
+		position += duration + 0.05
+
+		// 日本語などでも英単語どうしの間には空白を入れる
+		if previous, _ := utf8.DecodeLastRuneInString(transcript.String()); transcript.Len() > 0 &&
+			(!unspaced || (fakeScript(previous) == "other" && fakeScript([]rune(token.content)[0]) == "other" && !strings.ContainsRune(fakeTranscriptPunctuation, previous))) {
+			transcript.WriteString(" ")
+		}
+		transcript.WriteString(token.content)
+	}
+	if items == nil {
+		items = []item{}
+	}
+
+	output := map[string]interface{}{
+		"jobName":   job.JobName,
+		"accountId": "000000000000",
+		"status":    "COMPLETED",
+		"results": map[string]interface{}{
+			"transcripts": []map[string]string{{"transcript": transcript.String()}},
+			"items":       items,
+		},
+	}
+	return json.Marshal(output)
+}
+
+// vocabularyPhrases ジョブで使うボキャブラリの語彙を、長いものから一致させるよう並べて返します。ロックを持って呼び出します
+func (e *FakeTranscribeEngine) vocabularyPhrases(name string) []fakeTranscriptPhrase {
+	vocabulary, exists := e.vocabularies[name]
+	if name == "" || !exists {
+		return nil
+	}
+	var phrases []fakeTranscriptPhrase
+	for _, entry := range vocabulary.entries {
+		// 語彙ファイルのフレーズは単語をハイフンでつなぐ
+		text := strings.ReplaceAll(entry.Phrase, "-", " ")
+		if text == "" {
+			continue
+		}
+		displayAs := entry.DisplayAs
+		if displayAs == "" {
+			displayAs = text
+		}
+		phrases = append(phrases, fakeTranscriptPhrase{text: text, displayAs: displayAs})
+	}
+	sort.SliceStable(phrases, func(i, j int) bool {
+		return len(phrases[i].text) > len(phrases[j].text)
+	})
+	return phrases
+}
+
+// filterWords ジョブで使う語彙フィルタの単語を返します。ロックを持って呼び出します
+func (e *FakeTranscribeEngine) filterWords(name string) map[string]bool {
+	filter, exists := e.filters[name]
+	if name == "" || !exists {
+		return nil
+	}
+	return filter.words
+}
+
+// readObject オブジェクトの内容を読み込みます
+func (e *FakeTranscribeEngine) readObject(ctx context.Context, bucketName, key string) ([]byte, error) {
+	reader, size, err := e.storage.OpenObjectReader(ctx, bucketName, key)
+	if err != nil {
+		return nil, err
+	}
+	return io.ReadAll(io.NewSectionReader(reader, 0, size))
+}
+
+// toTranscriptionJob ジョブを Amazon Transcribe のジョブ詳細と同じ形式に変換します
+func (j *fakeJob) toTranscriptionJob() *types.TranscriptionJob {
+	job := &types.TranscriptionJob{
+		TranscriptionJobName:   aws.String(j.job.JobName),
+		CreationTime:           aws.Time(j.createdAt),
+		CompletionTime:         j.completedAt,
+		LanguageCode:           types.LanguageCode(j.job.LanguageCode),
+		TranscriptionJobStatus: j.status,
+		FailureReason:          optionalString(j.failureReason),
+		Media:                  &types.Media{MediaFileUri: aws.String(j.job.MediaFileURI)},
+		Transcript:             &types.Transcript{},
+	}
+	if j.outputLocation != "" {
+		job.Transcript.TranscriptFileUri = aws.String(j.outputLocation)
+	}
+	if j.job.CustomVocabularyName != "" || j.job.VocabularyFilterName != "" {
+		job.Settings = &types.Settings{
+			VocabularyName:       optionalString(j.job.CustomVocabularyName),
+			VocabularyFilterName: optionalString(j.job.VocabularyFilterName),
+		}
+	}
+	return job
+}
+
+// fakeTranscriptToken 文字起こし結果の単語または句読点1件
+type fakeTranscriptToken struct {
+	content     string
+	punctuation bool
+	vocabulary  bool // ボキャブラリの語彙と一致した単語
+}
+
+// tokenizeFakeTranscript テキストを単語と句読点に分けます。
+// 空白と句読点に加えて文字の種類 (漢字、ひらがな、カタカナ、英数字) が変わる位置で区切り、ボキャブラリの語彙は1語として扱います
+func tokenizeFakeTranscript(text string, phrases []fakeTranscriptPhrase) []fakeTranscriptToken {
+	var tokens []fakeTranscriptToken
+	var word []rune
+	flush := func() {
+		if len(word) > 0 {
+			tokens = append(tokens, fakeTranscriptToken{content: string(word)})
+			word = word[:0]
+		}
+	}
+
+	runes := []rune(text)
+	for i := 0; i < len(runes); {
+		if phrase, ok := matchFakeTranscriptPhrase(runes[i:], phrases); ok {
+			flush()
+			tokens = append(tokens, fakeTranscriptToken{content: phrase.displayAs, vocabulary: true})
+			i += utf8.RuneCountInString(phrase.text)
+			continue
+		}
+
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			flush()
+		case strings.ContainsRune(fakeTranscriptPunctuation, r):
+			flush()
+			tokens = append(tokens, fakeTranscriptToken{content: string(r), punctuation: true})
+		default:
+			if len(word) > 0 && fakeScript(word[len(word)-1]) != fakeScript(r) {
+				flush()
+			}
+			word = append(word, r)
+		}
+		i++
+	}
+	flush()
+	return tokens
+}
+
+// matchFakeTranscriptPhrase テキストの先頭がボキャブラリの語彙と一致するかどうかを返します
+func matchFakeTranscriptPhrase(runes []rune, phrases []fakeTranscriptPhrase) (fakeTranscriptPhrase, bool) {
+	for _, phrase := range phrases {
+		phraseRunes := []rune(phrase.text)
+		if len(phraseRunes) <= len(runes) && strings.EqualFold(string(runes[:len(phraseRunes)]), phrase.text) {
+			return phrase, true
+		}
+	}
+	return fakeTranscriptPhrase{}, false
+}
+
+// fakeScript 単語の区切りを決めるための文字の種類を返します
+func fakeScript(r rune) string {
+	switch {
+	case unicode.In(r, unicode.Han):
+		return "han"
+	case unicode.In(r, unicode.Hiragana):
+		return "hiragana"
+	case unicode.In(r, unicode.Katakana) || r == 'ー':
+		return "katakana"
+	default:
+		return "other"
+	}
+}
+
+// fakeConfidence 単語から 0.55〜0.99 の信頼度を決めます。同じ単語には常に同じ値を返します
+func fakeConfidence(word string) float64 {
+	hash := fnv.New32a()
+	_, _ = hash.Write([]byte(word))
+	return 0.55 + float64(hash.Sum32()%45)/100
+}
+
+// optionalString 空文字の場合はnilを返します
+func optionalString(value string) *string {
+	if value == "" {
+		return nil
+	}
+	return aws.String(value)
+}
@@ -3,62 +3,52 @@ package service
 import (
 	"cmTranscribe/internal/domain/model"
 	"cmTranscribe/internal/domain/service"
+	"crypto/sha256"
 	"encoding/csv"
+	"encoding/hex"
 	"fmt"
-	"os"
+	"io"
 )
 
-// FileService ファイル操作に関連するサービスの実装
-type FileService struct {
-	fileCreator FileCreator
-}
-
-// FileCreator インターフェースでファイル操作を抽象化
-type FileCreator interface {
-	Create(name string) (*os.File, error)
-	Remove(name string) error
-}
+// FileService ファイルのエンコードに関連するサービスの実装。ローカルディスクは使用しません
+type FileService struct{}
 
-// NewFileService デフォルトのOSFileCreatorを使用してFileServiceを初期化
+// NewFileService FileServiceを初期化
 func NewFileService() service.FileService {
-	return &FileService{
-		fileCreator: &OSFileCreator{},
-	}
-}
-
-// OSFileCreator 実際のOSファイル操作の実装
-type OSFileCreator struct{}
-
-func (c *OSFileCreator) Create(name string) (*os.File, error) {
-	return os.Create(name)
+	return &FileService{}
 }
 
-func (c *OSFileCreator) Remove(name string) error {
-	return os.Remove(name)
+// EncodeTSV TSVとしてエンコードした内容をパイプ経由で読み出せるようにします
+func (f *FileService) EncodeTSV(tsvFile model.TSVFile) io.ReadCloser {
+	reader, writer := io.Pipe()
+	go func() {
+		// 読み出し側が閉じられた場合、書き込みはエラーになって終了する
+		writer.CloseWithError(writeTSV(writer, tsvFile))
+	}()
+	return reader
 }
 
-// CreateCSV CSVファイルを作成
-func (f *FileService) CreateCSV(csvFile model.CSVFile) (string, *os.File, error) {
-	file, err := f.fileCreator.Create(csvFile.Path)
-	if err != nil {
-		return "", nil, fmt.Errorf("failed to create CSV file: %v", err)
+// DigestTSV TSVとしてエンコードした内容のSHA-256を計算します
+func (f *FileService) DigestTSV(tsvFile model.TSVFile) (string, error) {
+	hash := sha256.New()
+	if err := writeTSV(hash, tsvFile); err != nil {
+		return "", err
 	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
 
-	writer := csv.NewWriter(file)
+// writeTSV TSVの内容を書き込みます
+func writeTSV(w io.Writer, tsvFile model.TSVFile) error {
+	writer := csv.NewWriter(w)
 	writer.Comma = '\t'
-	defer writer.Flush()
-
-	// CSVの内容を書き込み
-	for _, record := range csvFile.Content {
+	for _, record := range tsvFile.Content {
 		if err := writer.Write(record); err != nil {
-			return "", nil, fmt.Errorf("failed to write CSV record: %v", err)
+			return fmt.Errorf("failed to write TSV record: %w", err)
 		}
 	}
-
-	return csvFile.Path, file, nil
-}
-
-// Cleanup ファイルを削除
-func (f *FileService) Cleanup(filePath string) error {
-	return f.fileCreator.Remove(filePath)
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return fmt.Errorf("failed to write TSV record: %w", err)
+	}
+	return nil
 }
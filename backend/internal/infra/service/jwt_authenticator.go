@@ -0,0 +1,362 @@
+package service
+
+import (
+	"cmTranscribe/internal/domain/domainerr"
+	"cmTranscribe/internal/domain/model"
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// jwtClockSkew トークンの有効期限を検証する際に許容する時計のずれ
+const jwtClockSkew = time.Minute
+
+// JWTSettings JWTの検証に使う設定
+type JWTSettings struct {
+	JWKSFile    string // 検証に使う公開鍵のJWKSファイル
+	Issuer      string // iss と一致する必要がある値 (空の場合は検証しない)
+	Audience    string // aud に含まれる必要がある値 (空の場合は検証しない)
+	RolesClaim  string // ロールを読み取るクレーム。"realm_access.roles" のようにドットでネストしたクレームを指定できます
+	TenantClaim string // テナントのIDを読み取るクレーム。ロールと同じくネストしたクレームを指定できます
+	AdminRole   string // 管理者として扱うロール
+}
+
+// JWTAuthenticator は、OIDCのプロバイダーなどが発行したJWT (RS256, ES256) をJWKSファイルの公開鍵で検証します
+type JWTAuthenticator struct {
+	settings JWTSettings
+
+	mu       sync.Mutex
+	keys     map[string]crypto.PublicKey // kid と公開鍵の対応
+	modTime  time.Time                   // 読み込んだJWKSファイルの更新日時
+	loadedAt time.Time
+}
+
+// NewJWTAuthenticator は、JWKSファイルを読み込んでJWTAuthenticatorを作成します
+func NewJWTAuthenticator(settings JWTSettings) (*JWTAuthenticator, error) {
+	if settings.RolesClaim == "" {
+		settings.RolesClaim = "roles"
+	}
+	if settings.TenantClaim == "" {
+		settings.TenantClaim = "tenant"
+	}
+	authenticator := &JWTAuthenticator{settings: settings}
+	if err := authenticator.loadKeys(); err != nil {
+		return nil, err
+	}
+	return authenticator, nil
+}
+
+// jwtHeader JWTのヘッダー
+type jwtHeader struct {
+	Alg string `json:"alg"`
+	Kid string `json:"kid"`
+}
+
+// Authenticate は、トークンの署名、有効期限、iss、aud を検証し、sub と設定したクレームのロール、テナントから呼び出し元を返します
+func (a *JWTAuthenticator) Authenticate(ctx context.Context, credential string) (*model.Principal, error) {
+	parts := strings.Split(credential, ".")
+	if len(parts) != 3 {
+		return nil, invalidToken("token is not a JWT")
+	}
+
+	var header jwtHeader
+	if err := decodeJWTSegment(parts[0], &header); err != nil {
+		return nil, invalidToken("token header is malformed")
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, invalidToken("token signature is malformed")
+	}
+	key, err := a.key(header.Kid)
+	if err != nil {
+		return nil, err
+	}
+	if err := verifyJWTSignature(header.Alg, key, parts[0]+"."+parts[1], signature); err != nil {
+		return nil, err
+	}
+
+	claims := map[string]interface{}{}
+	decoder := json.NewDecoder(base64.NewDecoder(base64.RawURLEncoding, strings.NewReader(parts[1])))
+	decoder.UseNumber()
+	if err := decoder.Decode(&claims); err != nil {
+		return nil, invalidToken("token claims are malformed")
+	}
+	if err := a.validateClaims(claims, time.Now()); err != nil {
+		return nil, err
+	}
+
+	subject, _ := claims["sub"].(string)
+	if subject == "" {
+		return nil, invalidToken("token has no sub claim")
+	}
+	tenant, _ := claimValue(claims, a.settings.TenantClaim).(string)
+	return model.NewPrincipal(subject, rolesClaim(claims, a.settings.RolesClaim), a.settings.AdminRole).WithTenant(tenant), nil
+}
+
+// validateClaims は、exp、nbf、iss、aud を検証します
+func (a *JWTAuthenticator) validateClaims(claims map[string]interface{}, now time.Time) error {
+	expiresAt, ok := numericDate(claims["exp"])
+	if !ok {
+		return invalidToken("token has no exp claim")
+	}
+	if !now.Before(expiresAt.Add(jwtClockSkew)) {
+		return domainerr.New(domainerr.Unauthorized, "token_expired", "token expired at %s", expiresAt.UTC().Format(time.RFC3339))
+	}
+	if notBefore, ok := numericDate(claims["nbf"]); ok && now.Add(jwtClockSkew).Before(notBefore) {
+		return invalidToken("token is not valid yet")
+	}
+	if a.settings.Issuer != "" {
+		if issuer, _ := claims["iss"].(string); issuer != a.settings.Issuer {
+			return invalidToken("token issuer is not trusted")
+		}
+	}
+	if a.settings.Audience != "" && !containsString(stringsClaim(claims["aud"]), a.settings.Audience) {
+		return invalidToken("token audience does not include " + a.settings.Audience)
+	}
+	return nil
+}
+
+// key は、kid に対応する公開鍵を返します。見つからない場合は、鍵の更新に備えてJWKSファイルを読み込み直します
+func (a *JWTAuthenticator) key(kid string) (crypto.PublicKey, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if key, ok := a.lookupKey(kid); ok {
+		return key, nil
+	}
+	// 存在しない kid のトークンでファイルを読み続けないよう、確認の間隔を空ける
+	if time.Since(a.loadedAt) >= 10*time.Second {
+		a.loadedAt = time.Now()
+		if stat, err := os.Stat(a.settings.JWKSFile); err == nil && !stat.ModTime().Equal(a.modTime) {
+			if err := a.readKeys(); err != nil {
+				return nil, fmt.Errorf("failed to reload JWKS: %w", err)
+			}
+			if key, ok := a.lookupKey(kid); ok {
+				return key, nil
+			}
+		}
+	}
+	return nil, invalidToken("token is signed by an unknown key")
+}
+
+// lookupKey は、kid に対応する公開鍵を返します。kid が無いトークンは、鍵が1つだけの場合にその鍵で検証します
+func (a *JWTAuthenticator) lookupKey(kid string) (crypto.PublicKey, bool) {
+	if kid == "" && len(a.keys) == 1 {
+		for _, key := range a.keys {
+			return key, true
+		}
+	}
+	key, ok := a.keys[kid]
+	return key, ok
+}
+
+func (a *JWTAuthenticator) loadKeys() error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.loadedAt = time.Now()
+	return a.readKeys()
+}
+
+// jwk JWKSに含まれる公開鍵1件分
+type jwk struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+// readKeys は、JWKSファイルから署名の検証に使う RSA と P-256 の公開鍵を読み込みます
+func (a *JWTAuthenticator) readKeys() error {
+	stat, err := os.Stat(a.settings.JWKSFile)
+	if err != nil {
+		return fmt.Errorf("failed to read JWKS file: %w", err)
+	}
+	data, err := os.ReadFile(a.settings.JWKSFile)
+	if err != nil {
+		return fmt.Errorf("failed to read JWKS file: %w", err)
+	}
+	var jwks struct {
+		Keys []jwk `json:"keys"`
+	}
+	if err := json.Unmarshal(data, &jwks); err != nil {
+		return fmt.Errorf("failed to parse JWKS file: %w", err)
+	}
+
+	keys := map[string]crypto.PublicKey{}
+	for _, key := range jwks.Keys {
+		if key.Use != "" && key.Use != "sig" {
+			continue
+		}
+		publicKey, err := key.publicKey()
+		if err != nil {
+			return fmt.Errorf("failed to parse JWKS key %q: %w", key.Kid, err)
+		}
+		if publicKey != nil {
+			keys[key.Kid] = publicKey
+		}
+	}
+	if len(keys) == 0 {
+		return fmt.Errorf("JWKS file %s has no RSA or P-256 signing keys", a.settings.JWKSFile)
+	}
+	a.keys = keys
+	a.modTime = stat.ModTime()
+	return nil
+}
+
+// publicKey は、JWKを公開鍵に変換します。対応していない種類の鍵は nil を返します
+func (k jwk) publicKey() (crypto.PublicKey, error) {
+	switch {
+	case k.Kty == "RSA" && (k.Alg == "" || k.Alg == "RS256"):
+		n, err := decodeBigInt(k.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := decodeBigInt(k.E)
+		if err != nil {
+			return nil, err
+		}
+		if !e.IsInt64() || e.Int64() > 1<<31-1 {
+			return nil, fmt.Errorf("RSA exponent is too large")
+		}
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+	case k.Kty == "EC" && k.Crv == "P-256" && (k.Alg == "" || k.Alg == "ES256"):
+		x, err := decodeBigInt(k.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := decodeBigInt(k.Y)
+		if err != nil {
+			return nil, err
+		}
+		if !elliptic.P256().IsOnCurve(x, y) {
+			return nil, fmt.Errorf("EC point is not on P-256")
+		}
+		return &ecdsa.PublicKey{Curve: elliptic.P256(), X: x, Y: y}, nil
+	default:
+		return nil, nil
+	}
+}
+
+// verifyJWTSignature は、alg と鍵の種類が一致することを確認してから署名を検証します
+func verifyJWTSignature(alg string, key crypto.PublicKey, signingInput string, signature []byte) error {
+	digest := sha256.Sum256([]byte(signingInput))
+	switch alg {
+	case "RS256":
+		publicKey, ok := key.(*rsa.PublicKey)
+		if !ok || rsa.VerifyPKCS1v15(publicKey, crypto.SHA256, digest[:], signature) != nil {
+			return invalidToken("token signature is invalid")
+		}
+	case "ES256":
+		publicKey, ok := key.(*ecdsa.PublicKey)
+		// ES256 の署名は32バイトずつの r と s を連結したもの
+		if !ok || len(signature) != 64 {
+			return invalidToken("token signature is invalid")
+		}
+		r := new(big.Int).SetBytes(signature[:32])
+		s := new(big.Int).SetBytes(signature[32:])
+		if !ecdsa.Verify(publicKey, digest[:], r, s) {
+			return invalidToken("token signature is invalid")
+		}
+	default:
+		return invalidToken("token algorithm " + alg + " is not allowed (RS256 or ES256)")
+	}
+	return nil
+}
+
+// rolesClaim は、ドットで区切ったパスのクレームからロールを読み取ります。配列とスペース区切りの文字列に対応します
+func rolesClaim(claims map[string]interface{}, path string) []string {
+	value := claimValue(claims, path)
+	if roles, ok := value.(string); ok {
+		return strings.Fields(roles)
+	}
+	return stringsClaim(value)
+}
+
+// claimValue は、ドットで区切ったパスのクレームの値を返します。存在しない場合は nil を返します
+func claimValue(claims map[string]interface{}, path string) interface{} {
+	var value interface{} = claims
+	for _, name := range strings.Split(path, ".") {
+		object, ok := value.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		value = object[name]
+	}
+	return value
+}
+
+// stringsClaim は、文字列または文字列の配列のクレームを返します
+func stringsClaim(value interface{}) []string {
+	switch v := value.(type) {
+	case string:
+		return []string{v}
+	case []interface{}:
+		var values []string
+		for _, item := range v {
+			if s, ok := item.(string); ok {
+				values = append(values, s)
+			}
+		}
+		return values
+	default:
+		return nil
+	}
+}
+
+// numericDate は、UNIX時間 (秒) のクレームを時刻に変換します
+func numericDate(value interface{}) (time.Time, bool) {
+	number, ok := value.(json.Number)
+	if !ok {
+		return time.Time{}, false
+	}
+	seconds, err := number.Float64()
+	if err != nil {
+		return time.Time{}, false
+	}
+	return time.Unix(int64(seconds), 0), true
+}
+
+func containsString(values []string, target string) bool {
+	for _, value := range values {
+		if value == target {
+			return true
+		}
+	}
+	return false
+}
+
+func decodeJWTSegment(segment string, v interface{}) error {
+	data, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
+
+func decodeBigInt(value string) (*big.Int, error) {
+	data, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil || len(data) == 0 {
+		return nil, fmt.Errorf("invalid base64url integer")
+	}
+	return new(big.Int).SetBytes(data), nil
+}
+
+func invalidToken(message string) error {
+	return domainerr.New(domainerr.Unauthorized, "invalid_token", "%s", message)
+}
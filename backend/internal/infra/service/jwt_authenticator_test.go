@@ -0,0 +1,182 @@
+package service
+
+import (
+	"cmTranscribe/internal/domain/domainerr"
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// testJWTKeys テスト用のJWKSファイルに書き出す RSA と P-256 の鍵
+type testJWTKeys struct {
+	rsaKey *rsa.PrivateKey
+	ecKey  *ecdsa.PrivateKey
+}
+
+// newTestJWTAuthenticator kid "rsa" と "ec" の公開鍵を持つJWKSファイルを作成してJWTAuthenticatorを作成します
+func newTestJWTAuthenticator(t *testing.T, settings JWTSettings) (*JWTAuthenticator, testJWTKeys) {
+	t.Helper()
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	encode := func(b []byte) string { return base64.RawURLEncoding.EncodeToString(b) }
+	jwks := map[string][]jwk{"keys": {
+		{Kty: "RSA", Kid: "rsa", Use: "sig", N: encode(rsaKey.N.Bytes()), E: encode(big.NewInt(int64(rsaKey.E)).Bytes())},
+		{Kty: "EC", Kid: "ec", Crv: "P-256", X: encode(ecKey.X.FillBytes(make([]byte, 32))), Y: encode(ecKey.Y.FillBytes(make([]byte, 32)))},
+	}}
+	data, err := json.Marshal(jwks)
+	if err != nil {
+		t.Fatal(err)
+	}
+	settings.JWKSFile = filepath.Join(t.TempDir(), "jwks.json")
+	if err := os.WriteFile(settings.JWKSFile, data, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	authenticator, err := NewJWTAuthenticator(settings)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return authenticator, testJWTKeys{rsaKey: rsaKey, ecKey: ecKey}
+}
+
+// signTestJWT header と claims を signer で署名したJWTを返します。signer が nil の場合は空の署名を付けます
+func signTestJWT(t *testing.T, header, claims map[string]interface{}, signer crypto.Signer) string {
+	t.Helper()
+	encodeJSON := func(v interface{}) string {
+		data, err := json.Marshal(v)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return base64.RawURLEncoding.EncodeToString(data)
+	}
+	signingInput := encodeJSON(header) + "." + encodeJSON(claims)
+	digest := sha256.Sum256([]byte(signingInput))
+	var signature []byte
+	switch key := signer.(type) {
+	case *rsa.PrivateKey:
+		s, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, digest[:])
+		if err != nil {
+			t.Fatal(err)
+		}
+		signature = s
+	case *ecdsa.PrivateKey:
+		r, s, err := ecdsa.Sign(rand.Reader, key, digest[:])
+		if err != nil {
+			t.Fatal(err)
+		}
+		signature = append(r.FillBytes(make([]byte, 32)), s.FillBytes(make([]byte, 32))...)
+	}
+	return signingInput + "." + base64.RawURLEncoding.EncodeToString(signature)
+}
+
+func TestJWTAuthenticatorAuthenticate(t *testing.T) {
+	authenticator, keys := newTestJWTAuthenticator(t, JWTSettings{Issuer: "https://issuer.example", Audience: "cm-transcribe", AdminRole: "admin"})
+	now := time.Now()
+	validClaims := func() map[string]interface{} {
+		return map[string]interface{}{
+			"sub":    "alice",
+			"iss":    "https://issuer.example",
+			"aud":    []string{"other", "cm-transcribe"},
+			"exp":    now.Add(time.Hour).Unix(),
+			"roles":  []string{"admin"},
+			"tenant": "team-a",
+		}
+	}
+	withClaim := func(name string, value interface{}) map[string]interface{} {
+		claims := validClaims()
+		if value == nil {
+			delete(claims, name)
+		} else {
+			claims[name] = value
+		}
+		return claims
+	}
+	rs256 := map[string]interface{}{"alg": "RS256", "kid": "rsa"}
+	es256 := map[string]interface{}{"alg": "ES256", "kid": "ec"}
+
+	tests := []struct {
+		name     string
+		token    string
+		wantCode string // 空の場合は認証に成功する
+	}{
+		{name: "RS256", token: signTestJWT(t, rs256, validClaims(), keys.rsaKey)},
+		{name: "ES256", token: signTestJWT(t, es256, validClaims(), keys.ecKey)},
+		{name: "alg none", token: signTestJWT(t, map[string]interface{}{"alg": "none", "kid": "rsa"}, validClaims(), nil), wantCode: "invalid_token"},
+		{name: "alg HS256", token: signTestJWT(t, map[string]interface{}{"alg": "HS256", "kid": "rsa"}, validClaims(), nil), wantCode: "invalid_token"},
+		{name: "RS256 with an EC key", token: signTestJWT(t, map[string]interface{}{"alg": "RS256", "kid": "ec"}, validClaims(), keys.rsaKey), wantCode: "invalid_token"},
+		{name: "ES256 with an RSA key", token: signTestJWT(t, map[string]interface{}{"alg": "ES256", "kid": "rsa"}, validClaims(), keys.ecKey), wantCode: "invalid_token"},
+		{name: "signed by another key", token: signTestJWT(t, map[string]interface{}{"alg": "ES256", "kid": "ec"}, validClaims(), mustECKey(t)), wantCode: "invalid_token"},
+		{name: "unknown kid", token: signTestJWT(t, map[string]interface{}{"alg": "RS256", "kid": "unknown"}, validClaims(), keys.rsaKey), wantCode: "invalid_token"},
+		{name: "expired", token: signTestJWT(t, rs256, withClaim("exp", now.Add(-2*jwtClockSkew).Unix()), keys.rsaKey), wantCode: "token_expired"},
+		{name: "expired within the clock skew", token: signTestJWT(t, rs256, withClaim("exp", now.Add(-jwtClockSkew/2).Unix()), keys.rsaKey)},
+		{name: "no exp", token: signTestJWT(t, rs256, withClaim("exp", nil), keys.rsaKey), wantCode: "invalid_token"},
+		{name: "not valid yet", token: signTestJWT(t, rs256, withClaim("nbf", now.Add(2*jwtClockSkew).Unix()), keys.rsaKey), wantCode: "invalid_token"},
+		{name: "nbf within the clock skew", token: signTestJWT(t, rs256, withClaim("nbf", now.Add(jwtClockSkew/2).Unix()), keys.rsaKey)},
+		{name: "untrusted issuer", token: signTestJWT(t, rs256, withClaim("iss", "https://evil.example"), keys.rsaKey), wantCode: "invalid_token"},
+		{name: "no issuer", token: signTestJWT(t, rs256, withClaim("iss", nil), keys.rsaKey), wantCode: "invalid_token"},
+		{name: "audience as a string", token: signTestJWT(t, rs256, withClaim("aud", "cm-transcribe"), keys.rsaKey)},
+		{name: "other audience", token: signTestJWT(t, rs256, withClaim("aud", "other"), keys.rsaKey), wantCode: "invalid_token"},
+		{name: "no audience", token: signTestJWT(t, rs256, withClaim("aud", nil), keys.rsaKey), wantCode: "invalid_token"},
+		{name: "no sub", token: signTestJWT(t, rs256, withClaim("sub", nil), keys.rsaKey), wantCode: "invalid_token"},
+		{name: "not a JWT", token: "abc.def", wantCode: "invalid_token"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			principal, err := authenticator.Authenticate(context.Background(), tt.token)
+			if tt.wantCode == "" {
+				if err != nil {
+					t.Fatalf("Authenticate() error = %v", err)
+				}
+				if principal.Subject != "alice" || !principal.Admin || principal.Tenant != "team-a" {
+					t.Errorf("principal = %+v, want alice, admin, team-a", principal)
+				}
+				return
+			}
+			domainErr, ok := domainerr.As(err)
+			if !ok || domainErr.Kind != domainerr.Unauthorized || domainErr.Code != tt.wantCode {
+				t.Fatalf("Authenticate() error = %v, want %s", err, tt.wantCode)
+			}
+		})
+	}
+}
+
+func TestJWTAuthenticatorTamperedClaims(t *testing.T) {
+	authenticator, keys := newTestJWTAuthenticator(t, JWTSettings{AdminRole: "admin"})
+	header := map[string]interface{}{"alg": "RS256", "kid": "rsa"}
+	exp := time.Now().Add(time.Hour).Unix()
+	token := strings.Split(signTestJWT(t, header, map[string]interface{}{"sub": "alice", "exp": exp}, keys.rsaKey), ".")
+	forged := strings.Split(signTestJWT(t, header, map[string]interface{}{"sub": "root", "exp": exp, "roles": "admin"}, nil), ".")
+
+	// 署名した後にクレームを書き換えたトークンは拒否します
+	_, err := authenticator.Authenticate(context.Background(), token[0]+"."+forged[1]+"."+token[2])
+	if !errors.Is(err, domainerr.Unauthorized) {
+		t.Fatalf("Authenticate() error = %v, want Unauthorized", err)
+	}
+}
+
+func mustECKey(t *testing.T) *ecdsa.PrivateKey {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return key
+}
@@ -0,0 +1,94 @@
+package service
+
+import (
+	"cmTranscribe/internal/domain/service"
+	"encoding/csv"
+	"fmt"
+	"os"
+	"strings"
+	"unicode"
+)
+
+// KatakanaReadingGenerator 英字・ローマ字の語をカタカナの読みに変換するReadingGeneratorの実装
+type KatakanaReadingGenerator struct {
+	dictionary map[string]string // 小文字にした語 → 読み
+}
+
+// NewKatakanaReadingGenerator 読みの上書き辞書（「語<TAB>読み」のTSV）を読み込んでKatakanaReadingGeneratorを作成します。
+// dictionaryPathが空の場合は変換ルールのみを使用します
+func NewKatakanaReadingGenerator(dictionaryPath string) (service.ReadingGenerator, error) {
+	generator := &KatakanaReadingGenerator{dictionary: make(map[string]string)}
+	if dictionaryPath == "" {
+		return generator, nil
+	}
+
+	file, err := os.Open(dictionaryPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open reading dictionary: %w", err)
+	}
+	defer file.Close()
+
+	reader := csv.NewReader(file)
+	reader.Comma = '\t'
+	reader.Comment = '#'
+	reader.FieldsPerRecord = 2
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse reading dictionary: %w", err)
+	}
+	for _, record := range records {
+		generator.dictionary[strings.ToLower(record[0])] = hiraganaToKatakana(record[1])
+	}
+	return generator, nil
+}
+
+// GenerateSoundsLike Phraseの読みをハイフン区切りのカタカナで返します
+func (g *KatakanaReadingGenerator) GenerateSoundsLike(phrase string, overrides map[string]string) (string, error) {
+	// Phrase全体が辞書にある場合はそれを優先
+	if reading, ok := g.lookup(phrase, overrides); ok {
+		return reading, nil
+	}
+
+	var segments []string
+	for _, word := range strings.FieldsFunc(phrase, func(r rune) bool { return r == '-' || unicode.IsSpace(r) }) {
+		reading, ok := g.lookup(word, overrides)
+		if !ok {
+			var err error
+			if reading, err = readingOf(word); err != nil {
+				return "", err
+			}
+		}
+		if reading != "" {
+			segments = append(segments, reading)
+		}
+	}
+	if len(segments) == 0 {
+		return "", fmt.Errorf("cannot generate a reading for %q", phrase)
+	}
+	return strings.Join(segments, "-"), nil
+}
+
+// lookup リクエストで指定された上書き、辞書の順に語の読みを探します
+func (g *KatakanaReadingGenerator) lookup(word string, overrides map[string]string) (string, bool) {
+	key := strings.ToLower(word)
+	for term, reading := range overrides {
+		if strings.ToLower(term) == key {
+			return hiraganaToKatakana(reading), true
+		}
+	}
+	reading, ok := g.dictionary[key]
+	return reading, ok
+}
+
+// readingOf 辞書にない語を変換ルールでカタカナにします。かなの語はカタカナにそろえます
+func readingOf(word string) (string, error) {
+	word = strings.NewReplacer(".", "", "'", "").Replace(word)
+	if isKana(word) {
+		return hiraganaToKatakana(word), nil
+	}
+	reading, err := transliterate(word)
+	if err != nil {
+		return "", fmt.Errorf("cannot generate a reading for %q: %w", word, err)
+	}
+	return reading, nil
+}
@@ -0,0 +1,210 @@
+package service
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// romajiKatakana ローマ字の音節とカタカナの対応表
+var romajiKatakana = map[string]string{
+	"a": "ア", "i": "イ", "u": "ウ", "e": "エ", "o": "オ",
+	"ka": "カ", "ki": "キ", "ku": "ク", "ke": "ケ", "ko": "コ",
+	"sa": "サ", "si": "シ", "shi": "シ", "su": "ス", "se": "セ", "so": "ソ",
+	"ta": "タ", "ti": "ティ", "chi": "チ", "tu": "トゥ", "tsu": "ツ", "te": "テ", "to": "ト",
+	"na": "ナ", "ni": "ニ", "nu": "ヌ", "ne": "ネ", "no": "ノ",
+	"ha": "ハ", "hi": "ヒ", "hu": "フ", "fu": "フ", "he": "ヘ", "ho": "ホ",
+	"ma": "マ", "mi": "ミ", "mu": "ム", "me": "メ", "mo": "モ",
+	"ya": "ヤ", "yu": "ユ", "ye": "イェ", "yo": "ヨ",
+	"ra": "ラ", "ri": "リ", "ru": "ル", "re": "レ", "ro": "ロ",
+	"wa": "ワ", "wi": "ウィ", "we": "ウェ", "wo": "ウォ",
+	"ga": "ガ", "gi": "ギ", "gu": "グ", "ge": "ゲ", "go": "ゴ",
+	"za": "ザ", "zi": "ジ", "ji": "ジ", "zu": "ズ", "ze": "ゼ", "zo": "ゾ",
+	"da": "ダ", "di": "ディ", "du": "ドゥ", "de": "デ", "do": "ド",
+	"ba": "バ", "bi": "ビ", "bu": "ブ", "be": "ベ", "bo": "ボ",
+	"pa": "パ", "pi": "ピ", "pu": "プ", "pe": "ペ", "po": "ポ",
+	"va": "ヴァ", "vi": "ヴィ", "vu": "ヴ", "ve": "ヴェ", "vo": "ヴォ",
+	"fa": "ファ", "fi": "フィ", "fe": "フェ", "fo": "フォ",
+	"ja": "ジャ", "ju": "ジュ", "je": "ジェ", "jo": "ジョ",
+	"sha": "シャ", "shu": "シュ", "she": "シェ", "sho": "ショ",
+	"cha": "チャ", "chu": "チュ", "che": "チェ", "cho": "チョ",
+	"kya": "キャ", "kyu": "キュ", "kyo": "キョ",
+	"gya": "ギャ", "gyu": "ギュ", "gyo": "ギョ",
+	"nya": "ニャ", "nyu": "ニュ", "nyo": "ニョ",
+	"hya": "ヒャ", "hyu": "ヒュ", "hyo": "ヒョ",
+	"mya": "ミャ", "myu": "ミュ", "myo": "ミョ",
+	"rya": "リャ", "ryu": "リュ", "ryo": "リョ",
+	"bya": "ビャ", "byu": "ビュ", "byo": "ビョ",
+	"pya": "ピャ", "pyu": "ピュ", "pyo": "ピョ",
+	"tsa": "ツァ", "tyu": "テュ", "dyu": "デュ", "kwa": "クァ",
+}
+
+// consonantKatakana 母音を伴わない子音の読み (英語の綴りを変換する場合のみ使用)
+var consonantKatakana = map[string]string{
+	"sh": "シュ", "ch": "チ", "ts": "ツ",
+	"b": "ブ", "c": "ク", "d": "ド", "f": "フ", "g": "グ", "h": "", "j": "ジ", "k": "ク",
+	"l": "ル", "m": "ム", "n": "ン", "p": "プ", "q": "ク", "r": "ル", "s": "ス", "t": "ト",
+	"v": "ヴ", "w": "ウ", "x": "クス", "y": "イ", "z": "ズ",
+}
+
+// rColoredVowel 子音の前や語末の母音+r (例: server, smart, port)
+var rColoredVowel = regexp.MustCompile(`[aeiou]r($|[^aeiouy])`)
+
+// englishSpelling 英語の綴りをローマ字に近づける置き換え
+var englishSpelling = strings.NewReplacer(
+	"ph", "f", "ck", "k", "qu", "kw", "th", "s", "wh", "w", "x", "ks", "l", "r", "q", "k",
+)
+
+// transliterate 英字の語をカタカナに変換します。
+// ヘボン式ローマ字として読める場合はそのまま変換し、読めない場合は英語の綴りとみなして発音に近づけてから変換します
+func transliterate(word string) (string, error) {
+	word = strings.ToLower(word)
+	for _, r := range word {
+		if r < 'a' || r > 'z' {
+			return "", fmt.Errorf("unsupported character %q in %q", r, word)
+		}
+	}
+	if katakana, ok := romajiToKatakana(word, false); ok {
+		return katakana, nil
+	}
+	katakana, _ := romajiToKatakana(anglicize(word), true)
+	return katakana, nil
+}
+
+// romajiToKatakana ローマ字をカタカナに変換します。
+// looseがfalseの場合、ローマ字として読めない綴りがあるとfalseを返します
+func romajiToKatakana(s string, loose bool) (string, bool) {
+	var builder strings.Builder
+	var lastVowel byte
+	for i := 0; i < len(s); {
+		c := s[i]
+		// 撥音: 母音やyが続かないn
+		if c == 'n' && (i+1 == len(s) || !isRomajiVowel(s[i+1]) && s[i+1] != 'y') {
+			builder.WriteString("ン")
+			lastVowel = 0
+			i++
+			continue
+		}
+		// 促音: 同じ子音の連続、またはtch
+		if i+1 < len(s) && !isRomajiVowel(c) && (s[i+1] == c || c == 't' && s[i+1] == 'c') {
+			builder.WriteString("ッ")
+			lastVowel = 0
+			i++
+			continue
+		}
+		// 長音: 同じ母音の連続 (英語の綴りのみ。ローマ字の「おお」などはそのまま)
+		if loose && isRomajiVowel(c) && c == lastVowel {
+			builder.WriteString("ー")
+			lastVowel = 0
+			i++
+			continue
+		}
+
+		matched := false
+		for n := 3; n >= 1; n-- {
+			if i+n > len(s) {
+				continue
+			}
+			if katakana, ok := romajiKatakana[s[i:i+n]]; ok {
+				builder.WriteString(katakana)
+				lastVowel = 0
+				if isRomajiVowel(s[i+n-1]) {
+					lastVowel = s[i+n-1]
+				}
+				i += n
+				matched = true
+				break
+			}
+		}
+		if matched {
+			continue
+		}
+		if !loose {
+			return "", false
+		}
+
+		// 母音を伴わない子音
+		lastVowel = 0
+		if i+2 <= len(s) {
+			if katakana, ok := consonantKatakana[s[i:i+2]]; ok {
+				builder.WriteString(katakana)
+				i += 2
+				continue
+			}
+		}
+		builder.WriteString(consonantKatakana[s[i:i+1]])
+		i++
+	}
+	return builder.String(), true
+}
+
+// anglicize 英語の綴りを発音に近いローマ字に置き換えます (例: google → guugr, server → saavaa)
+func anglicize(word string) string {
+	// 語末の黙字のe
+	if len(word) > 3 && word[len(word)-1] == 'e' && !isRomajiVowel(word[len(word)-2]) {
+		word = word[:len(word)-1]
+	}
+	// eeとooは長音にする (free → フリー, google → グーグル)
+	word = strings.NewReplacer("ee", "ii", "oo", "uu").Replace(word)
+
+	// cとyは前後の文字によって読みが変わる
+	var builder strings.Builder
+	for i := 0; i < len(word); i++ {
+		c := word[i]
+		var next byte
+		if i+1 < len(word) {
+			next = word[i+1]
+		}
+		switch {
+		case c == 'c' && next == 'h':
+			builder.WriteByte('c')
+		case c == 'c' && (next == 'e' || next == 'i' || next == 'y'):
+			builder.WriteByte('s')
+		case c == 'c':
+			builder.WriteByte('k')
+		case c == 'y' && i > 0 && !isRomajiVowel(word[i-1]) && next == 0:
+			builder.WriteString("ii")
+		case c == 'y' && i > 0 && !isRomajiVowel(word[i-1]):
+			builder.WriteByte('i')
+		default:
+			builder.WriteByte(c)
+		}
+	}
+	word = builder.String()
+
+	// 母音+rは長音にする (er, ir, ur, ar → アー, or → オー)
+	word = rColoredVowel.ReplaceAllStringFunc(word, func(m string) string {
+		vowel := "aa"
+		if m[0] == 'o' {
+			vowel = "oo"
+		}
+		return vowel + m[2:]
+	})
+	return englishSpelling.Replace(word)
+}
+
+// isRomajiVowel ローマ字の母音かどうかを返します
+func isRomajiVowel(c byte) bool {
+	return c == 'a' || c == 'i' || c == 'u' || c == 'e' || c == 'o'
+}
+
+// isKana ひらがな・カタカナ・長音記号のみで構成されているかどうかを返します
+func isKana(word string) bool {
+	for _, r := range word {
+		if !unicode.In(r, unicode.Hiragana, unicode.Katakana) && r != 'ー' {
+			return false
+		}
+	}
+	return word != ""
+}
+
+// hiraganaToKatakana ひらがなをカタカナに変換します
+func hiraganaToKatakana(word string) string {
+	return strings.Map(func(r rune) rune {
+		if r >= 'ぁ' && r <= 'ゖ' {
+			return r + ('ァ' - 'ぁ')
+		}
+		return r
+	}, word)
+}
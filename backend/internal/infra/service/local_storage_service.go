@@ -0,0 +1,629 @@
+package service
+
+import (
+	"cmTranscribe/internal/domain/domainerr"
+	"cmTranscribe/internal/domain/model"
+	"cmTranscribe/internal/shared/logger"
+	"context"
+	"crypto/hmac"
+	"crypto/md5"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// LocalStorageObjectPath ローカルストレージの署名付きURLを受け付けるパス
+const LocalStorageObjectPath = "/api/storage/objects"
+
+// localListMaxKeys ListObjectsでmaxKeysが指定されなかった場合に返すオブジェクトの数 (S3と同じ)
+const localListMaxKeys = 1000
+
+// localObjectMetadata オブジェクトと一緒に保存するメタデータ
+type localObjectMetadata struct {
+	ContentType string            `json:"contentType,omitempty"`
+	Metadata    map[string]string `json:"metadata,omitempty"`
+}
+
+// localMultipartUpload 開始したマルチパートアップロードの情報
+type localMultipartUpload struct {
+	BucketName  string            `json:"bucketName"`
+	Key         string            `json:"key"`
+	ContentType string            `json:"contentType"`
+	Metadata    map[string]string `json:"metadata,omitempty"`
+}
+
+// LocalStorageService は S3 の代わりにローカルのファイルシステムにオブジェクトを保存する実装です。
+// 署名付きURLはサーバー自身が HMAC-SHA256 で署名し、LocalStorageObjectPath で配信・受け付けます。
+// ルートディレクトリの下は objects/<バケット>/<キー> にオブジェクト、metadata/ にメタデータ、
+// multipart/ に受け取り中のパート、tmp/ に書き込み中のファイルを置きます
+type LocalStorageService struct {
+	rootDir    string
+	publicURL  string
+	signingKey []byte
+}
+
+// NewLocalStorageService は LocalStorageService のインスタンスを生成します。
+// signingSecret が空の場合はランダムな鍵を使うため、再起動すると発行済みの署名付きURLは使えなくなります
+func NewLocalStorageService(rootDir, publicURL, signingSecret string) (*LocalStorageService, error) {
+	if rootDir == "" {
+		return nil, fmt.Errorf("root directory of local storage is required")
+	}
+	for _, dir := range []string{"objects", "metadata", "multipart", "tmp"} {
+		if err := os.MkdirAll(filepath.Join(rootDir, dir), 0o755); err != nil {
+			return nil, fmt.Errorf("failed to create local storage directory: %w", err)
+		}
+	}
+
+	signingKey := []byte(signingSecret)
+	if len(signingKey) == 0 {
+		signingKey = make([]byte, 32)
+		if _, err := rand.Read(signingKey); err != nil {
+			return nil, fmt.Errorf("failed to generate signing key: %w", err)
+		}
+		logger.Default().Warn("STORAGE_SIGNING_SECRET is not set; signed URLs will be invalid after restart")
+	}
+
+	return &LocalStorageService{
+		rootDir:    rootDir,
+		publicURL:  strings.TrimSuffix(publicURL, "/"),
+		signingKey: signingKey,
+	}, nil
+}
+
+// UploadToS3 はファイルをローカルストレージに保存します
+func (s *LocalStorageService) UploadToS3(ctx context.Context, s3File model.S3File) (string, error) {
+	file, err := os.Open(s3File.FilePath)
+	if err != nil {
+		return "", fmt.Errorf("failed to open file for upload: %w", err)
+	}
+	defer func() {
+		if err := file.Close(); err != nil {
+			logger.FromContext(ctx).Warn("Error closing file", "path", s3File.FilePath, "error", err)
+		}
+	}()
+
+	key := path.Join(s3File.KeyPrefix, filepath.Base(s3File.FilePath))
+	if err := s.putObject(s3File.BucketName, key, file, localObjectMetadata{Metadata: s3File.Metadata}); err != nil {
+		return "", fmt.Errorf("failed to upload file: %w", err)
+	}
+	return objectURI(s3File.BucketName, key), nil
+}
+
+// PutObject はストリームの内容をそのまま保存し、s3:// 形式のURIを返します
+func (s *LocalStorageService) PutObject(ctx context.Context, object model.S3Object) (string, error) {
+	if err := s.putObject(object.BucketName, object.Key, object.Body, localObjectMetadata{ContentType: object.ContentType, Metadata: object.Metadata}); err != nil {
+		return "", fmt.Errorf("failed to upload object: %w", err)
+	}
+	return objectURI(object.BucketName, object.Key), nil
+}
+
+// PresignUpload は、ファイルをサーバーに直接アップロードするための署名付きURL (PUT) またはフォームフィールド (POST) を生成します
+func (s *LocalStorageService) PresignUpload(ctx context.Context, bucketName string, upload model.UploadRequest, expires time.Duration) (*model.PresignedUpload, error) {
+	if _, err := s.objectPath(bucketName, upload.Key); err != nil {
+		return nil, err
+	}
+	request := &model.SignedObjectRequest{
+		Method:      upload.Method,
+		BucketName:  bucketName,
+		Key:         upload.Key,
+		ContentType: upload.ContentType,
+		Owner:       upload.Owner,
+		ExpiresAt:   time.Now().Add(expires),
+	}
+	presigned := &model.PresignedUpload{
+		Method:    upload.Method,
+		Key:       upload.Key,
+		ExpiresAt: request.ExpiresAt,
+	}
+
+	if upload.Method == model.UploadMethodPost {
+		// サイズの上限とContent-Typeをフォームフィールドの署名で制約する
+		request.MaxBytes = upload.MaxBytes
+		if upload.Size > 0 {
+			request.MaxBytes = upload.Size
+		}
+		presigned.URL = s.publicURL + LocalStorageObjectPath
+		presigned.Fields = make(map[string]string)
+		for name, values := range request.Params(s.sign(request)) {
+			presigned.Fields[name] = values[0]
+		}
+		return presigned, nil
+	}
+
+	// PUTの場合はContent-TypeとContent-Lengthを署名に含める
+	request.Size = upload.Size
+	presigned.URL = s.signedURL(request)
+	presigned.Headers = map[string]string{"Content-Type": upload.ContentType}
+	return presigned, nil
+}
+
+// VerifySignedRequest は、署名付きURLのクエリパラメータ (POSTの場合はフォームフィールド) の署名と有効期限を検証します
+func (s *LocalStorageService) VerifySignedRequest(method string, params url.Values) (*model.SignedObjectRequest, error) {
+	request, signature, err := model.ParseSignedObjectRequest(method, params)
+	if err != nil {
+		return nil, err
+	}
+	if !hmac.Equal([]byte(signature), []byte(s.sign(request))) {
+		return nil, domainerr.New(domainerr.Forbidden, "invalid_signed_url", "signature does not match")
+	}
+	if request.Expired(time.Now()) {
+		return nil, domainerr.New(domainerr.Forbidden, "signed_url_expired", "signed URL has expired")
+	}
+	if _, err := s.objectPath(request.BucketName, request.Key); err != nil {
+		return nil, domainerr.Wrap(domainerr.Forbidden, "invalid_signed_url", err, "signed URL does not point to a valid object")
+	}
+	return request, nil
+}
+
+// HeadObject は、オブジェクトのメタデータを取得します
+func (s *LocalStorageService) HeadObject(ctx context.Context, bucketName, key string) (*model.S3ObjectInfo, error) {
+	objectPath, err := s.objectPath(bucketName, key)
+	if err != nil {
+		return nil, err
+	}
+	stat, err := os.Stat(objectPath)
+	if err != nil || stat.IsDir() {
+		if err == nil || errors.Is(err, fs.ErrNotExist) {
+			return nil, domainerr.New(domainerr.NotFound, "object_not_found", "object %s does not exist", key)
+		}
+		return nil, fmt.Errorf("failed to get object metadata: %w", err)
+	}
+	metadata, err := s.readMetadata(bucketName, key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get object metadata: %w", err)
+	}
+
+	contentType := metadata.ContentType
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+	return &model.S3ObjectInfo{
+		BucketName:   bucketName,
+		Key:          key,
+		Size:         stat.Size(),
+		ContentType:  contentType,
+		LastModified: stat.ModTime(),
+		Metadata:     metadata.Metadata,
+	}, nil
+}
+
+// OpenObject は、オブジェクトを読み込むために開きます
+func (s *LocalStorageService) OpenObject(ctx context.Context, bucketName, key string) (io.ReadSeekCloser, *model.S3ObjectInfo, error) {
+	object, err := s.HeadObject(ctx, bucketName, key)
+	if err != nil {
+		return nil, nil, err
+	}
+	objectPath, _ := s.objectPath(bucketName, key)
+	file, err := os.Open(objectPath)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to open object: %w", err)
+	}
+	return file, object, nil
+}
+
+// OpenObjectReader は、オブジェクトの必要な範囲だけを読み込むための io.ReaderAt とサイズを返します
+func (s *LocalStorageService) OpenObjectReader(ctx context.Context, bucketName, key string) (io.ReaderAt, int64, error) {
+	object, err := s.HeadObject(ctx, bucketName, key)
+	if err != nil {
+		return nil, 0, err
+	}
+	objectPath, _ := s.objectPath(bucketName, key)
+	return &localObjectReader{path: objectPath}, object.Size, nil
+}
+
+// ListObjects は、プレフィックスの下のオブジェクトをキーの順に1ページ分取得します。
+// 継続トークンはページの最後のキーをエンコードしたものです
+func (s *LocalStorageService) ListObjects(ctx context.Context, bucketName, prefix, continuationToken string, maxKeys int) (*model.S3ObjectPage, error) {
+	startAfter := ""
+	if continuationToken != "" {
+		decoded, err := base64.RawURLEncoding.DecodeString(continuationToken)
+		if err != nil {
+			return nil, domainerr.New(domainerr.Validation, "invalid_continuation_token", "continuation token is invalid")
+		}
+		startAfter = string(decoded)
+	}
+	if maxKeys <= 0 {
+		maxKeys = localListMaxKeys
+	}
+
+	bucketDir := filepath.Join(s.rootDir, "objects", bucketName)
+	var keys []string
+	err := filepath.WalkDir(bucketDir, func(filePath string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				return nil
+			}
+			return err
+		}
+		if entry.IsDir() {
+			return nil
+		}
+		relative, err := filepath.Rel(bucketDir, filePath)
+		if err != nil {
+			return err
+		}
+		if key := filepath.ToSlash(relative); strings.HasPrefix(key, prefix) && key > startAfter {
+			keys = append(keys, key)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list objects: %w", err)
+	}
+	sort.Strings(keys)
+
+	page := &model.S3ObjectPage{}
+	if len(keys) > maxKeys {
+		keys = keys[:maxKeys]
+		page.NextContinuationToken = base64.RawURLEncoding.EncodeToString([]byte(keys[len(keys)-1]))
+	}
+	for _, key := range keys {
+		stat, err := os.Stat(filepath.Join(bucketDir, filepath.FromSlash(key)))
+		if err != nil {
+			// 一覧の取得中に削除されたオブジェクトは含めない
+			continue
+		}
+		page.Objects = append(page.Objects, &model.S3ObjectInfo{
+			BucketName:   bucketName,
+			Key:          key,
+			Size:         stat.Size(),
+			LastModified: stat.ModTime(),
+		})
+	}
+	return page, nil
+}
+
+// CopyObject は、同じバケット内でオブジェクトをメタデータと一緒にコピーします
+func (s *LocalStorageService) CopyObject(ctx context.Context, bucketName, sourceKey, destinationKey string) error {
+	source, _, err := s.OpenObject(ctx, bucketName, sourceKey)
+	if err != nil {
+		return fmt.Errorf("failed to copy object: %w", err)
+	}
+	defer func() {
+		if err := source.Close(); err != nil {
+			logger.FromContext(ctx).Warn("Error closing file", "key", sourceKey, "error", err)
+		}
+	}()
+	metadata, err := s.readMetadata(bucketName, sourceKey)
+	if err != nil {
+		return fmt.Errorf("failed to copy object: %w", err)
+	}
+
+	if err := s.putObject(bucketName, destinationKey, source, metadata); err != nil {
+		return fmt.Errorf("failed to copy object: %w", err)
+	}
+	return nil
+}
+
+// DeleteObject は、オブジェクトを削除します。存在しないオブジェクトの削除はエラーにしません (S3と同じ)
+func (s *LocalStorageService) DeleteObject(ctx context.Context, bucketName, key string) error {
+	objectPath, err := s.objectPath(bucketName, key)
+	if err != nil {
+		return fmt.Errorf("failed to delete object: %w", err)
+	}
+	for _, filePath := range []string{objectPath, s.metadataPath(bucketName, key)} {
+		if err := os.Remove(filePath); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return fmt.Errorf("failed to delete object: %w", err)
+		}
+	}
+	return nil
+}
+
+// CreateMultipartUpload は、マルチパートアップロードを開始してアップロードIDを返します
+func (s *LocalStorageService) CreateMultipartUpload(ctx context.Context, bucketName, key, contentType string, metadata map[string]string) (string, error) {
+	if _, err := s.objectPath(bucketName, key); err != nil {
+		return "", fmt.Errorf("failed to create multipart upload: %w", err)
+	}
+	uploadID := uuid.New().String()
+	upload, err := json.Marshal(localMultipartUpload{BucketName: bucketName, Key: key, ContentType: contentType, Metadata: metadata})
+	if err != nil {
+		return "", fmt.Errorf("failed to create multipart upload: %w", err)
+	}
+
+	uploadDir := filepath.Join(s.rootDir, "multipart", uploadID)
+	if err := os.MkdirAll(uploadDir, 0o755); err != nil {
+		return "", fmt.Errorf("failed to create multipart upload: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(uploadDir, "upload.json"), upload, 0o644); err != nil {
+		return "", fmt.Errorf("failed to create multipart upload: %w", err)
+	}
+	return uploadID, nil
+}
+
+// UploadPart は、マルチパートアップロードのパートを1件保存してETag (MD5) を返します。
+// パートはメモリに溜めずに書き込み、サイズとSHA-256が一致しない場合は保存しません (S3と同じ)
+func (s *LocalStorageService) UploadPart(ctx context.Context, session model.UploadSession, part model.UploadPart, body io.Reader) (string, error) {
+	uploadDir, err := s.multipartDir(session.S3UploadID)
+	if err != nil {
+		return "", fmt.Errorf("failed to upload part %d: %w", part.Number, err)
+	}
+
+	hash := md5.New()
+	checksum := sha256.New()
+	err = s.writeFile(filepath.Join(uploadDir, strconv.Itoa(part.Number)), func(w io.Writer) error {
+		written, err := io.Copy(io.MultiWriter(w, hash, checksum), io.LimitReader(body, part.Size+1))
+		if err != nil {
+			return err
+		}
+		if written != part.Size {
+			return domainerr.New(domainerr.Unprocessable, "upload_part_size_mismatch", "part %d has %d bytes but %d bytes were declared", part.Number, written, part.Size)
+		}
+		if actual := hex.EncodeToString(checksum.Sum(nil)); !strings.EqualFold(actual, part.Checksum) {
+			return domainerr.New(domainerr.Unprocessable, "checksum_mismatch", "checksum mismatch for part %d (received %s)", part.Number, actual)
+		}
+		return nil
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to upload part %d: %w", part.Number, err)
+	}
+	return `"` + hex.EncodeToString(hash.Sum(nil)) + `"`, nil
+}
+
+// CompleteMultipartUpload は、受け取り済みのパートを結合してオブジェクトを作成し、s3:// 形式のURIを返します
+func (s *LocalStorageService) CompleteMultipartUpload(ctx context.Context, session model.UploadSession) (string, error) {
+	uploadDir, err := s.multipartDir(session.S3UploadID)
+	if err != nil {
+		return "", fmt.Errorf("failed to complete multipart upload: %w", err)
+	}
+	data, err := os.ReadFile(filepath.Join(uploadDir, "upload.json"))
+	if err != nil {
+		return "", fmt.Errorf("failed to complete multipart upload: %w", err)
+	}
+	var upload localMultipartUpload
+	if err := json.Unmarshal(data, &upload); err != nil {
+		return "", fmt.Errorf("failed to complete multipart upload: %w", err)
+	}
+	objectPath, err := s.objectPath(upload.BucketName, upload.Key)
+	if err != nil {
+		return "", fmt.Errorf("failed to complete multipart upload: %w", err)
+	}
+
+	// ETagを照合しながらパートを番号順に結合する
+	err = s.writeFile(objectPath, func(w io.Writer) error {
+		for _, part := range session.SortedParts() {
+			if err := copyPart(w, filepath.Join(uploadDir, strconv.Itoa(part.Number)), part.ETag); err != nil {
+				return fmt.Errorf("part %d: %w", part.Number, err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to complete multipart upload: %w", err)
+	}
+	if err := s.writeMetadata(upload.BucketName, upload.Key, localObjectMetadata{ContentType: upload.ContentType, Metadata: upload.Metadata}); err != nil {
+		return "", fmt.Errorf("failed to complete multipart upload: %w", err)
+	}
+
+	if err := os.RemoveAll(uploadDir); err != nil {
+		logger.FromContext(ctx).Warn("Failed to remove parts of upload", "upload_id", session.S3UploadID, "error", err)
+	}
+	return objectURI(upload.BucketName, upload.Key), nil
+}
+
+// AbortMultipartUpload は、マルチパートアップロードを中止して保存済みのパートを破棄します
+func (s *LocalStorageService) AbortMultipartUpload(ctx context.Context, session model.UploadSession) error {
+	uploadDir, err := s.multipartDir(session.S3UploadID)
+	if err != nil {
+		return fmt.Errorf("failed to abort multipart upload: %w", err)
+	}
+	if err := os.RemoveAll(uploadDir); err != nil {
+		return fmt.Errorf("failed to abort multipart upload: %w", err)
+	}
+	return nil
+}
+
+// PresignDownload は、オブジェクトをダウンロードするための署名付きURLを生成します
+func (s *LocalStorageService) PresignDownload(ctx context.Context, bucketName, key string, expires time.Duration) (string, error) {
+	if _, err := s.objectPath(bucketName, key); err != nil {
+		return "", fmt.Errorf("failed to generate presigned URL: %w", err)
+	}
+	return s.signedURL(&model.SignedObjectRequest{
+		Method:     http.MethodGet,
+		BucketName: bucketName,
+		Key:        key,
+		ExpiresAt:  time.Now().Add(expires),
+	}), nil
+}
+
+// GeneratePresignedURL は、バケットの key に出力されたジョブの文字起こし結果を取得するための署名付きURLを生成します
+func (s *LocalStorageService) GeneratePresignedURL(ctx context.Context, bucketName, key string) (string, error) {
+	return s.PresignDownload(ctx, bucketName, key, 15*time.Minute) // 15分間有効
+}
+
+// GetTranscriptionContent は、署名付きURLを検証してローカルストレージから文字起こしデータを読み込みます
+func (s *LocalStorageService) GetTranscriptionContent(ctx context.Context, signedURL string) (string, error) {
+	parsed, err := url.Parse(signedURL)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse signed URL: %w", err)
+	}
+	request, err := s.VerifySignedRequest(http.MethodGet, parsed.Query())
+	if err != nil {
+		return "", err
+	}
+
+	objectPath, _ := s.objectPath(request.BucketName, request.Key)
+	content, err := os.ReadFile(objectPath)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return "", domainerr.New(domainerr.NotFound, "transcript_not_found", "transcription content does not exist")
+		}
+		return "", fmt.Errorf("failed to read object: %w", err)
+	}
+	return string(content), nil
+}
+
+// sign は、署名付きURLの署名 (HMAC-SHA256) を計算します
+func (s *LocalStorageService) sign(request *model.SignedObjectRequest) string {
+	mac := hmac.New(sha256.New, s.signingKey)
+	mac.Write([]byte(request.StringToSign()))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// signedURL は、署名をクエリパラメータに含めたURLを返します
+func (s *LocalStorageService) signedURL(request *model.SignedObjectRequest) string {
+	return s.publicURL + LocalStorageObjectPath + "?" + request.Params(s.sign(request)).Encode()
+}
+
+// objectPath は、オブジェクトを保存するファイルのパスを返します。ルートディレクトリの外を指すキーは拒否します
+func (s *LocalStorageService) objectPath(bucketName, key string) (string, error) {
+	if bucketName == "" || strings.HasPrefix(bucketName, ".") || strings.ContainsAny(bucketName, `/\`) {
+		return "", domainerr.New(domainerr.Validation, "invalid_object_key", "invalid bucket name: %s", bucketName)
+	}
+	if key == "" || strings.Contains(key, `\`) {
+		return "", domainerr.New(domainerr.Validation, "invalid_object_key", "invalid object key: %s", key)
+	}
+	for _, segment := range strings.Split(key, "/") {
+		if segment == "" || segment == "." || segment == ".." {
+			return "", domainerr.New(domainerr.Validation, "invalid_object_key", "invalid object key: %s", key)
+		}
+	}
+	return filepath.Join(s.rootDir, "objects", bucketName, filepath.FromSlash(key)), nil
+}
+
+// metadataPath は、オブジェクトのメタデータを保存するファイルのパスを返します
+func (s *LocalStorageService) metadataPath(bucketName, key string) string {
+	return filepath.Join(s.rootDir, "metadata", bucketName, filepath.FromSlash(key)+".json")
+}
+
+// multipartDir は、マルチパートアップロードのパートを保存するディレクトリを返します
+func (s *LocalStorageService) multipartDir(uploadID string) (string, error) {
+	if _, err := uuid.Parse(uploadID); err != nil {
+		return "", domainerr.New(domainerr.NotFound, "upload_not_found", "upload %s does not exist", uploadID)
+	}
+	uploadDir := filepath.Join(s.rootDir, "multipart", uploadID)
+	if _, err := os.Stat(uploadDir); err != nil {
+		return "", domainerr.New(domainerr.NotFound, "upload_not_found", "upload %s does not exist", uploadID)
+	}
+	return uploadDir, nil
+}
+
+// putObject は、オブジェクトとメタデータを保存します
+func (s *LocalStorageService) putObject(bucketName, key string, body io.Reader, metadata localObjectMetadata) error {
+	objectPath, err := s.objectPath(bucketName, key)
+	if err != nil {
+		return err
+	}
+	err = s.writeFile(objectPath, func(w io.Writer) error {
+		_, err := io.Copy(w, body)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	return s.writeMetadata(bucketName, key, metadata)
+}
+
+// readMetadata は、オブジェクトのメタデータを読み込みます。保存されていない場合は空のメタデータを返します
+func (s *LocalStorageService) readMetadata(bucketName, key string) (localObjectMetadata, error) {
+	var metadata localObjectMetadata
+	data, err := os.ReadFile(s.metadataPath(bucketName, key))
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return metadata, nil
+		}
+		return metadata, err
+	}
+	err = json.Unmarshal(data, &metadata)
+	return metadata, err
+}
+
+// writeMetadata は、オブジェクトのメタデータを保存します
+func (s *LocalStorageService) writeMetadata(bucketName, key string, metadata localObjectMetadata) error {
+	data, err := json.Marshal(metadata)
+	if err != nil {
+		return err
+	}
+	return s.writeFile(s.metadataPath(bucketName, key), func(w io.Writer) error {
+		_, err := w.Write(data)
+		return err
+	})
+}
+
+// writeFile は、tmp/ の一時ファイルに書き込んでから置き換えます。
+// 書き込みに失敗した場合や途中で切断された場合に、書きかけのファイルが読まれることはありません
+func (s *LocalStorageService) writeFile(filePath string, write func(w io.Writer) error) error {
+	if err := os.MkdirAll(filepath.Dir(filePath), 0o755); err != nil {
+		return err
+	}
+	tempFile, err := os.CreateTemp(filepath.Join(s.rootDir, "tmp"), "object-*")
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := os.Remove(tempFile.Name()); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			logger.Default().Warn("Error removing temp file", "path", tempFile.Name(), "error", err)
+		}
+	}()
+
+	if err := write(tempFile); err != nil {
+		_ = tempFile.Close()
+		return err
+	}
+	if err := tempFile.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tempFile.Name(), filePath)
+}
+
+// copyPart は、パートのファイルを書き込み、内容がETagと一致することを確認します
+func copyPart(w io.Writer, partPath, etag string) error {
+	part, err := os.Open(partPath)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := part.Close(); err != nil {
+			logger.Default().Warn("Error closing file", "path", partPath, "error", err)
+		}
+	}()
+
+	hash := md5.New()
+	if _, err := io.Copy(io.MultiWriter(w, hash), part); err != nil {
+		return err
+	}
+	if strings.Trim(etag, `"`) != hex.EncodeToString(hash.Sum(nil)) {
+		return fmt.Errorf("ETag does not match")
+	}
+	return nil
+}
+
+// objectURI は、s3:// 形式のURIを返します。ローカルストレージでも同じ形式でオブジェクトを指します
+func objectURI(bucketName, key string) string {
+	return fmt.Sprintf("s3://%s/%s", bucketName, key)
+}
+
+// localObjectReader はローカルストレージのオブジェクトを読み込む io.ReaderAt の実装です。
+// 呼び出し元が閉じる必要のないよう、読み込みのたびにファイルを開きます
+type localObjectReader struct {
+	path string
+}
+
+// ReadAt は、offsetからlen(p)バイトを読み込みます
+func (r *localObjectReader) ReadAt(p []byte, offset int64) (int, error) {
+	file, err := os.Open(r.path)
+	if err != nil {
+		return 0, err
+	}
+	defer func() {
+		if err := file.Close(); err != nil {
+			logger.Default().Warn("Error closing file", "path", r.path, "error", err)
+		}
+	}()
+	return file.ReadAt(p, offset)
+}
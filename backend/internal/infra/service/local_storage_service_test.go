@@ -0,0 +1,99 @@
+package service
+
+import (
+	"cmTranscribe/internal/domain/domainerr"
+	"cmTranscribe/internal/domain/model"
+	"context"
+	"net/http"
+	"net/url"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func newTestLocalStorageService(t *testing.T, signingSecret string) *LocalStorageService {
+	t.Helper()
+	s, err := NewLocalStorageService(t.TempDir(), "http://localhost:8080", signingSecret)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+// signedParams 署名付きURLのクエリパラメータを返します
+func signedParams(t *testing.T, signedURL string) url.Values {
+	t.Helper()
+	parsed, err := url.Parse(signedURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return parsed.Query()
+}
+
+func TestLocalStorageServiceVerifySignedRequest(t *testing.T) {
+	s := newTestLocalStorageService(t, "s3cr3t")
+	presigned, err := s.PresignUpload(context.Background(), "bucket", model.UploadRequest{
+		Method:      http.MethodPut,
+		Key:         "uploads/a.wav",
+		ContentType: "audio/wav",
+		Size:        1024,
+		Owner:       "alice",
+	}, time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expired := s.signedURL(&model.SignedObjectRequest{Method: http.MethodGet, BucketName: "bucket", Key: "uploads/a.wav", ExpiresAt: time.Now().Add(-time.Second)})
+	traversal := s.signedURL(&model.SignedObjectRequest{Method: http.MethodGet, BucketName: "bucket", Key: "uploads/../../secret", ExpiresAt: time.Now().Add(time.Minute)})
+	otherSecret := newTestLocalStorageService(t, "other").signedURL(&model.SignedObjectRequest{Method: http.MethodPut, BucketName: "bucket", Key: "uploads/a.wav", ExpiresAt: time.Now().Add(time.Minute)})
+
+	tests := []struct {
+		name     string
+		method   string
+		url      string
+		modify   func(params url.Values)
+		wantCode string // 空の場合は検証に成功する
+	}{
+		{name: "valid", method: http.MethodPut, url: presigned.URL},
+		{name: "another method", method: http.MethodGet, url: presigned.URL, wantCode: "invalid_signed_url"},
+		{name: "another bucket", method: http.MethodPut, url: presigned.URL, modify: func(p url.Values) { p.Set("bucket", "other") }, wantCode: "invalid_signed_url"},
+		{name: "another key", method: http.MethodPut, url: presigned.URL, modify: func(p url.Values) { p.Set("key", "uploads/b.wav") }, wantCode: "invalid_signed_url"},
+		{name: "another content type", method: http.MethodPut, url: presigned.URL, modify: func(p url.Values) { p.Set("contentType", "text/html") }, wantCode: "invalid_signed_url"},
+		{name: "larger size", method: http.MethodPut, url: presigned.URL, modify: func(p url.Values) { p.Set("size", "2048") }, wantCode: "invalid_signed_url"},
+		{name: "another owner", method: http.MethodPut, url: presigned.URL, modify: func(p url.Values) { p.Set("owner", "bob") }, wantCode: "invalid_signed_url"},
+		{name: "owner removed", method: http.MethodPut, url: presigned.URL, modify: func(p url.Values) { p.Del("owner") }, wantCode: "invalid_signed_url"},
+		{name: "extended expiry", method: http.MethodPut, url: presigned.URL, modify: func(p url.Values) {
+			p.Set("expires", strconv.FormatInt(time.Now().Add(time.Hour).Unix(), 10))
+		}, wantCode: "invalid_signed_url"},
+		{name: "tampered signature", method: http.MethodPut, url: presigned.URL, modify: func(p url.Values) {
+			signature := []byte(p.Get("signature"))
+			signature[0] ^= 1
+			p.Set("signature", string(signature))
+		}, wantCode: "invalid_signed_url"},
+		{name: "no signature", method: http.MethodPut, url: presigned.URL, modify: func(p url.Values) { p.Del("signature") }, wantCode: "invalid_signed_url"},
+		{name: "expired", method: http.MethodGet, url: expired, wantCode: "signed_url_expired"},
+		{name: "key outside the root", method: http.MethodGet, url: traversal, wantCode: "invalid_signed_url"},
+		{name: "signed with another secret", method: http.MethodPut, url: otherSecret, wantCode: "invalid_signed_url"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			params := signedParams(t, tt.url)
+			if tt.modify != nil {
+				tt.modify(params)
+			}
+			request, err := s.VerifySignedRequest(tt.method, params)
+			if tt.wantCode == "" {
+				if err != nil {
+					t.Fatalf("VerifySignedRequest() error = %v", err)
+				}
+				if request.Key != "uploads/a.wav" || request.Owner != "alice" || request.Size != 1024 {
+					t.Errorf("request = %+v, want the signed upload", request)
+				}
+				return
+			}
+			domainErr, ok := domainerr.As(err)
+			if !ok || domainErr.Kind != domainerr.Forbidden || domainErr.Code != tt.wantCode {
+				t.Fatalf("VerifySignedRequest() error = %v, want %s", err, tt.wantCode)
+			}
+		})
+	}
+}
@@ -0,0 +1,607 @@
+package service
+
+import (
+	"bytes"
+	"cmTranscribe/internal/domain/model"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+)
+
+// maxMP4MetadataBytes メモリに読み込むmoovボックスのサイズの上限
+const maxMP4MetadataBytes = 64 * 1024 * 1024
+
+// oggTailBytes 最後のOggページを探すために読み込む末尾のバイト数
+const oggTailBytes = 64 * 1024
+
+// MediaProber はメディアファイルのヘッダーを外部コマンドを使わずに解析する実装です。
+// WAV, MP3, FLAC, OGG (Vorbis, Opus, FLAC), MP4/M4A に対応し、それ以外のフォーマットはフォーマットのみを返します
+type MediaProber struct{}
+
+// NewMediaProber は MediaProber のインスタンスを生成します
+func NewMediaProber() *MediaProber {
+	return &MediaProber{}
+}
+
+// Probe は、メディアファイルのフォーマット、コーデック、長さ、サンプルレート、チャンネル数を読み取ります
+func (p *MediaProber) Probe(r io.ReaderAt, size int64) (*model.MediaInfo, error) {
+	header, err := readAt(r, 0, model.MediaSniffLength, size)
+	if err != nil {
+		return nil, err
+	}
+	format := model.DetectMediaFormat(header)
+
+	switch format {
+	case model.MediaFormatWAV:
+		return probeWAV(r, size)
+	case model.MediaFormatFLAC:
+		return probeFLAC(r, size)
+	case model.MediaFormatOGG:
+		return probeOGG(r, size)
+	case model.MediaFormatMP3:
+		return probeMP3(r, size)
+	case model.MediaFormatMP4, model.MediaFormatM4A:
+		info, err := probeMP4(r, size)
+		if err != nil {
+			return nil, err
+		}
+		info.Format = format
+		return info, nil
+	case "":
+		return nil, fmt.Errorf("unsupported media format")
+	}
+	// フォーマットのみ判定できたもの (webmなど)
+	return &model.MediaInfo{Format: format}, nil
+}
+
+// readAt は、offsetから最大length バイトを読み込みます (ファイルの終端で短くなります)
+func readAt(r io.ReaderAt, offset, length, size int64) ([]byte, error) {
+	if offset >= size {
+		return nil, io.ErrUnexpectedEOF
+	}
+	if offset+length > size {
+		length = size - offset
+	}
+	buf := make([]byte, length)
+	n, err := r.ReadAt(buf, offset)
+	if err != nil && !(err == io.EOF && int64(n) == length) {
+		return nil, fmt.Errorf("failed to read media: %w", err)
+	}
+	return buf, nil
+}
+
+// probeWAV は、RIFFのfmtチャンクとdataチャンクを読み取ります。fmtチャンクはdataチャンクの後にあっても構いません
+func probeWAV(r io.ReaderAt, size int64) (*model.MediaInfo, error) {
+	info := &model.MediaInfo{Format: model.MediaFormatWAV}
+	var byteRate uint32
+	var dataSize int64 = -1
+
+	for offset := int64(12); offset+8 <= size && (dataSize < 0 || info.SampleRateHertz == 0); {
+		chunk, err := readAt(r, offset, 8, size)
+		if err != nil {
+			return nil, err
+		}
+		id := string(chunk[0:4])
+		chunkSize := int64(binary.LittleEndian.Uint32(chunk[4:8]))
+
+		switch id {
+		case "fmt ":
+			fmtChunk, err := readAt(r, offset+8, 40, size)
+			if err != nil || len(fmtChunk) < 16 {
+				return nil, fmt.Errorf("invalid WAV fmt chunk")
+			}
+			audioFormat := binary.LittleEndian.Uint16(fmtChunk[0:2])
+			info.Channels = int(binary.LittleEndian.Uint16(fmtChunk[2:4]))
+			info.SampleRateHertz = int(binary.LittleEndian.Uint32(fmtChunk[4:8]))
+			byteRate = binary.LittleEndian.Uint32(fmtChunk[8:12])
+			bitsPerSample := binary.LittleEndian.Uint16(fmtChunk[14:16])
+			// WAVE_FORMAT_EXTENSIBLEの場合はサブフォーマットの先頭2バイトが実際のフォーマット
+			if audioFormat == 0xFFFE && len(fmtChunk) >= 26 && chunkSize >= 26 {
+				audioFormat = binary.LittleEndian.Uint16(fmtChunk[24:26])
+			}
+			info.Codec = wavCodec(audioFormat, bitsPerSample)
+		case "data":
+			dataSize = chunkSize
+			// ストリーミングで書き出されたファイルはサイズが未設定のことがある。
+			// その場合はdataチャンクがファイルの終わりまで続くため、後ろにチャンクは無い
+			if dataSize == 0 || dataSize == 0xFFFFFFFF || offset+8+dataSize > size {
+				dataSize = size - offset - 8
+				chunkSize = dataSize
+			}
+		}
+		// チャンクは2バイト境界に揃えられる
+		offset += 8 + chunkSize + chunkSize%2
+	}
+
+	if info.SampleRateHertz == 0 {
+		return nil, fmt.Errorf("WAV file has no fmt chunk")
+	}
+	if byteRate > 0 && dataSize > 0 {
+		info.DurationSeconds = float64(dataSize) / float64(byteRate)
+	}
+	return info, nil
+}
+
+// wavCodec WAVのフォーマットタグからコーデック名を返します
+func wavCodec(audioFormat, bitsPerSample uint16) string {
+	switch audioFormat {
+	case 1:
+		if bitsPerSample == 8 {
+			return "pcm_u8"
+		}
+		return fmt.Sprintf("pcm_s%dle", bitsPerSample)
+	case 3:
+		return fmt.Sprintf("pcm_f%dle", bitsPerSample)
+	case 6:
+		return "pcm_alaw"
+	case 7:
+		return "pcm_mulaw"
+	case 2, 0x11:
+		return "adpcm"
+	}
+	return fmt.Sprintf("wav_0x%04x", audioFormat)
+}
+
+// probeFLAC は、FLACのSTREAMINFOブロックを読み取ります
+func probeFLAC(r io.ReaderAt, size int64) (*model.MediaInfo, error) {
+	block, err := readAt(r, 4, 4+34, size)
+	if err != nil || len(block) < 38 || block[0]&0x7F != 0 {
+		return nil, fmt.Errorf("FLAC file has no STREAMINFO block")
+	}
+	info := parseFLACStreamInfo(block[4:])
+	info.Format = model.MediaFormatFLAC
+	return info, nil
+}
+
+// parseFLACStreamInfo STREAMINFOブロック (34バイト) からサンプルレート、チャンネル数、長さを読み取ります
+func parseFLACStreamInfo(streamInfo []byte) *model.MediaInfo {
+	packed := binary.BigEndian.Uint64(streamInfo[10:18])
+	sampleRate := int(packed >> 44)
+	totalSamples := packed & 0xFFFFFFFFF
+	info := &model.MediaInfo{
+		Codec:           "flac",
+		SampleRateHertz: sampleRate,
+		Channels:        int((packed>>41)&0x07) + 1,
+	}
+	if sampleRate > 0 {
+		info.DurationSeconds = float64(totalSamples) / float64(sampleRate)
+	}
+	return info
+}
+
+// probeOGG は、最初のパケットからコーデックを判定し、最後のページのグラニュール位置から長さを求めます
+func probeOGG(r io.ReaderAt, size int64) (*model.MediaInfo, error) {
+	page, err := readAt(r, 0, 27+255+64, size)
+	if err != nil || len(page) < 28 {
+		return nil, fmt.Errorf("invalid Ogg page")
+	}
+	packetStart := 27 + int(page[26])
+	if len(page) < packetStart+19 {
+		return nil, fmt.Errorf("invalid Ogg page")
+	}
+	packet := page[packetStart:]
+
+	info := &model.MediaInfo{Format: model.MediaFormatOGG}
+	var preSkip uint64
+	var granuleRate int
+	switch {
+	case len(packet) >= 16 && packet[0] == 0x01 && bytes.Equal(packet[1:7], []byte("vorbis")):
+		info.Codec = "vorbis"
+		info.Channels = int(packet[11])
+		info.SampleRateHertz = int(binary.LittleEndian.Uint32(packet[12:16]))
+		granuleRate = info.SampleRateHertz
+	case len(packet) >= 19 && bytes.Equal(packet[0:8], []byte("OpusHead")):
+		// Opusは常に48kHzでデコードされ、グラニュール位置も48kHz単位
+		info.Codec = "opus"
+		info.Channels = int(packet[9])
+		info.SampleRateHertz = 48000
+		preSkip = uint64(binary.LittleEndian.Uint16(packet[10:12]))
+		granuleRate = 48000
+	case len(packet) >= 13+4+34 && bytes.Equal(packet[0:5], []byte("\x7FFLAC")):
+		flacInfo := parseFLACStreamInfo(packet[13+4:])
+		info.Codec = flacInfo.Codec
+		info.Channels = flacInfo.Channels
+		info.SampleRateHertz = flacInfo.SampleRateHertz
+		granuleRate = info.SampleRateHertz
+	default:
+		return nil, fmt.Errorf("unsupported Ogg codec")
+	}
+
+	if granule, ok := lastOggGranule(r, size); ok && granuleRate > 0 && granule > preSkip {
+		info.DurationSeconds = float64(granule-preSkip) / float64(granuleRate)
+	}
+	return info, nil
+}
+
+// lastOggGranule ファイル末尾の最後のOggページのグラニュール位置を返します
+func lastOggGranule(r io.ReaderAt, size int64) (uint64, bool) {
+	offset := size - oggTailBytes
+	if offset < 0 {
+		offset = 0
+	}
+	tail, err := readAt(r, offset, size-offset, size)
+	if err != nil {
+		return 0, false
+	}
+	for i := bytes.LastIndex(tail, []byte("OggS")); i >= 0; i = bytes.LastIndex(tail[:i], []byte("OggS")) {
+		if i+14 <= len(tail) {
+			granule := binary.LittleEndian.Uint64(tail[i+6 : i+14])
+			// -1 はそのページで終わるパケットが無いことを示す
+			if granule != 0xFFFFFFFFFFFFFFFF {
+				return granule, true
+			}
+		}
+	}
+	return 0, false
+}
+
+// MPEGオーディオのビットレート (kbps) [MPEG1かどうか][レイヤー][インデックス]
+var mpegBitrates = [2][3][16]int{
+	{ // MPEG2, MPEG2.5
+		{0, 32, 48, 56, 64, 80, 96, 112, 128, 144, 160, 176, 192, 224, 256, 0},
+		{0, 8, 16, 24, 32, 40, 48, 56, 64, 80, 96, 112, 128, 144, 160, 0},
+		{0, 8, 16, 24, 32, 40, 48, 56, 64, 80, 96, 112, 128, 144, 160, 0},
+	},
+	{ // MPEG1
+		{0, 32, 64, 96, 128, 160, 192, 224, 256, 288, 320, 352, 384, 416, 448, 0},
+		{0, 32, 48, 56, 64, 80, 96, 112, 128, 160, 192, 224, 256, 320, 384, 0},
+		{0, 32, 40, 48, 56, 64, 80, 96, 112, 128, 160, 192, 224, 256, 320, 0},
+	},
+}
+
+// MPEG1のサンプルレート (MPEG2は1/2、MPEG2.5は1/4)
+var mpegSampleRates = [3]int{44100, 48000, 32000}
+
+// mpegFrame MPEGオーディオのフレームヘッダー
+type mpegFrame struct {
+	version         int // 1: MPEG1, 2: MPEG2, 25: MPEG2.5
+	layer           int // 1, 2, 3
+	bitrate         int // bps
+	sampleRate      int
+	channels        int
+	samplesPerFrame int
+	length          int // フレームのバイト数
+}
+
+// parseMPEGFrame フレームヘッダー (4バイト) を解析します
+func parseMPEGFrame(h []byte) (*mpegFrame, bool) {
+	if len(h) < 4 || h[0] != 0xFF || h[1]&0xE0 != 0xE0 {
+		return nil, false
+	}
+	versionBits := (h[1] >> 3) & 0x03
+	layerBits := (h[1] >> 1) & 0x03
+	bitrateIndex := h[2] >> 4
+	sampleRateIndex := (h[2] >> 2) & 0x03
+	if versionBits == 0x01 || layerBits == 0x00 || bitrateIndex == 0x0F || bitrateIndex == 0 || sampleRateIndex == 0x03 {
+		return nil, false
+	}
+
+	frame := &mpegFrame{layer: 4 - int(layerBits)}
+	mpeg1 := 0
+	sampleRate := mpegSampleRates[sampleRateIndex]
+	switch versionBits {
+	case 0x03:
+		frame.version, mpeg1 = 1, 1
+	case 0x02:
+		frame.version, sampleRate = 2, sampleRate/2
+	default:
+		frame.version, sampleRate = 25, sampleRate/4
+	}
+	frame.sampleRate = sampleRate
+	frame.bitrate = mpegBitrates[mpeg1][frame.layer-1][bitrateIndex] * 1000
+	frame.channels = 2
+	if h[3]>>6 == 0x03 {
+		frame.channels = 1
+	}
+
+	padding := int((h[2] >> 1) & 0x01)
+	switch {
+	case frame.layer == 1:
+		frame.samplesPerFrame = 384
+		frame.length = (12*frame.bitrate/sampleRate + padding) * 4
+	case frame.layer == 3 && frame.version != 1:
+		frame.samplesPerFrame = 576
+		frame.length = 72*frame.bitrate/sampleRate + padding
+	default:
+		frame.samplesPerFrame = 1152
+		frame.length = 144*frame.bitrate/sampleRate + padding
+	}
+	return frame, true
+}
+
+// probeMP3 は、ID3v2タグを読み飛ばして最初のフレームを解析し、Xing/VBRIヘッダーまたはビットレートから長さを求めます
+func probeMP3(r io.ReaderAt, size int64) (*model.MediaInfo, error) {
+	audioStart := int64(0)
+	if id3, err := readAt(r, 0, 10, size); err == nil && len(id3) == 10 && bytes.HasPrefix(id3, []byte("ID3")) {
+		// ID3v2のサイズは7ビットずつのsyncsafe整数
+		tagSize := int64(id3[6])<<21 | int64(id3[7])<<14 | int64(id3[8])<<7 | int64(id3[9])
+		audioStart = 10 + tagSize
+		if id3[5]&0x10 != 0 {
+			audioStart += 10
+		}
+	}
+
+	buf, err := readAt(r, audioStart, 64*1024, size)
+	if err != nil {
+		return nil, fmt.Errorf("MP3 file has no audio frames")
+	}
+	var frame *mpegFrame
+	offset := 0
+	for ; offset+4 <= len(buf); offset++ {
+		candidate, ok := parseMPEGFrame(buf[offset:])
+		if !ok {
+			continue
+		}
+		// 次のフレームも同期ワードで始まることを確認して誤検出を避ける
+		next := offset + candidate.length
+		if next+4 <= len(buf) {
+			if _, ok := parseMPEGFrame(buf[next:]); !ok {
+				continue
+			}
+		}
+		frame = candidate
+		break
+	}
+	if frame == nil {
+		return nil, fmt.Errorf("MP3 file has no audio frames")
+	}
+
+	info := &model.MediaInfo{
+		Format:          model.MediaFormatMP3,
+		Codec:           fmt.Sprintf("mp%d", frame.layer),
+		SampleRateHertz: frame.sampleRate,
+		Channels:        frame.channels,
+	}
+
+	if frames, ok := mpegVBRFrameCount(buf[offset:], frame); ok {
+		info.DurationSeconds = float64(frames) * float64(frame.samplesPerFrame) / float64(frame.sampleRate)
+		return info, nil
+	}
+
+	// CBRとみなしてファイルサイズから求める (末尾のID3v1タグを除く)
+	audioBytes := size - audioStart - int64(offset)
+	if tag, err := readAt(r, size-128, 3, size); err == nil && bytes.Equal(tag, []byte("TAG")) {
+		audioBytes -= 128
+	}
+	if frame.bitrate > 0 && audioBytes > 0 {
+		info.DurationSeconds = float64(audioBytes) * 8 / float64(frame.bitrate)
+	}
+	return info, nil
+}
+
+// mpegVBRFrameCount 最初のフレームのXing/Info またはVBRIヘッダーからフレーム数を返します
+func mpegVBRFrameCount(buf []byte, frame *mpegFrame) (uint32, bool) {
+	// Xingヘッダーの位置はバージョンとチャンネル数で決まる
+	sideInfo := 32
+	switch {
+	case frame.version == 1 && frame.channels == 1:
+		sideInfo = 17
+	case frame.version != 1 && frame.channels == 2:
+		sideInfo = 17
+	case frame.version != 1:
+		sideInfo = 9
+	}
+	xing := 4 + sideInfo
+	if len(buf) >= xing+12 && (bytes.Equal(buf[xing:xing+4], []byte("Xing")) || bytes.Equal(buf[xing:xing+4], []byte("Info"))) {
+		flags := binary.BigEndian.Uint32(buf[xing+4 : xing+8])
+		if flags&0x01 != 0 {
+			return binary.BigEndian.Uint32(buf[xing+8 : xing+12]), true
+		}
+	}
+	if len(buf) >= 36+18 && bytes.Equal(buf[36:40], []byte("VBRI")) {
+		return binary.BigEndian.Uint32(buf[36+14 : 36+18]), true
+	}
+	return 0, false
+}
+
+// mp4Box MP4のボックス
+type mp4Box struct {
+	boxType string
+	payload []byte
+}
+
+// mp4Codecs サンプルエントリのタイプとコーデック名の対応
+var mp4Codecs = map[string]string{
+	"mp4a": "aac",
+	"alac": "alac",
+	"Opus": "opus",
+	"fLaC": "flac",
+	"ac-3": "ac3",
+	"ec-3": "eac3",
+	".mp3": "mp3",
+	"avc1": "h264",
+	"avc3": "h264",
+	"hvc1": "hevc",
+	"hev1": "hevc",
+	"av01": "av1",
+	"vp09": "vp9",
+	"mp4v": "mpeg4",
+}
+
+// probeMP4 は、moovボックスのmvhdと各トラックのmdhd、hdlr、stsdを読み取ります
+func probeMP4(r io.ReaderAt, size int64) (*model.MediaInfo, error) {
+	moov, err := findMP4Moov(r, size)
+	if err != nil {
+		return nil, err
+	}
+
+	info := &model.MediaInfo{}
+	hasAudio := false
+	for _, box := range parseMP4Boxes(moov) {
+		switch box.boxType {
+		case "mvhd":
+			if timescale, duration, ok := parseMP4Duration(box.payload, 12); ok && timescale > 0 {
+				info.DurationSeconds = float64(duration) / float64(timescale)
+			}
+		case "trak":
+			track := probeMP4Track(box.payload)
+			switch track.handler {
+			case "soun":
+				if hasAudio {
+					continue
+				}
+				hasAudio = true
+				info.Codec = track.codec
+				info.Channels = track.channels
+				info.SampleRateHertz = track.sampleRate
+				if info.DurationSeconds == 0 {
+					info.DurationSeconds = track.duration
+				}
+			case "vide":
+				if info.VideoCodec == "" {
+					info.VideoCodec = track.codec
+				}
+			}
+		}
+	}
+	if !hasAudio {
+		return nil, fmt.Errorf("MP4 file has no audio track")
+	}
+	return info, nil
+}
+
+// findMP4Moov トップレベルのボックスをたどってmoovボックスを読み込みます (mdatは読み飛ばします)
+func findMP4Moov(r io.ReaderAt, size int64) ([]byte, error) {
+	for offset := int64(0); offset+8 <= size; {
+		header, err := readAt(r, offset, 16, size)
+		if err != nil {
+			return nil, err
+		}
+		boxSize := int64(binary.BigEndian.Uint32(header[0:4]))
+		headerSize := int64(8)
+		switch boxSize {
+		case 0:
+			boxSize = size - offset
+		case 1:
+			if len(header) < 16 {
+				return nil, fmt.Errorf("invalid MP4 box")
+			}
+			boxSize = int64(binary.BigEndian.Uint64(header[8:16]))
+			headerSize = 16
+		}
+		// 不正なサイズでオフセットが溢れないよう、加算せずに残りのバイト数と比べる
+		if boxSize < headerSize || boxSize > size-offset {
+			return nil, fmt.Errorf("invalid MP4 box")
+		}
+
+		if string(header[4:8]) == "moov" {
+			if boxSize-headerSize > maxMP4MetadataBytes {
+				return nil, fmt.Errorf("MP4 metadata is too large")
+			}
+			return readAt(r, offset+headerSize, boxSize-headerSize, size)
+		}
+		offset += boxSize
+	}
+	return nil, fmt.Errorf("MP4 file has no moov box")
+}
+
+// parseMP4Boxes バイト列に並んだボックスを解析します
+func parseMP4Boxes(data []byte) []mp4Box {
+	var boxes []mp4Box
+	for offset := 0; len(data)-offset >= 8; {
+		boxSize := uint64(binary.BigEndian.Uint32(data[offset : offset+4]))
+		headerSize := uint64(8)
+		if boxSize == 1 {
+			if len(data)-offset < 16 {
+				break
+			}
+			boxSize = binary.BigEndian.Uint64(data[offset+8 : offset+16])
+			headerSize = 16
+		} else if boxSize == 0 {
+			boxSize = uint64(len(data) - offset)
+		}
+		// 不正なサイズでintが溢れないよう、加算せずに残りのバイト数と比べる
+		if boxSize < headerSize || boxSize > math.MaxInt32 || boxSize > uint64(len(data)-offset) {
+			break
+		}
+		end := offset + int(boxSize)
+		boxes = append(boxes, mp4Box{
+			boxType: string(data[offset+4 : offset+8]),
+			payload: data[offset+int(headerSize) : end],
+		})
+		offset = end
+	}
+	return boxes
+}
+
+// findMP4Box パスに沿って入れ子のボックスを探します
+func findMP4Box(data []byte, path ...string) ([]byte, bool) {
+	for _, boxType := range path {
+		found := false
+		for _, box := range parseMP4Boxes(data) {
+			if box.boxType == boxType {
+				data, found = box.payload, true
+				break
+			}
+		}
+		if !found {
+			return nil, false
+		}
+	}
+	return data, true
+}
+
+// parseMP4Duration mvhd/mdhdのタイムスケールと長さを読み取ります。offsetはバージョン0のときのタイムスケールの位置です
+func parseMP4Duration(payload []byte, offset int) (uint32, uint64, bool) {
+	if len(payload) < 1 {
+		return 0, 0, false
+	}
+	if payload[0] == 1 {
+		// バージョン1は作成・更新日時と長さが64ビット
+		offset += 8
+		if len(payload) < offset+12 {
+			return 0, 0, false
+		}
+		return binary.BigEndian.Uint32(payload[offset : offset+4]), binary.BigEndian.Uint64(payload[offset+4 : offset+12]), true
+	}
+	if len(payload) < offset+8 {
+		return 0, 0, false
+	}
+	return binary.BigEndian.Uint32(payload[offset : offset+4]), uint64(binary.BigEndian.Uint32(payload[offset+4 : offset+8])), true
+}
+
+// mp4Track トラックから読み取った情報
+type mp4Track struct {
+	handler    string
+	codec      string
+	channels   int
+	sampleRate int
+	duration   float64
+}
+
+// probeMP4Track trakボックスからハンドラー、コーデック、チャンネル数、サンプルレートを読み取ります
+func probeMP4Track(trak []byte) mp4Track {
+	var track mp4Track
+	var timescale uint32
+	if hdlr, ok := findMP4Box(trak, "mdia", "hdlr"); ok && len(hdlr) >= 12 {
+		track.handler = string(hdlr[8:12])
+	}
+	if mdhd, ok := findMP4Box(trak, "mdia", "mdhd"); ok {
+		var duration uint64
+		if timescale, duration, ok = parseMP4Duration(mdhd, 12); ok && timescale > 0 {
+			track.duration = float64(duration) / float64(timescale)
+		}
+	}
+
+	stsd, ok := findMP4Box(trak, "mdia", "minf", "stbl", "stsd")
+	// stsdはバージョン・フラグとエントリ数の後にサンプルエントリが続く
+	if !ok || len(stsd) < 16 {
+		return track
+	}
+	entry := stsd[8:]
+	entryType := string(entry[4:8])
+	track.codec = mp4Codecs[entryType]
+	if track.codec == "" {
+		track.codec = entryType
+	}
+	if track.handler == "soun" && len(entry) >= 36 {
+		track.channels = int(binary.BigEndian.Uint16(entry[24:26]))
+		// サンプルレートは16.16の固定小数点。収まらない場合はメディアのタイムスケールを使う
+		track.sampleRate = int(binary.BigEndian.Uint32(entry[32:36]) >> 16)
+		if track.sampleRate == 0 {
+			track.sampleRate = int(timescale)
+		}
+	}
+	return track
+}
@@ -0,0 +1,223 @@
+package service
+
+import (
+	"bytes"
+	"cmTranscribe/internal/domain/model"
+	"encoding/binary"
+	"math"
+	"testing"
+)
+
+// mp4BoxBytes タイプとペイロードからボックスのバイト列を作ります
+func mp4BoxBytes(boxType string, payload ...[]byte) []byte {
+	body := bytes.Join(payload, nil)
+	box := make([]byte, 8, 8+len(body))
+	binary.BigEndian.PutUint32(box[0:4], uint32(8+len(body)))
+	copy(box[4:8], boxType)
+	return append(box, body...)
+}
+
+// mp4LargeBoxHeader 64ビットのサイズを持つボックスのヘッダーを作ります
+func mp4LargeBoxHeader(boxType string, size uint64) []byte {
+	header := make([]byte, 16)
+	binary.BigEndian.PutUint32(header[0:4], 1)
+	copy(header[4:8], boxType)
+	binary.BigEndian.PutUint64(header[8:16], size)
+	return header
+}
+
+// mp4SizedBoxHeader 任意の32ビットのサイズを持つボックスのヘッダーを作ります
+func mp4SizedBoxHeader(boxType string, size uint32) []byte {
+	header := make([]byte, 8)
+	binary.BigEndian.PutUint32(header[0:4], size)
+	copy(header[4:8], boxType)
+	return header
+}
+
+// testMP4 ftyp、mdat、moov (音声トラック1つ) からなるMP4を作ります
+func testMP4() []byte {
+	mvhd := make([]byte, 20)
+	binary.BigEndian.PutUint32(mvhd[12:16], 1000)  // タイムスケール
+	binary.BigEndian.PutUint32(mvhd[16:20], 65300) // 長さ
+	hdlr := make([]byte, 12)
+	copy(hdlr[8:12], "soun")
+	entry := make([]byte, 36)
+	copy(entry[4:8], "mp4a")
+	binary.BigEndian.PutUint16(entry[24:26], 2)
+	binary.BigEndian.PutUint32(entry[32:36], 44100<<16)
+	stsd := append(make([]byte, 8), entry...)
+
+	trak := mp4BoxBytes("trak", mp4BoxBytes("mdia",
+		mp4BoxBytes("hdlr", hdlr),
+		mp4BoxBytes("minf", mp4BoxBytes("stbl", mp4BoxBytes("stsd", stsd))),
+	))
+	return bytes.Join([][]byte{
+		mp4BoxBytes("ftyp", []byte("M4A "), make([]byte, 4)),
+		mp4BoxBytes("mdat", make([]byte, 32)),
+		mp4BoxBytes("moov", mp4BoxBytes("mvhd", mvhd), trak),
+	}, nil)
+}
+
+func TestParseMP4Boxes(t *testing.T) {
+	tests := []struct {
+		name  string
+		data  []byte
+		types []string
+	}{
+		{
+			name:  "boxes",
+			data:  append(mp4BoxBytes("free", []byte{1, 2}), mp4BoxBytes("mvhd", make([]byte, 4))...),
+			types: []string{"free", "mvhd"},
+		},
+		{
+			name:  "size zero extends to the end",
+			data:  append(mp4BoxBytes("free"), append(mp4SizedBoxHeader("mdat", 0), 1, 2, 3)...),
+			types: []string{"free", "mdat"},
+		},
+		{
+			name:  "64-bit size",
+			data:  append(mp4LargeBoxHeader("mdat", 20), 1, 2, 3, 4),
+			types: []string{"mdat"},
+		},
+		{
+			name:  "truncated header",
+			data:  append(mp4BoxBytes("free"), 0, 0, 0),
+			types: []string{"free"},
+		},
+		{
+			name:  "truncated 64-bit header",
+			data:  append(mp4BoxBytes("free"), mp4LargeBoxHeader("mdat", 20)[:12]...),
+			types: []string{"free"},
+		},
+		{
+			name:  "size beyond the data",
+			data:  append(mp4BoxBytes("free"), append(mp4SizedBoxHeader("mdat", 100), 1, 2)...),
+			types: []string{"free"},
+		},
+		{
+			name:  "size smaller than the header",
+			data:  append(mp4SizedBoxHeader("mdat", 4), 1, 2, 3, 4),
+			types: nil,
+		},
+		{
+			name:  "64-bit size smaller than the header",
+			data:  append(mp4LargeBoxHeader("mdat", 12), 1, 2, 3, 4),
+			types: nil,
+		},
+		{
+			name:  "64-bit size overflowing int",
+			data:  append(mp4LargeBoxHeader("mdat", math.MaxUint64), 1, 2, 3, 4),
+			types: nil,
+		},
+		{
+			name:  "64-bit size above MaxInt32",
+			data:  append(mp4LargeBoxHeader("mdat", math.MaxInt32+1), 1, 2, 3, 4),
+			types: nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			boxes := parseMP4Boxes(tt.data)
+			if len(boxes) != len(tt.types) {
+				t.Fatalf("got %d boxes, want %v", len(boxes), tt.types)
+			}
+			for i, box := range boxes {
+				if box.boxType != tt.types[i] {
+					t.Errorf("box %d: got %q, want %q", i, box.boxType, tt.types[i])
+				}
+			}
+		})
+	}
+}
+
+func TestProbeMP4(t *testing.T) {
+	valid := testMP4()
+	ftyp := mp4BoxBytes("ftyp", []byte("M4A "), make([]byte, 4))
+	tests := []struct {
+		name    string
+		data    []byte
+		wantErr bool
+	}{
+		{name: "valid", data: valid},
+		{name: "truncated moov", data: valid[:len(valid)-10], wantErr: true},
+		{name: "no moov", data: append(ftyp, mp4BoxBytes("mdat", make([]byte, 8))...), wantErr: true},
+		{name: "box beyond the file", data: append(ftyp, append(mp4SizedBoxHeader("mdat", 1000), make([]byte, 8)...)...), wantErr: true},
+		{name: "64-bit size overflowing the offset", data: append(ftyp, append(mp4LargeBoxHeader("mdat", math.MaxInt64), make([]byte, 8)...)...), wantErr: true},
+		{name: "negative 64-bit size", data: append(ftyp, append(mp4LargeBoxHeader("mdat", math.MaxUint64), make([]byte, 8)...)...), wantErr: true},
+		{name: "moov above the metadata limit", data: append(ftyp, mp4LargeBoxHeader("moov", maxMP4MetadataBytes+17)...), wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			info, err := NewMediaProber().Probe(bytes.NewReader(tt.data), int64(len(tt.data)))
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("expected an error, got %+v", info)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if info.Format != model.MediaFormatM4A || info.Codec != "aac" || info.SampleRateHertz != 44100 || info.Channels != 2 || info.DurationSeconds != 65.3 {
+				t.Errorf("unexpected media info: %+v", info)
+			}
+		})
+	}
+}
+
+// wavChunk IDと内容からRIFFのチャンクを作ります
+func wavChunk(id string, body []byte) []byte {
+	chunk := make([]byte, 8, 8+len(body))
+	copy(chunk[0:4], id)
+	binary.LittleEndian.PutUint32(chunk[4:8], uint32(len(body)))
+	return append(chunk, body...)
+}
+
+// testWAV チャンクを並べたWAVを作ります
+func testWAV(chunks ...[]byte) []byte {
+	body := append([]byte("WAVE"), bytes.Join(chunks, nil)...)
+	header := make([]byte, 8)
+	copy(header[0:4], "RIFF")
+	binary.LittleEndian.PutUint32(header[4:8], uint32(len(body)))
+	return append(header, body...)
+}
+
+func TestProbeWAV(t *testing.T) {
+	// 16kHz モノラル 16ビットPCM
+	fmtBody := make([]byte, 16)
+	binary.LittleEndian.PutUint16(fmtBody[0:2], 1)
+	binary.LittleEndian.PutUint16(fmtBody[2:4], 1)
+	binary.LittleEndian.PutUint32(fmtBody[4:8], 16000)
+	binary.LittleEndian.PutUint32(fmtBody[8:12], 32000)
+	binary.LittleEndian.PutUint16(fmtBody[14:16], 16)
+	data := make([]byte, 64000)
+
+	tests := []struct {
+		name     string
+		data     []byte
+		duration float64
+		wantErr  bool
+	}{
+		{name: "fmt before data", data: testWAV(wavChunk("fmt ", fmtBody), wavChunk("data", data)), duration: 2},
+		{name: "fmt after data", data: testWAV(wavChunk("data", data), wavChunk("fmt ", fmtBody)), duration: 2},
+		{name: "other chunks", data: testWAV(wavChunk("LIST", []byte("INFO")), wavChunk("fmt ", fmtBody), wavChunk("data", data)), duration: 2},
+		{name: "no fmt", data: testWAV(wavChunk("data", data)), wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			info, err := NewMediaProber().Probe(bytes.NewReader(tt.data), int64(len(tt.data)))
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("expected an error, got %+v", info)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if info.Codec != "pcm_s16le" || info.SampleRateHertz != 16000 || info.Channels != 1 || info.DurationSeconds != tt.duration {
+				t.Errorf("unexpected media info: %+v", info)
+			}
+		})
+	}
+}
@@ -0,0 +1,80 @@
+package service
+
+import (
+	"cmTranscribe/internal/shared/logger"
+	"context"
+	"fmt"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"io"
+	"sync"
+)
+
+// s3ReadChunkSize 1回のRangeリクエストで読み込む最小のバイト数
+const s3ReadChunkSize = 64 * 1024
+
+// s3ObjectReader はS3オブジェクトをRangeリクエストで部分的に読み込む io.ReaderAt の実装です。
+// ヘッダーの解析では近い位置を何度も読むため、直前に読み込んだ範囲を保持します
+type s3ObjectReader struct {
+	ctx        context.Context
+	client     *s3.Client
+	bucketName string
+	key        string
+	size       int64
+
+	mu          sync.Mutex
+	cacheOffset int64
+	cache       []byte
+}
+
+// ReadAt は、offsetからlen(p)バイトを読み込みます
+func (r *s3ObjectReader) ReadAt(p []byte, offset int64) (int, error) {
+	if offset >= r.size {
+		return 0, io.EOF
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	end := offset + int64(len(p))
+	if end > r.size {
+		end = r.size
+	}
+	if offset < r.cacheOffset || end > r.cacheOffset+int64(len(r.cache)) {
+		length := end - offset
+		if length < s3ReadChunkSize {
+			length = s3ReadChunkSize
+		}
+		if offset+length > r.size {
+			length = r.size - offset
+		}
+		data, err := r.fetch(offset, length)
+		if err != nil {
+			return 0, err
+		}
+		r.cacheOffset, r.cache = offset, data
+	}
+
+	n := copy(p, r.cache[offset-r.cacheOffset:])
+	if n < len(p) {
+		return n, io.EOF
+	}
+	return n, nil
+}
+
+// fetch は、Rangeリクエストでオブジェクトの一部を取得します
+func (r *s3ObjectReader) fetch(offset, length int64) ([]byte, error) {
+	output, err := r.client.GetObject(r.ctx, &s3.GetObjectInput{
+		Bucket: aws.String(r.bucketName),
+		Key:    aws.String(r.key),
+		Range:  aws.String(fmt.Sprintf("bytes=%d-%d", offset, offset+length-1)),
+	})
+	if err != nil {
+		return nil, translateAWSError(err, "object", r.key, "failed to read object range")
+	}
+	defer func() {
+		if err := output.Body.Close(); err != nil {
+			logger.FromContext(r.ctx).Warn("Error closing response body", "key", r.key, "error", err)
+		}
+	}()
+	return io.ReadAll(output.Body)
+}
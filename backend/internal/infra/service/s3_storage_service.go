@@ -1,41 +1,49 @@
 package service
 
 import (
+	"cmTranscribe/internal/domain/domainerr"
 	"cmTranscribe/internal/domain/model"
-	appConfig "cmTranscribe/internal/infra/config"
+	"cmTranscribe/internal/shared/logger"
 	"context"
+	"errors"
 	"fmt"
 	"github.com/aws/aws-sdk-go-v2/aws"
-	"github.com/aws/aws-sdk-go-v2/config"
 	"github.com/aws/aws-sdk-go-v2/feature/s3/manager"
 	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/aws/smithy-go"
 	"io"
 	"net/http"
+	"net/url"
 	"os"
 	"path/filepath"
+	"strings"
 	"time"
 )
 
 // S3StorageService は AWS S3 とのやり取りを行う具体的な実装です
 type S3StorageService struct {
-	s3Client      *s3.Client
-	presignClient *s3.PresignClient
+	s3Client            *s3.Client
+	presignClient       *s3.PresignClient
+	uploadPresignClient *s3.PresignClient // ブラウザに渡すアップロード用の署名付きURLを生成する
 }
 
 // NewS3StorageService は S3StorageService のインスタンスを生成します
-func NewS3StorageService(ctx context.Context, region string) (*S3StorageService, error) {
-	cfg, err := config.LoadDefaultConfig(ctx, config.WithRegion(region))
-	if err != nil {
-		return nil, fmt.Errorf("failed to load AWS config: %v", err)
-	}
-
-	s3Client := s3.NewFromConfig(cfg)
+func NewS3StorageService(cfg aws.Config, settings AWSSettings) *S3StorageService {
+	s3Client := newS3Client(cfg, settings, settings.S3EndpointURL)
 	presignClient := s3.NewPresignClient(s3Client)
 
+	// コンテナ内のエンドポイントにブラウザから接続できない場合は、公開用のエンドポイントで署名する
+	uploadPresignClient := presignClient
+	if settings.S3PublicURL != "" {
+		uploadPresignClient = s3.NewPresignClient(newS3Client(cfg, settings, settings.S3PublicURL))
+	}
+
 	return &S3StorageService{
-		s3Client:      s3Client,
-		presignClient: presignClient,
-	}, nil
+		s3Client:            s3Client,
+		presignClient:       presignClient,
+		uploadPresignClient: uploadPresignClient,
+	}
 }
 
 // UploadToS3 はファイルを S3 にアップロードします
@@ -43,11 +51,11 @@ func (s *S3StorageService) UploadToS3(ctx context.Context, s3File model.S3File)
 	// ファイルを開く
 	file, err := os.Open(s3File.FilePath)
 	if err != nil {
-		return "", fmt.Errorf("failed to open file for S3 upload: %v", err)
+		return "", fmt.Errorf("failed to open file for S3 upload: %w", err)
 	}
 	defer func() {
 		if err := file.Close(); err != nil {
-			fmt.Printf("Error closing file: %v\n", err)
+			logger.FromContext(ctx).Warn("Error closing file", "path", s3File.FilePath, "error", err)
 		}
 	}()
 
@@ -59,60 +67,318 @@ func (s *S3StorageService) UploadToS3(ctx context.Context, s3File model.S3File)
 
 	// ファイルをS3にアップロード
 	_, err = uploader.Upload(ctx, &s3.PutObjectInput{
-		Bucket: aws.String(s3File.BucketName),
-		Key:    aws.String(key),
-		Body:   file,
+		Bucket:   aws.String(s3File.BucketName),
+		Key:      aws.String(key),
+		Body:     file,
+		Metadata: s3File.Metadata,
 	})
 	if err != nil {
-		return "", fmt.Errorf("failed to upload file to S3: %v", err)
+		return "", translateAWSError(err, "object", key, "failed to upload file to S3")
 	}
 
 	return fmt.Sprintf("s3://%s/%s", s3File.BucketName, key), nil
 }
 
-// GeneratePresignedURL は、transcriptFileUri から署名付きURLを生成します
-func (s *S3StorageService) GeneratePresignedURL(ctx context.Context, jobName string) (string, error) {
-	// 環境変数からバケット名を取得
-	bucketName := appConfig.AppConfig.S3BucketName
-	key := fmt.Sprintf("%s.json", jobName)
+// PutObject はストリームの内容をそのまま S3 にアップロードし、s3:// 形式のURIを返します
+func (s *S3StorageService) PutObject(ctx context.Context, object model.S3Object) (string, error) {
+	input := &s3.PutObjectInput{
+		Bucket:   aws.String(object.BucketName),
+		Key:      aws.String(object.Key),
+		Body:     object.Body,
+		Metadata: object.Metadata,
+	}
+	if object.ContentType != "" {
+		input.ContentType = aws.String(object.ContentType)
+	}
 
-	// presignClient を使って署名付きURLを生成
-	req, err := s.presignClient.PresignGetObject(ctx, &s3.GetObjectInput{
+	// サイズの分からないストリームでもアップロードできるようUploaderを使う
+	uploader := manager.NewUploader(s.s3Client)
+	if _, err := uploader.Upload(ctx, input); err != nil {
+		return "", translateAWSError(err, "object", object.Key, "failed to upload object to S3")
+	}
+
+	return fmt.Sprintf("s3://%s/%s", object.BucketName, object.Key), nil
+}
+
+// PresignUpload は、ファイルを直接 S3 にアップロードするための署名付きURL (PUT) またはフォームフィールド (POST) を生成します
+func (s *S3StorageService) PresignUpload(ctx context.Context, bucketName string, upload model.UploadRequest, expires time.Duration) (*model.PresignedUpload, error) {
+	presigned := &model.PresignedUpload{
+		Method:    upload.Method,
+		Key:       upload.Key,
+		ExpiresAt: time.Now().Add(expires),
+	}
+
+	if upload.Method == model.UploadMethodPost {
+		// サイズとContent-Typeをポリシーで制約する
+		maxBytes := upload.MaxBytes
+		if upload.Size > 0 {
+			maxBytes = upload.Size
+		}
+		conditions := []interface{}{
+			[]interface{}{"content-length-range", 1, maxBytes},
+			[]interface{}{"eq", "$Content-Type", upload.ContentType},
+		}
+		metadata := model.OwnerMetadata(upload.Owner)
+		for name, value := range metadata {
+			conditions = append(conditions, []interface{}{"eq", "$x-amz-meta-" + name, value})
+		}
+		req, err := s.uploadPresignClient.PresignPostObject(ctx, &s3.PutObjectInput{
+			Bucket: aws.String(bucketName),
+			Key:    aws.String(upload.Key),
+		}, func(options *s3.PresignPostOptions) {
+			options.Expires = expires
+			options.Conditions = conditions
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate presigned POST: %w", err)
+		}
+		presigned.URL = req.URL
+		presigned.Fields = req.Values
+		presigned.Fields["Content-Type"] = upload.ContentType
+		for name, value := range metadata {
+			presigned.Fields["x-amz-meta-"+name] = value
+		}
+		return presigned, nil
+	}
+
+	// PUTの場合はContent-Type、Content-Lengthとメタデータを署名に含める
+	req, err := s.uploadPresignClient.PresignPutObject(ctx, &s3.PutObjectInput{
+		Bucket:        aws.String(bucketName),
+		Key:           aws.String(upload.Key),
+		ContentType:   aws.String(upload.ContentType),
+		ContentLength: aws.Int64(upload.Size),
+		Metadata:      model.OwnerMetadata(upload.Owner),
+	}, s3.WithPresignExpires(expires))
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate presigned PUT URL: %w", err)
+	}
+	presigned.URL = req.URL
+	presigned.Headers = make(map[string]string)
+	for name, values := range req.SignedHeader {
+		if strings.EqualFold(name, "Host") || len(values) == 0 {
+			continue
+		}
+		presigned.Headers[name] = values[0]
+	}
+	return presigned, nil
+}
+
+// HeadObject は、S3 オブジェクトのメタデータを取得します
+func (s *S3StorageService) HeadObject(ctx context.Context, bucketName, key string) (*model.S3ObjectInfo, error) {
+	output, err := s.s3Client.HeadObject(ctx, &s3.HeadObjectInput{
 		Bucket: aws.String(bucketName),
 		Key:    aws.String(key),
-	}, s3.WithPresignExpires(15*time.Minute)) // 15分間有効
+	})
+	if err != nil {
+		return nil, translateAWSError(err, "object", key, "failed to get object metadata")
+	}
 
+	return &model.S3ObjectInfo{
+		BucketName:   bucketName,
+		Key:          key,
+		Size:         aws.ToInt64(output.ContentLength),
+		ContentType:  aws.ToString(output.ContentType),
+		LastModified: aws.ToTime(output.LastModified),
+		Metadata:     output.Metadata,
+	}, nil
+}
+
+// OpenObjectReader は、S3 オブジェクトをダウンロードせずに必要な範囲だけ読み込むための io.ReaderAt とサイズを返します
+func (s *S3StorageService) OpenObjectReader(ctx context.Context, bucketName, key string) (io.ReaderAt, int64, error) {
+	object, err := s.HeadObject(ctx, bucketName, key)
 	if err != nil {
-		return "", fmt.Errorf("failed to generate presigned URL: %v", err)
+		return nil, 0, err
+	}
+	return &s3ObjectReader{
+		ctx:        ctx,
+		client:     s.s3Client,
+		bucketName: bucketName,
+		key:        key,
+		size:       object.Size,
+	}, object.Size, nil
+}
+
+// ListObjects は、プレフィックスの下のオブジェクトを1ページ分取得します
+func (s *S3StorageService) ListObjects(ctx context.Context, bucketName, prefix, continuationToken string, maxKeys int) (*model.S3ObjectPage, error) {
+	input := &s3.ListObjectsV2Input{
+		Bucket:  aws.String(bucketName),
+		MaxKeys: aws.Int32(int32(maxKeys)),
+	}
+	if prefix != "" {
+		input.Prefix = aws.String(prefix)
+	}
+	if continuationToken != "" {
+		input.ContinuationToken = aws.String(continuationToken)
 	}
 
+	output, err := s.s3Client.ListObjectsV2(ctx, input)
+	if err != nil {
+		return nil, translateAWSError(err, "object", prefix, "failed to list objects")
+	}
+
+	page := &model.S3ObjectPage{}
+	for _, object := range output.Contents {
+		page.Objects = append(page.Objects, &model.S3ObjectInfo{
+			BucketName:   bucketName,
+			Key:          aws.ToString(object.Key),
+			Size:         aws.ToInt64(object.Size),
+			LastModified: aws.ToTime(object.LastModified),
+		})
+	}
+	if aws.ToBool(output.IsTruncated) {
+		page.NextContinuationToken = aws.ToString(output.NextContinuationToken)
+	}
+	return page, nil
+}
+
+// CopyObject は、同じバケット内でオブジェクトをコピーします (model.MaxCopyObjectSize を超えるオブジェクトには対応していません)
+func (s *S3StorageService) CopyObject(ctx context.Context, bucketName, sourceKey, destinationKey string) error {
+	_, err := s.s3Client.CopyObject(ctx, &s3.CopyObjectInput{
+		Bucket:     aws.String(bucketName),
+		Key:        aws.String(destinationKey),
+		CopySource: aws.String(url.PathEscape(bucketName + "/" + sourceKey)),
+	})
+	if err != nil {
+		return translateAWSError(err, "object", sourceKey, "failed to copy object")
+	}
+	return nil
+}
+
+// DeleteObject は、オブジェクトを削除します
+func (s *S3StorageService) DeleteObject(ctx context.Context, bucketName, key string) error {
+	_, err := s.s3Client.DeleteObject(ctx, &s3.DeleteObjectInput{
+		Bucket: aws.String(bucketName),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		return translateAWSError(err, "object", key, "failed to delete object")
+	}
+	return nil
+}
+
+// CreateMultipartUpload は、マルチパートアップロードを開始してアップロードIDを返します
+func (s *S3StorageService) CreateMultipartUpload(ctx context.Context, bucketName, key, contentType string, metadata map[string]string) (string, error) {
+	output, err := s.s3Client.CreateMultipartUpload(ctx, &s3.CreateMultipartUploadInput{
+		Bucket:      aws.String(bucketName),
+		Key:         aws.String(key),
+		ContentType: aws.String(contentType),
+		Metadata:    metadata,
+		// パートごとに送るSHA-256をS3に検証させる
+		ChecksumAlgorithm: types.ChecksumAlgorithmSha256,
+	})
+	if err != nil {
+		return "", translateAWSError(err, "object", key, "failed to create multipart upload")
+	}
+	return aws.ToString(output.UploadId), nil
+}
+
+// UploadPart は、マルチパートアップロードのパートを1件アップロードしてETagを返します。
+// パートはメモリに溜めずにそのまま送り、サイズとSHA-256はS3に検証させます
+func (s *S3StorageService) UploadPart(ctx context.Context, session model.UploadSession, part model.UploadPart, body io.Reader) (string, error) {
+	output, err := s.s3Client.UploadPart(ctx, &s3.UploadPartInput{
+		Bucket:         aws.String(session.BucketName),
+		Key:            aws.String(session.Key),
+		UploadId:       aws.String(session.S3UploadID),
+		PartNumber:     aws.Int32(int32(part.Number)),
+		ContentLength:  aws.Int64(part.Size),
+		ChecksumSHA256: aws.String(model.ChecksumBase64(part.Checksum)),
+		Body:           body,
+	})
+	if err != nil {
+		var apiErr smithy.APIError
+		if errors.As(err, &apiErr) && apiErr.ErrorCode() == "BadDigest" {
+			return "", domainerr.Wrap(domainerr.Unprocessable, "checksum_mismatch", err, "checksum mismatch for part %d", part.Number)
+		}
+		return "", translateAWSError(err, "upload", session.ID, fmt.Sprintf("failed to upload part %d", part.Number))
+	}
+	return aws.ToString(output.ETag), nil
+}
+
+// CompleteMultipartUpload は、受け取り済みのパートを結合してオブジェクトを作成し、s3:// 形式のURIを返します
+func (s *S3StorageService) CompleteMultipartUpload(ctx context.Context, session model.UploadSession) (string, error) {
+	var parts []types.CompletedPart
+	for _, part := range session.SortedParts() {
+		parts = append(parts, types.CompletedPart{
+			ETag:           aws.String(part.ETag),
+			PartNumber:     aws.Int32(int32(part.Number)),
+			ChecksumSHA256: aws.String(model.ChecksumBase64(part.Checksum)),
+		})
+	}
+
+	_, err := s.s3Client.CompleteMultipartUpload(ctx, &s3.CompleteMultipartUploadInput{
+		Bucket:          aws.String(session.BucketName),
+		Key:             aws.String(session.Key),
+		UploadId:        aws.String(session.S3UploadID),
+		MultipartUpload: &types.CompletedMultipartUpload{Parts: parts},
+	})
+	if err != nil {
+		return "", translateAWSError(err, "upload", session.ID, "failed to complete multipart upload")
+	}
+	return fmt.Sprintf("s3://%s/%s", session.BucketName, session.Key), nil
+}
+
+// AbortMultipartUpload は、マルチパートアップロードを中止してアップロード済みのパートを破棄します
+func (s *S3StorageService) AbortMultipartUpload(ctx context.Context, session model.UploadSession) error {
+	_, err := s.s3Client.AbortMultipartUpload(ctx, &s3.AbortMultipartUploadInput{
+		Bucket:   aws.String(session.BucketName),
+		Key:      aws.String(session.Key),
+		UploadId: aws.String(session.S3UploadID),
+	})
+	if err != nil {
+		return translateAWSError(err, "upload", session.ID, "failed to abort multipart upload")
+	}
+	return nil
+}
+
+// PresignDownload は、オブジェクトをダウンロードするための署名付きURLを生成します
+func (s *S3StorageService) PresignDownload(ctx context.Context, bucketName, key string, expires time.Duration) (string, error) {
+	req, err := s.presignClient.PresignGetObject(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(bucketName),
+		Key:    aws.String(key),
+	}, s3.WithPresignExpires(expires))
+	if err != nil {
+		return "", fmt.Errorf("failed to generate presigned URL: %w", err)
+	}
 	return req.URL, nil
 }
 
+// GeneratePresignedURL は、バケットの key に出力されたジョブの文字起こし結果を取得するための署名付きURLを生成します
+func (s *S3StorageService) GeneratePresignedURL(ctx context.Context, bucketName, key string) (string, error) {
+	return s.PresignDownload(ctx, bucketName, key, 15*time.Minute) // 15分間有効
+}
+
 // GetTranscriptionContent は、署名付きURLから文字起こしデータを取得する関数です
 func (s *S3StorageService) GetTranscriptionContent(ctx context.Context, signedURL string) (string, error) {
 	// HTTP GET リクエストで署名付きURLにアクセス
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, signedURL, nil)
 	if err != nil {
-		return "", fmt.Errorf("failed to create request: %v", err)
+		return "", fmt.Errorf("failed to create request: %w", err)
 	}
 
 	// HTTPクライアントでリクエストを送信
 	client := &http.Client{}
 	resp, err := client.Do(req)
 	if err != nil {
-		return "", fmt.Errorf("failed to execute request: %v", err)
+		return "", domainerr.Wrap(domainerr.Upstream, "upstream_unavailable", err, "failed to download transcription content")
 	}
 	defer func() {
 		if err := resp.Body.Close(); err != nil {
-			fmt.Printf("Error closing response body: %v\n", err)
+			logger.FromContext(ctx).Warn("Error closing response body", "error", err)
 		}
 	}()
 
+	// 文字起こし結果が出力されていない場合は NotFound として扱う
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		return "", domainerr.New(domainerr.NotFound, "transcript_not_found", "transcription content does not exist")
+	case resp.StatusCode != http.StatusOK:
+		return "", domainerr.New(domainerr.Upstream, "upstream_error", "failed to download transcription content: %s", resp.Status)
+	}
+
 	// レスポンスボディを読み込む
 	content, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return "", fmt.Errorf("failed to read response body: %v", err)
+		return "", fmt.Errorf("failed to read response body: %w", err)
 	}
 
 	return string(content), nil
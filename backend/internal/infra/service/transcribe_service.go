@@ -4,14 +4,9 @@ import (
 	"cmTranscribe/internal/domain/model"
 	appConfig "cmTranscribe/internal/infra/config"
 	"context"
-	"errors"
-	"fmt"
 	"github.com/aws/aws-sdk-go-v2/aws"
-	"github.com/aws/aws-sdk-go-v2/config"
 	"github.com/aws/aws-sdk-go-v2/service/transcribe"
 	"github.com/aws/aws-sdk-go-v2/service/transcribe/types"
-	"github.com/aws/smithy-go"
-	"log"
 )
 
 // TranscribeService Amazon Transcribeの操作を行うサービスです。
@@ -20,19 +15,10 @@ type TranscribeService struct {
 }
 
 // NewTranscribeService ファクトリ関数
-func NewTranscribeService(ctx context.Context, region string) (*TranscribeService, error) {
-	// AWS Configを取得
-	cfg, err := config.LoadDefaultConfig(ctx, config.WithRegion(region))
-	if err != nil {
-		return nil, fmt.Errorf("failed to load AWS config: %v", err)
-	}
-
-	// Transcribe クライアントを作成
-	client := transcribe.NewFromConfig(cfg)
-
+func NewTranscribeService(cfg aws.Config, settings AWSSettings) *TranscribeService {
 	return &TranscribeService{
-		client: client,
-	}, nil
+		client: newTranscribeClient(cfg, settings),
+	}
 }
 
 // StartTranscriptionJob インターフェースの実装
@@ -47,24 +33,33 @@ func (t *TranscribeService) StartTranscriptionJob(ctx context.Context, input *mo
 		},
 		OutputBucketName: aws.String(bucketName),
 	}
+	if input.OutputKey != "" {
+		transcriptionInput.OutputKey = aws.String(input.OutputKey)
+	}
+	if input.MediaFormat != "" {
+		transcriptionInput.MediaFormat = types.MediaFormat(input.MediaFormat)
+	}
+	if input.MediaSampleRateHertz > 0 {
+		transcriptionInput.MediaSampleRateHertz = aws.Int32(int32(input.MediaSampleRateHertz))
+	}
 
-	// カスタムボキャブラリの指定がある場合
+	// カスタムボキャブラリ・語彙フィルタの指定がある場合
+	if input.CustomVocabularyName != "" || input.VocabularyFilterName != "" {
+		transcriptionInput.Settings = &types.Settings{}
+	}
 	if input.CustomVocabularyName != "" {
-		transcriptionInput.Settings = &types.Settings{
-			VocabularyName: aws.String(input.CustomVocabularyName),
-		}
+		transcriptionInput.Settings.VocabularyName = aws.String(input.CustomVocabularyName)
+	}
+	if input.VocabularyFilterName != "" {
+		transcriptionInput.Settings.VocabularyFilterName = aws.String(input.VocabularyFilterName)
+		transcriptionInput.Settings.VocabularyFilterMethod = types.VocabularyFilterMethod(input.VocabularyFilterMethod)
 	}
 
 	// Transcriptionジョブを開始
 	result, err := t.client.StartTranscriptionJob(ctx, transcriptionInput)
 	if err != nil {
-		var awsErr smithy.APIError
-		if errors.As(err, &awsErr) && awsErr.ErrorCode() == "ConflictException" {
-			// フロントエンドに重複エラーを知らせる
-			return nil, fmt.Errorf("conflict: job name already exists")
-		}
-		fmt.Printf("Error starting transcription job: %v\n", err)
-		return nil, fmt.Errorf("failed to start transcription job: %v", err)
+		// 重複したジョブ名は Conflict としてフロントエンドに知らせる
+		return nil, translateAWSError(err, "transcription job", input.JobName, "failed to start transcription job")
 	}
 
 	// TranscriptionJobStatusResponse を作成して返す
@@ -84,38 +79,60 @@ func (t *TranscribeService) GetTranscriptionJob(ctx context.Context, jobName str
 	// Call AWS Transcribes GetTranscriptionJob API.
 	output, err := t.client.GetTranscriptionJob(ctx, input)
 	if err != nil {
-		// Handle API errors with logging and appropriate error messages.
-		var apiErr smithy.APIError
-		if errors.As(err, &apiErr) {
-			log.Printf("Failed to get transcription job: %s (code: %s)", apiErr.ErrorMessage(), apiErr.ErrorCode())
-		} else {
-			log.Printf("Failed to get transcription job: %v", err)
-		}
-		return nil, fmt.Errorf("failed to get transcription job: %v", err)
+		// The failed call itself is logged by the AWS call logging middleware.
+		return nil, translateAWSError(err, "transcription job", jobName, "failed to get transcription job")
 	}
 
 	// Convert the AWS response to a domain model using a factory method.
 	return model.NewTranscriptionJobResponse(output.TranscriptionJob), nil
 }
 
-// GetTranscriptionJobList retrieves the list of transcription jobs from AWS Transcribe.
-func (t *TranscribeService) GetTranscriptionJobList(ctx context.Context) (*model.TranscriptionJobSummariesResponse, error) {
-	// Create the request input for listing transcription jobs.
-	input := &transcribe.ListTranscriptionJobsInput{}
+// GetTranscriptionJobList retrieves one page of the transcription jobs matching the query from AWS Transcribe.
+func (t *TranscribeService) GetTranscriptionJobList(ctx context.Context, query model.TranscriptionJobListQuery) (*model.TranscriptionJobSummariesResponse, error) {
+	// Create the request input for listing transcription jobs. Filter by name on the Transcribe side
+	// so that a tenant does not page through the jobs of the other tenants.
+	input := &transcribe.ListTranscriptionJobsInput{
+		MaxResults: aws.Int32(int32(query.MaxResults)),
+	}
+	if query.MaxResults <= 0 || query.MaxResults > model.MaxTranscriptionJobPageSize {
+		input.MaxResults = aws.Int32(model.MaxTranscriptionJobPageSize)
+	}
+	if query.NameContains != "" {
+		input.JobNameContains = aws.String(query.NameContains)
+	}
+	if query.Status != "" {
+		input.Status = types.TranscriptionJobStatus(query.Status)
+	}
+	if query.NextToken != "" {
+		input.NextToken = aws.String(query.NextToken)
+	}
 
-	// Call AWS Transcribes ListTranscriptionJobs API.
+	// Call AWS Transcribes ListTranscriptionJobs API for a single page.
 	output, err := t.client.ListTranscriptionJobs(ctx, input)
 	if err != nil {
-		// Handle errors by logging and returning them.
-		var apiErr smithy.APIError
-		if errors.As(err, &apiErr) {
-			log.Printf("Failed to list transcription jobs: %s (code: %s)", apiErr.ErrorMessage(), apiErr.ErrorCode())
-		} else {
-			log.Printf("Failed to list transcription jobs: %v", err)
-		}
-		return nil, fmt.Errorf("failed to list transcription jobs: %v", err)
+		// Translate the error; the failed call itself is logged by the AWS call logging middleware.
+		return nil, translateAWSError(err, "transcription job", "", "failed to list transcription jobs")
 	}
 
 	// Use the factory method to convert the AWS response to the domain model.
-	return model.NewTranscriptionJobSummariesResponse(output.TranscriptionJobSummaries), nil
+	return model.NewTranscriptionJobSummariesResponse(output.TranscriptionJobSummaries, aws.ToString(output.NextToken)), nil
+}
+
+// CountActiveTranscriptionJobs counts the QUEUED and IN_PROGRESS transcription jobs of the account.
+func (t *TranscribeService) CountActiveTranscriptionJobs(ctx context.Context) (int, error) {
+	count := 0
+	for _, status := range []types.TranscriptionJobStatus{types.TranscriptionJobStatusQueued, types.TranscriptionJobStatusInProgress} {
+		paginator := transcribe.NewListTranscriptionJobsPaginator(t.client, &transcribe.ListTranscriptionJobsInput{
+			Status:     status,
+			MaxResults: aws.Int32(100),
+		})
+		for paginator.HasMorePages() {
+			output, err := paginator.NextPage(ctx)
+			if err != nil {
+				return 0, translateAWSError(err, "transcription job", "", "failed to count active transcription jobs")
+			}
+			count += len(output.TranscriptionJobSummaries)
+		}
+	}
+	return count, nil
 }
@@ -0,0 +1,100 @@
+package service
+
+import (
+	"cmTranscribe/internal/domain/model"
+	"context"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/transcribe"
+	"github.com/aws/aws-sdk-go-v2/service/transcribe/types"
+)
+
+// VocabularyFilterService Amazon Transcribeの語彙フィルタの操作を行うサービスです。
+type VocabularyFilterService struct {
+	client *transcribe.Client
+}
+
+// NewVocabularyFilterService ファクトリ関数
+func NewVocabularyFilterService(cfg aws.Config, settings AWSSettings) *VocabularyFilterService {
+	return &VocabularyFilterService{
+		client: newTranscribeClient(cfg, settings),
+	}
+}
+
+// CreateVocabularyFilter 語彙フィルタを作成します
+func (s *VocabularyFilterService) CreateVocabularyFilter(ctx context.Context, filter model.VocabularyFilter) error {
+	input := &transcribe.CreateVocabularyFilterInput{
+		LanguageCode:            types.LanguageCode(filter.LanguageCode),
+		VocabularyFilterName:    aws.String(filter.FilterName),
+		VocabularyFilterFileUri: aws.String(filter.FileUri),
+	}
+
+	_, err := s.client.CreateVocabularyFilter(ctx, input)
+	if err != nil {
+		return translateAWSError(err, "vocabulary filter", filter.FilterName, "failed to create vocabulary filter")
+	}
+	return nil
+}
+
+// UpdateVocabularyFilter 語彙フィルタの単語リストを置き換えます
+func (s *VocabularyFilterService) UpdateVocabularyFilter(ctx context.Context, filter model.VocabularyFilter) error {
+	input := &transcribe.UpdateVocabularyFilterInput{
+		VocabularyFilterName:    aws.String(filter.FilterName),
+		VocabularyFilterFileUri: aws.String(filter.FileUri),
+	}
+
+	_, err := s.client.UpdateVocabularyFilter(ctx, input)
+	if err != nil {
+		return translateAWSError(err, "vocabulary filter", filter.FilterName, "failed to update vocabulary filter")
+	}
+	return nil
+}
+
+// GetVocabularyFilter 名前で語彙フィルタを取得します
+func (s *VocabularyFilterService) GetVocabularyFilter(ctx context.Context, name string) (*model.VocabularyFilterResponse, error) {
+	result, err := s.client.GetVocabularyFilter(ctx, &transcribe.GetVocabularyFilterInput{
+		VocabularyFilterName: aws.String(name),
+	})
+	if err != nil {
+		return nil, translateAWSError(err, "vocabulary filter", name, "failed to get vocabulary filter")
+	}
+
+	return &model.VocabularyFilterResponse{
+		FilterName:       aws.ToString(result.VocabularyFilterName),
+		LanguageCode:     string(result.LanguageCode),
+		DownloadUri:      aws.ToString(result.DownloadUri),
+		LastModifiedTime: aws.ToTime(result.LastModifiedTime),
+	}, nil
+}
+
+// ListVocabularyFilters 語彙フィルタの一覧を取得します
+func (s *VocabularyFilterService) ListVocabularyFilters(ctx context.Context) ([]*model.VocabularyFilterResponse, error) {
+	var filters []*model.VocabularyFilterResponse
+	paginator := transcribe.NewListVocabularyFiltersPaginator(s.client, &transcribe.ListVocabularyFiltersInput{
+		MaxResults: aws.Int32(100),
+	})
+	for paginator.HasMorePages() {
+		output, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, translateAWSError(err, "vocabulary filter", "", "failed to list vocabulary filters")
+		}
+		for _, filter := range output.VocabularyFilters {
+			filters = append(filters, &model.VocabularyFilterResponse{
+				FilterName:       aws.ToString(filter.VocabularyFilterName),
+				LanguageCode:     string(filter.LanguageCode),
+				LastModifiedTime: aws.ToTime(filter.LastModifiedTime),
+			})
+		}
+	}
+	return filters, nil
+}
+
+// DeleteVocabularyFilter 語彙フィルタを削除します
+func (s *VocabularyFilterService) DeleteVocabularyFilter(ctx context.Context, name string) error {
+	_, err := s.client.DeleteVocabularyFilter(ctx, &transcribe.DeleteVocabularyFilterInput{
+		VocabularyFilterName: aws.String(name),
+	})
+	if err != nil {
+		return translateAWSError(err, "vocabulary filter", name, "failed to delete vocabulary filter")
+	}
+	return nil
+}
@@ -3,12 +3,13 @@ package api
 import (
 	"cmTranscribe/internal/app/dto"
 	"cmTranscribe/internal/app/service"
+	"cmTranscribe/internal/domain/model"
+	"cmTranscribe/internal/shared/metrics"
 	"cmTranscribe/internal/shared/utils"
 	"encoding/json"
 	"fmt"
-	"log"
 	"net/http"
-	"strings"
+	"strconv"
 )
 
 // CustomVocabularyHandler ハンドラ構造体
@@ -28,6 +29,8 @@ func (h *CustomVocabularyHandler) HandleVocabulary(w http.ResponseWriter, r *htt
 		h.HandleCreateVocabulary(w, r)
 	case http.MethodPut:
 		h.HandleUpdateVocabulary(w, r)
+	case http.MethodPatch:
+		h.HandlePatchVocabulary(w, r)
 	case http.MethodGet:
 		h.HandleGetVocabularyByName(w, r)
 	default:
@@ -44,14 +47,9 @@ func (h *CustomVocabularyHandler) HandleCreateVocabulary(w http.ResponseWriter,
 
 	// DTOをサービスに渡して処理
 	err := h.Service.CreateCustomVocabulary(r.Context(), req)
+	metrics.VocabularyOperations.Inc(metrics.ResourceCustomVocabulary, "create", metrics.Result(err))
 	if err != nil {
-		log.Println("err.Error():", err.Error())
-		log.Println("err:", err)
-		if strings.Contains(err.Error(), "conflict: custom vocabulary name already exists") {
-			utils.RespondWithError(w, http.StatusConflict, err.Error())
-			return
-		}
-		utils.RespondWithError(w, http.StatusInternalServerError, "Failed to create custom vocabulary")
+		utils.RespondWithDomainError(w, r, err, "Failed to create custom vocabulary")
 		return
 	}
 
@@ -72,12 +70,9 @@ func (h *CustomVocabularyHandler) HandleUpdateVocabulary(w http.ResponseWriter,
 
 	// DTOをサービスに渡して処理
 	err := h.Service.UpdateCustomVocabulary(r.Context(), req)
+	metrics.VocabularyOperations.Inc(metrics.ResourceCustomVocabulary, "update", metrics.Result(err))
 	if err != nil {
-		if strings.Contains(err.Error(), "conflict: custom vocabulary name already exists") {
-			utils.RespondWithError(w, http.StatusConflict, err.Error())
-			return
-		}
-		utils.RespondWithError(w, http.StatusInternalServerError, "Failed to update custom vocabulary")
+		utils.RespondWithDomainError(w, r, err, "Failed to update custom vocabulary")
 		return
 	}
 
@@ -88,6 +83,31 @@ func (h *CustomVocabularyHandler) HandleUpdateVocabulary(w http.ResponseWriter,
 	}
 }
 
+// HandlePatchVocabulary カスタムボキャブラリの語彙を部分更新します。
+func (h *CustomVocabularyHandler) HandlePatchVocabulary(w http.ResponseWriter, r *http.Request) {
+	var req dto.PatchVocabularyDto
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, "Failed to parse JSON")
+		return
+	}
+	if req.VocabularyName == "" {
+		utils.RespondWithError(w, http.StatusBadRequest, "Missing vocabulary name")
+		return
+	}
+
+	req.IfMatch = r.Header.Get("If-Match")
+
+	version, err := h.Service.PatchCustomVocabulary(r.Context(), req)
+	metrics.VocabularyOperations.Inc(metrics.ResourceCustomVocabulary, "patch", metrics.Result(err))
+	if err != nil {
+		utils.RespondWithDomainError(w, r, err, "Failed to patch custom vocabulary")
+		return
+	}
+
+	w.Header().Set("ETag", model.VocabularyETag(version))
+	utils.RespondWithJSON(w, http.StatusOK, map[string]string{"message": "Custom vocabulary patched successfully"})
+}
+
 // HandleGetVocabularyByName カスタムボキャブラリの内容を取得します。
 func (h *CustomVocabularyHandler) HandleGetVocabularyByName(w http.ResponseWriter, r *http.Request) {
 	// クエリパラメータからカスタムボキャブラリーの名前を取得
@@ -100,7 +120,7 @@ func (h *CustomVocabularyHandler) HandleGetVocabularyByName(w http.ResponseWrite
 	// サービスを使ってカスタムボキャブラリーの内容を取得
 	vocabulary, err := h.Service.GetCustomVocabularyByName(r.Context(), vocabularyName)
 	if err != nil {
-		utils.RespondWithError(w, http.StatusInternalServerError, fmt.Sprintf("Error retrieving vocabulary: %v", err))
+		utils.RespondWithDomainError(w, r, err, "Failed to get custom vocabulary")
 		return
 	}
 
@@ -111,6 +131,7 @@ func (h *CustomVocabularyHandler) HandleGetVocabularyByName(w http.ResponseWrite
 		return
 	}
 	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("ETag", model.VocabularyETag(vocabulary.Version))
 	w.WriteHeader(http.StatusOK)
 	_, err = w.Write(response)
 	if err != nil {
@@ -118,3 +139,87 @@ func (h *CustomVocabularyHandler) HandleGetVocabularyByName(w http.ResponseWrite
 		return
 	}
 }
+
+// HandleGetVocabularyVersions カスタムボキャブラリのバージョン履歴を取得します。
+func (h *CustomVocabularyHandler) HandleGetVocabularyVersions(w http.ResponseWriter, r *http.Request) {
+	vocabularyName := r.URL.Query().Get("name")
+	if vocabularyName == "" {
+		utils.RespondWithError(w, http.StatusBadRequest, "Missing vocabulary name")
+		return
+	}
+
+	versions, err := h.Service.GetVocabularyVersions(r.Context(), vocabularyName)
+	if err != nil {
+		utils.RespondWithDomainError(w, r, err, "Failed to get vocabulary versions")
+		return
+	}
+
+	utils.RespondWithJSON(w, http.StatusOK, versions)
+}
+
+// HandleDiffVocabularyVersions 2つのバージョン間の語彙の差分を取得します。
+func (h *CustomVocabularyHandler) HandleDiffVocabularyVersions(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	vocabularyName := query.Get("name")
+	if vocabularyName == "" {
+		utils.RespondWithError(w, http.StatusBadRequest, "Missing vocabulary name")
+		return
+	}
+	from, err := strconv.Atoi(query.Get("from"))
+	if err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, "from must be a version number")
+		return
+	}
+	to, err := strconv.Atoi(query.Get("to"))
+	if err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, "to must be a version number")
+		return
+	}
+
+	diff, err := h.Service.DiffVocabularyVersions(r.Context(), vocabularyName, from, to)
+	if err != nil {
+		utils.RespondWithDomainError(w, r, err, "Failed to diff vocabulary versions")
+		return
+	}
+
+	utils.RespondWithJSON(w, http.StatusOK, diff)
+}
+
+// HandleRollbackVocabulary カスタムボキャブラリを過去のバージョンに戻します。
+func (h *CustomVocabularyHandler) HandleRollbackVocabulary(w http.ResponseWriter, r *http.Request) {
+	var req dto.RollbackVocabularyDto
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, "Failed to parse JSON")
+		return
+	}
+	if req.VocabularyName == "" || req.Version <= 0 {
+		utils.RespondWithError(w, http.StatusBadRequest, "name and version are required")
+		return
+	}
+
+	err := h.Service.RollbackCustomVocabulary(r.Context(), req)
+	metrics.VocabularyOperations.Inc(metrics.ResourceCustomVocabulary, "rollback", metrics.Result(err))
+	if err != nil {
+		utils.RespondWithDomainError(w, r, err, "Failed to roll back custom vocabulary")
+		return
+	}
+
+	utils.RespondWithJSON(w, http.StatusOK, map[string]string{"message": "Custom vocabulary rolled back successfully"})
+}
+
+// HandleSuggestReadings Phraseごとに読み（SoundsLike）の候補を返します。
+func (h *CustomVocabularyHandler) HandleSuggestReadings(w http.ResponseWriter, r *http.Request) {
+	var req dto.SuggestReadingsDto
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, "Failed to parse JSON")
+		return
+	}
+
+	readings, err := h.Service.SuggestReadings(r.Context(), req)
+	if err != nil {
+		utils.RespondWithDomainError(w, r, err, "Failed to suggest readings")
+		return
+	}
+
+	utils.RespondWithJSON(w, http.StatusOK, readings)
+}
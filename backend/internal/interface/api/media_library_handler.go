@@ -0,0 +1,78 @@
+package api
+
+import (
+	"cmTranscribe/internal/app/dto"
+	"cmTranscribe/internal/app/service"
+	"cmTranscribe/internal/shared/utils"
+	"encoding/json"
+	"net/http"
+	"strconv"
+)
+
+// MediaLibraryHandler アップロード済みのメディアファイルに関するAPIリクエストを処理します。
+type MediaLibraryHandler struct {
+	Service *service.MediaLibraryService
+}
+
+// NewMediaLibraryHandler 新しいMediaLibraryHandlerを作成します。
+func NewMediaLibraryHandler(service *service.MediaLibraryService) *MediaLibraryHandler {
+	return &MediaLibraryHandler{
+		Service: service,
+	}
+}
+
+// HandleListMedia アップロード済みのメディアファイルの一覧を返します。
+func (h *MediaLibraryHandler) HandleListMedia(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	limit := 0
+	if value := query.Get("limit"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed <= 0 {
+			utils.RespondWithError(w, http.StatusBadRequest, "limit must be a positive integer")
+			return
+		}
+		limit = parsed
+	}
+
+	media, err := h.Service.ListMedia(r.Context(), query.Get("continuationToken"), limit)
+	if err != nil {
+		utils.RespondWithDomainError(w, r, err, "Failed to list media")
+		return
+	}
+
+	utils.RespondWithJSON(w, http.StatusOK, media)
+}
+
+// HandleDeleteMedia メディアファイルを削除します。
+func (h *MediaLibraryHandler) HandleDeleteMedia(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	key := query.Get("key")
+	if key == "" {
+		utils.RespondWithError(w, http.StatusBadRequest, "Missing media key")
+		return
+	}
+
+	if err := h.Service.DeleteMedia(r.Context(), key, query.Get("force") == "true"); err != nil {
+		utils.RespondWithDomainError(w, r, err, "Failed to delete media")
+		return
+	}
+
+	utils.RespondWithJSON(w, http.StatusOK, map[string]string{"message": "Media deleted successfully"})
+}
+
+// HandleRenameMedia メディアファイルの名前を変更します。
+func (h *MediaLibraryHandler) HandleRenameMedia(w http.ResponseWriter, r *http.Request) {
+	var req dto.RenameMediaDto
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, "Failed to parse JSON")
+		return
+	}
+
+	media, err := h.Service.RenameMedia(r.Context(), req)
+	if err != nil {
+		utils.RespondWithDomainError(w, r, err, "Failed to rename media")
+		return
+	}
+
+	utils.RespondWithJSON(w, http.StatusOK, media)
+}
@@ -0,0 +1,95 @@
+package api
+
+import (
+	"cmTranscribe/internal/app/dto"
+	"cmTranscribe/internal/app/service"
+	"cmTranscribe/internal/shared/metrics"
+	"cmTranscribe/internal/shared/utils"
+	"encoding/json"
+	"github.com/gorilla/mux"
+	"net/http"
+	"strconv"
+)
+
+// ChecksumHeader パートのSHA-256 (16進数) を指定するリクエストヘッダー
+const ChecksumHeader = "X-Checksum-Sha256"
+
+// ResumableUploadHandler 再開可能なアップロードに関するAPIリクエストを処理します。
+type ResumableUploadHandler struct {
+	Service *service.ResumableUploadService
+}
+
+// NewResumableUploadHandler 新しいResumableUploadHandlerを作成します。
+func NewResumableUploadHandler(service *service.ResumableUploadService) *ResumableUploadHandler {
+	return &ResumableUploadHandler{
+		Service: service,
+	}
+}
+
+// HandleCreateUpload 再開可能なアップロードを開始し、アップロードIDを返します。
+func (h *ResumableUploadHandler) HandleCreateUpload(w http.ResponseWriter, r *http.Request) {
+	var req dto.CreateUploadSessionDto
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, "Failed to parse JSON")
+		return
+	}
+
+	upload, err := h.Service.CreateUpload(r.Context(), req)
+	if err != nil {
+		utils.RespondWithDomainError(w, r, err, "Failed to start upload")
+		return
+	}
+
+	utils.RespondWithJSON(w, http.StatusCreated, upload)
+}
+
+// HandleUploadPart パートを1件受け取ります。リクエストボディがパートの内容です。
+func (h *ResumableUploadHandler) HandleUploadPart(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	partNumber, err := strconv.Atoi(vars["partNumber"])
+	if err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, "partNumber must be an integer")
+		return
+	}
+
+	part, err := h.Service.UploadPart(r.Context(), vars["uploadId"], partNumber, r.Header.Get(ChecksumHeader), r.ContentLength, r.Body)
+	if err != nil {
+		utils.RespondWithDomainError(w, r, err, "Failed to upload part")
+		return
+	}
+	metrics.UploadBytes.Add(float64(part.Size), metrics.UploadMethodResumable)
+
+	utils.RespondWithJSON(w, http.StatusOK, part)
+}
+
+// HandleGetUpload アップロードの状態と受け取り済みのパートを返します。
+func (h *ResumableUploadHandler) HandleGetUpload(w http.ResponseWriter, r *http.Request) {
+	upload, err := h.Service.GetUpload(r.Context(), mux.Vars(r)["uploadId"])
+	if err != nil {
+		utils.RespondWithDomainError(w, r, err, "Failed to get upload")
+		return
+	}
+
+	utils.RespondWithJSON(w, http.StatusOK, upload)
+}
+
+// HandleCompleteUpload 受け取り済みのパートを結合し、s3:// 形式のURIを返します。
+func (h *ResumableUploadHandler) HandleCompleteUpload(w http.ResponseWriter, r *http.Request) {
+	completed, err := h.Service.CompleteUpload(r.Context(), mux.Vars(r)["uploadId"])
+	if err != nil {
+		utils.RespondWithDomainError(w, r, err, "Failed to complete upload")
+		return
+	}
+
+	utils.RespondWithJSON(w, http.StatusOK, completed)
+}
+
+// HandleAbortUpload 完了していないアップロードを中止します。
+func (h *ResumableUploadHandler) HandleAbortUpload(w http.ResponseWriter, r *http.Request) {
+	if err := h.Service.AbortUpload(r.Context(), mux.Vars(r)["uploadId"]); err != nil {
+		utils.RespondWithDomainError(w, r, err, "Failed to abort upload")
+		return
+	}
+
+	utils.RespondWithJSON(w, http.StatusOK, map[string]string{"message": "Upload aborted successfully"})
+}
@@ -1,16 +1,28 @@
 package api
 
 import (
+	"bufio"
+	"cmTranscribe/internal/app/dto"
 	"cmTranscribe/internal/app/service"
+	"cmTranscribe/internal/domain/domainerr"
+	"cmTranscribe/internal/domain/model"
 	"cmTranscribe/internal/infra/config"
+	"cmTranscribe/internal/shared/logger"
+	"cmTranscribe/internal/shared/metrics"
 	"cmTranscribe/internal/shared/utils"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"os"
 	"path/filepath"
+	"strings"
 )
 
+// multipartOverheadBytes ファイル以外のフォームの内容 (境界やヘッダー) として許容するサイズ
+const multipartOverheadBytes = 1 << 20
+
 type S3UploadHandler struct {
 	uploadService *service.S3UploadService
 }
@@ -20,58 +32,153 @@ func NewS3UploadHandler(uploadService *service.S3UploadService) *S3UploadHandler
 }
 
 func (h *S3UploadHandler) HandleUploadToS3(w http.ResponseWriter, r *http.Request) {
-	// 1. FormDataからファイルを取得
-	file, handler, err := r.FormFile("file")
-	if err != nil {
-		utils.RespondWithError(w, http.StatusBadRequest, "Failed to parse file from form data")
-		return
-	}
-	defer func() {
-		if err := file.Close(); err != nil {
-			fmt.Printf("Failed to close file: %v\n", err)
-		}
-	}()
+	// 1. リクエスト全体のサイズを制限 (フォームのフィールド分の余裕を持たせる)
+	r.Body = http.MaxBytesReader(w, r.Body, service.UploadMaxBytes(r.Context())+multipartOverheadBytes)
 
-	// 2. 一時ディレクトリにファイルを保存
-	tempFilePath, err := h.saveFileToTempDirectory(file, handler.Filename)
+	// 2. FormDataのファイルを一時ファイルに保存 (メモリやディスクに二重に溜めないようストリームで読む)
+	tempFilePath, err := h.saveFormFileToTempFile(r)
 	if err != nil {
-		utils.RespondWithError(w, http.StatusInternalServerError, err.Error())
+		utils.RespondWithDomainError(w, r, err, "Failed to save uploaded file")
 		return
 	}
 	defer func() {
 		if err := os.Remove(tempFilePath); err != nil {
-			fmt.Printf("Failed to remove temp file: %v\n", err)
+			logger.FromContext(r.Context()).Warn("Failed to remove temp file", "path", tempFilePath, "error", err)
 		}
 	}()
 
 	// 3. サービス層でアップロードを処理
-	url, err := h.uploadService.UploadToS3(r.Context(), tempFilePath, config.AppConfig.S3BucketName, config.AppConfig.S3PrefixUploadFile)
+	uploaded, err := h.uploadService.UploadToS3(r.Context(), tempFilePath, config.AppConfig.S3BucketName, service.UploadPrefix(r.Context()))
 	if err != nil {
-		utils.RespondWithError(w, http.StatusInternalServerError, "Failed to upload file to S3: "+err.Error())
+		utils.RespondWithDomainError(w, r, err, "Failed to upload file to S3")
 		return
 	}
+	if info, err := os.Stat(tempFilePath); err == nil {
+		metrics.UploadBytes.Add(float64(info.Size()), metrics.UploadMethodServer)
+	}
 
 	// 4. JSON形式でレスポンスを返す
-	utils.RespondWithJSON(w, http.StatusOK, map[string]string{"url": url})
+	utils.RespondWithJSON(w, http.StatusOK, uploaded)
 }
 
-// saveFileToTempDirectory は、ファイルを一時ディレクトリに保存し、そのパスを返します。
-func (h *S3UploadHandler) saveFileToTempDirectory(file io.Reader, fileName string) (string, error) {
-	tempFilePath := filepath.Join(os.TempDir(), fileName)
-	tempFile, err := os.Create(tempFilePath)
+// HandleCreateUploadURL は、S3 に直接アップロードするための署名付きURLを発行します
+func (h *S3UploadHandler) HandleCreateUploadURL(w http.ResponseWriter, r *http.Request) {
+	var req dto.CreateUploadURLDto
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, "Failed to parse JSON")
+		return
+	}
+
+	upload, err := h.uploadService.CreateUploadURL(r.Context(), req)
 	if err != nil {
-		return "", fmt.Errorf("failed to create temp file: %v", err)
+		utils.RespondWithDomainError(w, r, err, "Failed to create upload URL")
+		return
 	}
-	defer func() {
-		if err := tempFile.Close(); err != nil {
-			fmt.Printf("Failed to close file: %v\n", err)
+
+	utils.RespondWithJSON(w, http.StatusOK, upload)
+}
+
+// HandleConfirmUpload は、署名付きURLでのアップロードが完了したことを確認し、s3:// 形式のURIを返します
+func (h *S3UploadHandler) HandleConfirmUpload(w http.ResponseWriter, r *http.Request) {
+	var req dto.ConfirmUploadDto
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, "Failed to parse JSON")
+		return
+	}
+
+	confirmed, err := h.uploadService.ConfirmUpload(r.Context(), req)
+	if err != nil {
+		utils.RespondWithDomainError(w, r, err, "Failed to confirm upload")
+		return
+	}
+	metrics.UploadBytes.Add(float64(confirmed.Size), metrics.UploadMethodPresigned)
+
+	utils.RespondWithJSON(w, http.StatusOK, confirmed)
+}
+
+// saveFormFileToTempFile は、FormDataの "file" を一意な名前の一時ファイルに保存してパスを返します。
+// ファイル名はサニタイズし、拡張子は内容から判定したフォーマットに合わせます
+func (h *S3UploadHandler) saveFormFileToTempFile(r *http.Request) (string, error) {
+	reader, err := r.MultipartReader()
+	if err != nil {
+		return "", domainerr.New(domainerr.Validation, "multipart_required", "request must be multipart/form-data")
+	}
+
+	for {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			return "", domainerr.New(domainerr.Validation, "file_required", "file is required")
 		}
-	}()
+		if err != nil {
+			return "", formReadError(err)
+		}
+		if part.FormName() != "file" || part.FileName() == "" {
+			continue
+		}
+		return saveMediaToTempFile(part, part.FileName(), service.UploadMaxBytes(r.Context()))
+	}
+}
 
-	// ファイルを一時ディレクトリにコピー
-	if _, err := io.Copy(tempFile, file); err != nil {
-		return "", fmt.Errorf("failed to save file to temp directory: %v", err)
+// saveMediaToTempFile は、先頭のバイト列でフォーマットを判定してから maxBytes までを一時ファイルに書き込みます
+func saveMediaToTempFile(file io.Reader, fileName string, maxBytes int64) (string, error) {
+	buffered := bufio.NewReaderSize(file, model.MediaSniffLength)
+	header, err := buffered.Peek(model.MediaSniffLength)
+	if err != nil && err != io.EOF {
+		return "", formReadError(err)
 	}
+	if len(header) == 0 {
+		return "", domainerr.New(domainerr.Validation, "file_empty", "file is empty")
+	}
+	format := model.DetectMediaFormat(header)
+	if !isAllowedUploadFormat(format) {
+		return "", domainerr.New(domainerr.UnsupportedMediaType, "unsupported_media_format", "%s is not one of the allowed formats (%s)", fileName, strings.Join(config.AppConfig.UploadAllowedFormats, ", "))
+	}
+
+	// 同じファイル名のアップロードが上書きし合わないよう一意な名前で作成する
+	baseName := utils.SanitizeFileName(strings.TrimSuffix(fileName, filepath.Ext(fileName)), "upload")
+	tempFile, err := os.CreateTemp("", "*-"+baseName+"."+format)
+	if err != nil {
+		return "", fmt.Errorf("failed to create temp file: %w", err)
+	}
+	tempFilePath := tempFile.Name()
 
+	written, err := io.Copy(tempFile, io.LimitReader(buffered, maxBytes+1))
+	if closeErr := tempFile.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil && written > maxBytes {
+		err = domainerr.New(domainerr.TooLarge, "file_too_large", "file must not exceed %d bytes", maxBytes)
+	}
+	if err != nil {
+		if removeErr := os.Remove(tempFilePath); removeErr != nil {
+			logger.Default().Warn("Failed to remove temp file", "path", tempFilePath, "error", removeErr)
+		}
+		if errors.Is(err, domainerr.TooLarge) {
+			return "", err
+		}
+		return "", formReadError(err)
+	}
 	return tempFilePath, nil
 }
+
+// isAllowedUploadFormat 設定で許可されたフォーマットかどうかを返します
+func isAllowedUploadFormat(format string) bool {
+	if format == "" {
+		return false
+	}
+	for _, allowed := range config.AppConfig.UploadAllowedFormats {
+		if allowed == format {
+			return true
+		}
+	}
+	return false
+}
+
+// formReadError リクエストボディの読み込みエラーを、サイズ超過とそれ以外に分けて返します
+func formReadError(err error) error {
+	var maxBytesErr *http.MaxBytesError
+	if errors.As(err, &maxBytesErr) {
+		return domainerr.New(domainerr.TooLarge, "request_too_large", "request body must not exceed %d bytes", maxBytesErr.Limit)
+	}
+	return domainerr.Wrap(domainerr.Validation, "invalid_form_data", err, "failed to read file from form data")
+}
@@ -0,0 +1,94 @@
+package api
+
+import (
+	"cmTranscribe/internal/app/service"
+	"cmTranscribe/internal/shared/logger"
+	"cmTranscribe/internal/shared/utils"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+)
+
+// storageFormFieldMaxBytes 署名付きフォームのフィールド1件あたりのサイズの上限
+const storageFormFieldMaxBytes = 64 * 1024
+
+// StorageObjectHandler ローカルストレージの署名付きURLへのリクエストを処理します。
+type StorageObjectHandler struct {
+	Service *service.StorageObjectService
+}
+
+// NewStorageObjectHandler 新しいStorageObjectHandlerを作成します。
+func NewStorageObjectHandler(service *service.StorageObjectService) *StorageObjectHandler {
+	return &StorageObjectHandler{
+		Service: service,
+	}
+}
+
+// HandleGetObject 署名付きURLのオブジェクトを返します。Rangeリクエストにも対応します。
+func (h *StorageObjectHandler) HandleGetObject(w http.ResponseWriter, r *http.Request) {
+	object, err := h.Service.GetObject(r.Context(), r.URL.Query())
+	if err != nil {
+		utils.RespondWithDomainError(w, r, err, "Failed to get object")
+		return
+	}
+	defer func() {
+		if err := object.Body.Close(); err != nil {
+			logger.FromContext(r.Context()).Warn("Error closing object", "key", object.Key, "error", err)
+		}
+	}()
+
+	w.Header().Set("Content-Type", object.ContentType)
+	http.ServeContent(w, r, path.Base(object.Key), object.LastModified, object.Body)
+}
+
+// HandlePutObject 署名付きURLにPUTされたリクエストボディを保存します。
+func (h *StorageObjectHandler) HandlePutObject(w http.ResponseWriter, r *http.Request) {
+	uploaded, err := h.Service.PutObject(r.Context(), r.URL.Query(), r.Header.Get("Content-Type"), r.ContentLength, r.Body)
+	if err != nil {
+		utils.RespondWithDomainError(w, r, err, "Failed to save object")
+		return
+	}
+
+	utils.RespondWithJSON(w, http.StatusOK, uploaded)
+}
+
+// HandlePostObject 署名付きのフォームフィールドと一緒にmultipart/form-dataで送られたファイルを保存します。
+// フィールドはファイル (file) より前に送る必要があります。
+func (h *StorageObjectHandler) HandlePostObject(w http.ResponseWriter, r *http.Request) {
+	reader, err := r.MultipartReader()
+	if err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, "Request must be multipart/form-data")
+		return
+	}
+
+	fields := url.Values{}
+	for {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			utils.RespondWithError(w, http.StatusBadRequest, "Missing file in form data")
+			return
+		}
+		if err != nil {
+			utils.RespondWithError(w, http.StatusBadRequest, "Failed to read form data")
+			return
+		}
+
+		if part.FormName() == "file" {
+			uploaded, err := h.Service.PostObject(r.Context(), fields, part)
+			if err != nil {
+				utils.RespondWithDomainError(w, r, err, "Failed to save object")
+				return
+			}
+			utils.RespondWithJSON(w, http.StatusOK, uploaded)
+			return
+		}
+
+		value, err := io.ReadAll(io.LimitReader(part, storageFormFieldMaxBytes))
+		if err != nil {
+			utils.RespondWithError(w, http.StatusBadRequest, "Failed to read form data")
+			return
+		}
+		fields.Add(part.FormName(), string(value))
+	}
+}
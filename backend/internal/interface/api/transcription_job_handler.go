@@ -3,13 +3,12 @@ package api
 import (
 	"cmTranscribe/internal/app/dto"
 	"cmTranscribe/internal/app/service"
+	"cmTranscribe/internal/shared/logger"
 	"cmTranscribe/internal/shared/utils"
 	"encoding/json"
-	"fmt"
 	"github.com/gorilla/mux"
-	"log"
 	"net/http"
-	"strings"
+	"strconv"
 )
 
 // TranscriptionJobHandler APIリクエストを処理します。
@@ -31,29 +30,39 @@ func (h *TranscriptionJobHandler) HandleStartJob(w http.ResponseWriter, r *http.
 		return
 	}
 
-	fmt.Printf("TranscriptionDto: %v\n", req)
+	logger.FromContext(r.Context()).Debug("Starting transcription job", "job_name", req.JobName, "media_uri", req.MediaURI)
 
 	job, err := h.Service.StartTranscriptionJob(r.Context(), &req)
 	if err != nil {
-		log.Println("err.Error():", err.Error())
-		log.Println("err:", err)
-		if strings.Contains(err.Error(), "conflict: job name already exists") {
-			utils.RespondWithError(w, http.StatusConflict, err.Error()) // 409を返す
-			return
-		}
-		utils.RespondWithError(w, http.StatusInternalServerError, "Failed to start transcription")
+		utils.RespondWithDomainError(w, r, err, "Failed to start transcription")
 		return
 	}
 
+	// 同時実行数の上限に達していて送信を待っている場合は 202 を返す
+	if job.QueuePosition > 0 {
+		utils.RespondWithJSON(w, http.StatusAccepted, job)
+		return
+	}
 	utils.RespondWithJSON(w, http.StatusOK, job)
 }
 
 // HandleGetJobList 文字起こしジョブリストのAPIリクエストを処理します。
 func (h *TranscriptionJobHandler) HandleGetJobList(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	limit := 0
+	if value := query.Get("limit"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed <= 0 {
+			utils.RespondWithError(w, http.StatusBadRequest, "limit must be a positive integer")
+			return
+		}
+		limit = parsed
+	}
+
 	// サービスを使ってジョブリストを取得
-	jobList, err := h.Service.GetTranscriptionJobList(r.Context())
+	jobList, err := h.Service.GetTranscriptionJobList(r.Context(), query.Get("nextToken"), limit)
 	if err != nil {
-		utils.RespondWithError(w, http.StatusInternalServerError, "Failed to get transcription job list")
+		utils.RespondWithDomainError(w, r, err, "Failed to get transcription job list")
 		return
 	}
 
@@ -75,7 +84,7 @@ func (h *TranscriptionJobHandler) HandleGetJob(w http.ResponseWriter, r *http.Re
 	// サービスを使って特定のジョブを取得
 	job, err := h.Service.GetTranscriptionJob(r.Context(), jobName)
 	if err != nil {
-		utils.RespondWithError(w, http.StatusInternalServerError, "Failed to get transcription job")
+		utils.RespondWithDomainError(w, r, err, "Failed to get transcription job")
 		return
 	}
 
@@ -95,7 +104,7 @@ func (h *TranscriptionJobHandler) HandleGetTranscriptionContent(w http.ResponseW
 	// サービスを使って文字起こし内容を取得
 	contentDto, err := h.Service.GetTranscriptionContent(r.Context(), jobName)
 	if err != nil {
-		utils.RespondWithError(w, http.StatusInternalServerError, "Failed to get transcription content")
+		utils.RespondWithDomainError(w, r, err, "Failed to get transcription content")
 		return
 	}
 
@@ -0,0 +1,104 @@
+package api
+
+import (
+	"cmTranscribe/internal/app/dto"
+	"cmTranscribe/internal/app/service"
+	"cmTranscribe/internal/shared/metrics"
+	"cmTranscribe/internal/shared/utils"
+	"encoding/json"
+	"net/http"
+)
+
+// VocabularyFilterHandler 語彙フィルタのAPIリクエストを処理します。
+type VocabularyFilterHandler struct {
+	Service *service.VocabularyFilterService
+}
+
+// NewVocabularyFilterHandler 新しいVocabularyFilterHandlerを作成します。
+func NewVocabularyFilterHandler(service *service.VocabularyFilterService) *VocabularyFilterHandler {
+	return &VocabularyFilterHandler{
+		Service: service,
+	}
+}
+
+// HandleCreateVocabularyFilter 語彙フィルタの作成リクエストを処理します。
+func (h *VocabularyFilterHandler) HandleCreateVocabularyFilter(w http.ResponseWriter, r *http.Request) {
+	var req dto.CreateVocabularyFilterDto
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, "Failed to parse JSON")
+		return
+	}
+
+	err := h.Service.CreateVocabularyFilter(r.Context(), req)
+	metrics.VocabularyOperations.Inc(metrics.ResourceVocabularyFilter, "create", metrics.Result(err))
+	if err != nil {
+		utils.RespondWithDomainError(w, r, err, "Failed to create vocabulary filter")
+		return
+	}
+
+	utils.RespondWithJSON(w, http.StatusOK, map[string]string{"message": "Vocabulary filter created successfully"})
+}
+
+// HandleUpdateVocabularyFilter 語彙フィルタの更新リクエストを処理します。
+func (h *VocabularyFilterHandler) HandleUpdateVocabularyFilter(w http.ResponseWriter, r *http.Request) {
+	var req dto.UpdateVocabularyFilterDto
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, "Failed to parse JSON")
+		return
+	}
+
+	err := h.Service.UpdateVocabularyFilter(r.Context(), req)
+	metrics.VocabularyOperations.Inc(metrics.ResourceVocabularyFilter, "update", metrics.Result(err))
+	if err != nil {
+		utils.RespondWithDomainError(w, r, err, "Failed to update vocabulary filter")
+		return
+	}
+
+	utils.RespondWithJSON(w, http.StatusOK, map[string]string{"message": "Vocabulary filter updated successfully"})
+}
+
+// HandleGetVocabularyFilter 語彙フィルタを単語リストとともに取得します。
+func (h *VocabularyFilterHandler) HandleGetVocabularyFilter(w http.ResponseWriter, r *http.Request) {
+	name := r.URL.Query().Get("name")
+	if name == "" {
+		utils.RespondWithError(w, http.StatusBadRequest, "Missing vocabulary filter name")
+		return
+	}
+
+	filter, err := h.Service.GetVocabularyFilter(r.Context(), name)
+	if err != nil {
+		utils.RespondWithDomainError(w, r, err, "Failed to get vocabulary filter")
+		return
+	}
+
+	utils.RespondWithJSON(w, http.StatusOK, filter)
+}
+
+// HandleListVocabularyFilters 語彙フィルタの一覧を取得します。
+func (h *VocabularyFilterHandler) HandleListVocabularyFilters(w http.ResponseWriter, r *http.Request) {
+	filters, err := h.Service.ListVocabularyFilters(r.Context())
+	if err != nil {
+		utils.RespondWithDomainError(w, r, err, "Failed to list vocabulary filters")
+		return
+	}
+
+	utils.RespondWithJSON(w, http.StatusOK, filters)
+}
+
+// HandleDeleteVocabularyFilter 語彙フィルタを削除します。
+func (h *VocabularyFilterHandler) HandleDeleteVocabularyFilter(w http.ResponseWriter, r *http.Request) {
+	name := r.URL.Query().Get("name")
+	if name == "" {
+		utils.RespondWithError(w, http.StatusBadRequest, "Missing vocabulary filter name")
+		return
+	}
+
+	err := h.Service.DeleteVocabularyFilter(r.Context(), name)
+	metrics.VocabularyOperations.Inc(metrics.ResourceVocabularyFilter, "delete", metrics.Result(err))
+	if err != nil {
+		utils.RespondWithDomainError(w, r, err, "Failed to delete vocabulary filter")
+		return
+	}
+
+	utils.RespondWithJSON(w, http.StatusOK, map[string]string{"message": "Vocabulary filter deleted successfully"})
+}
@@ -0,0 +1,36 @@
+package api
+
+import (
+	"cmTranscribe/internal/app/service"
+	"cmTranscribe/internal/shared/utils"
+	"net/http"
+)
+
+// VocabularyReportHandler ボキャブラリの効果レポートに関するAPIリクエストを処理します。
+type VocabularyReportHandler struct {
+	Service *service.VocabularyReportService
+}
+
+// NewVocabularyReportHandler 新しいVocabularyReportHandlerを作成します。
+func NewVocabularyReportHandler(service *service.VocabularyReportService) *VocabularyReportHandler {
+	return &VocabularyReportHandler{
+		Service: service,
+	}
+}
+
+// HandleGetEffectivenessReport ボキャブラリの語彙ごとの出現状況を返します。
+func (h *VocabularyReportHandler) HandleGetEffectivenessReport(w http.ResponseWriter, r *http.Request) {
+	vocabularyName := r.URL.Query().Get("name")
+	if vocabularyName == "" {
+		utils.RespondWithError(w, http.StatusBadRequest, "Missing vocabulary name")
+		return
+	}
+
+	report, err := h.Service.GetEffectivenessReport(r.Context(), vocabularyName)
+	if err != nil {
+		utils.RespondWithDomainError(w, r, err, "Failed to get vocabulary effectiveness report")
+		return
+	}
+
+	utils.RespondWithJSON(w, http.StatusOK, report)
+}
@@ -0,0 +1,91 @@
+package api
+
+import (
+	"cmTranscribe/internal/app/dto"
+	"cmTranscribe/internal/app/service"
+	"cmTranscribe/internal/shared/utils"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+)
+
+// VocabularySuggestionHandler ボキャブラリの追加候補に関するAPIリクエストを処理します。
+type VocabularySuggestionHandler struct {
+	Service *service.VocabularySuggestionService
+}
+
+// NewVocabularySuggestionHandler 新しいVocabularySuggestionHandlerを作成します。
+func NewVocabularySuggestionHandler(service *service.VocabularySuggestionService) *VocabularySuggestionHandler {
+	return &VocabularySuggestionHandler{
+		Service: service,
+	}
+}
+
+// HandleRecordCorrection レビュアーによる文字起こし結果の修正を登録します。
+func (h *VocabularySuggestionHandler) HandleRecordCorrection(w http.ResponseWriter, r *http.Request) {
+	var req dto.TranscriptCorrectionDto
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, "Failed to parse JSON")
+		return
+	}
+
+	if err := h.Service.RecordCorrection(r.Context(), req); err != nil {
+		utils.RespondWithDomainError(w, r, err, "Failed to record transcript correction")
+		return
+	}
+
+	utils.RespondWithJSON(w, http.StatusCreated, map[string]string{"message": "Transcript correction recorded successfully"})
+}
+
+// HandleGetSuggestions 文字起こし結果から作成したボキャブラリの追加候補を返します。
+func (h *VocabularySuggestionHandler) HandleGetSuggestions(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	maxConfidence := 0.0
+	if value := query.Get("maxConfidence"); value != "" {
+		parsed, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			utils.RespondWithError(w, http.StatusBadRequest, "maxConfidence must be a number")
+			return
+		}
+		maxConfidence = parsed
+	}
+	limit := 0
+	if value := query.Get("limit"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil {
+			utils.RespondWithError(w, http.StatusBadRequest, "limit must be a number")
+			return
+		}
+		limit = parsed
+	}
+
+	suggestions, err := h.Service.GetSuggestions(r.Context(), query.Get("language_code"), query.Get("name"), maxConfidence, limit)
+	if err != nil {
+		utils.RespondWithDomainError(w, r, err, "Failed to get vocabulary suggestions")
+		return
+	}
+
+	utils.RespondWithJSON(w, http.StatusOK, suggestions)
+}
+
+// HandleAcceptSuggestions 採用した候補をボキャブラリに追加します。
+func (h *VocabularySuggestionHandler) HandleAcceptSuggestions(w http.ResponseWriter, r *http.Request) {
+	var req dto.AcceptSuggestionsDto
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, "Failed to parse JSON")
+		return
+	}
+	if req.VocabularyName == "" || len(req.Vocabularies) == 0 {
+		utils.RespondWithError(w, http.StatusBadRequest, "name and vocabularies are required")
+		return
+	}
+
+	added, err := h.Service.AcceptSuggestions(r.Context(), req)
+	if err != nil {
+		utils.RespondWithDomainError(w, r, err, "Failed to accept vocabulary suggestions")
+		return
+	}
+
+	utils.RespondWithJSON(w, http.StatusOK, map[string]string{"message": fmt.Sprintf("%d suggestions added to custom vocabulary", added)})
+}
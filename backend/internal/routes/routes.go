@@ -1,7 +1,10 @@
 package routes
 
 import (
+	"cmTranscribe/internal/domain/repository"
+	"cmTranscribe/internal/domain/service"
 	"cmTranscribe/internal/interface/api"
+	"cmTranscribe/internal/shared/metrics"
 	"cmTranscribe/internal/shared/middleware"
 	"github.com/gorilla/mux"
 	"net/http"
@@ -11,32 +14,113 @@ type Router struct {
 	TranscriptionHandler    *api.TranscriptionJobHandler
 	CustomVocabularyHandler *api.CustomVocabularyHandler
 	S3UploadHandler         *api.S3UploadHandler
+	SuggestionHandler       *api.VocabularySuggestionHandler
+	VocabularyFilterHandler *api.VocabularyFilterHandler
+	ReportHandler           *api.VocabularyReportHandler
+	ResumableUploadHandler  *api.ResumableUploadHandler
+	MediaLibraryHandler     *api.MediaLibraryHandler
+	StorageObjectHandler    *api.StorageObjectHandler   // ローカルストレージを使う場合のみ
+	Authenticator           service.Authenticator       // 認証が無効な場合は nil
+	Tenants                 repository.TenantRepository // テナントを使わない場合は nil
+	RateLimiter             *middleware.RateLimiter     // レート制限が無効な場合は nil
+	MetricsPath             string                      // メトリクスを公開するパス (空の場合は公開しない)
 }
 
 func NewRouter(
 	transcriptionHandler *api.TranscriptionJobHandler,
 	customVocabularyHandler *api.CustomVocabularyHandler,
 	s3UploadHandler *api.S3UploadHandler,
+	suggestionHandler *api.VocabularySuggestionHandler,
+	vocabularyFilterHandler *api.VocabularyFilterHandler,
+	reportHandler *api.VocabularyReportHandler,
+	resumableUploadHandler *api.ResumableUploadHandler,
+	mediaLibraryHandler *api.MediaLibraryHandler,
+	storageObjectHandler *api.StorageObjectHandler,
+	authenticator service.Authenticator,
+	tenants repository.TenantRepository,
+	rateLimiter *middleware.RateLimiter,
+	metricsPath string,
 ) *Router {
 	return &Router{
 		TranscriptionHandler:    transcriptionHandler,
 		CustomVocabularyHandler: customVocabularyHandler,
 		S3UploadHandler:         s3UploadHandler,
+		SuggestionHandler:       suggestionHandler,
+		VocabularyFilterHandler: vocabularyFilterHandler,
+		ReportHandler:           reportHandler,
+		ResumableUploadHandler:  resumableUploadHandler,
+		MediaLibraryHandler:     mediaLibraryHandler,
+		StorageObjectHandler:    storageObjectHandler,
+		Authenticator:           authenticator,
+		Tenants:                 tenants,
+		RateLimiter:             rateLimiter,
+		MetricsPath:             metricsPath,
 	}
 }
 
 func (r *Router) RegisterRoutes() *mux.Router {
 
 	router := mux.NewRouter()
+	// 認証やレート制限で拒否したリクエストも数えるため、最初に設定する
+	router.Use(middleware.MetricsMiddleware())
+
+	// ストレージのオブジェクトは署名付きURLで認可され、メトリクスは Prometheus が収集するため、認証の対象外とする
+	publicPaths := []string{"/api/storage/objects"}
+	if r.MetricsPath != "" {
+		publicPaths = append(publicPaths, r.MetricsPath)
+	}
+	if r.Authenticator != nil {
+		router.Use(middleware.AuthMiddleware(r.Authenticator, publicPaths...))
+	}
+	if r.Tenants != nil {
+		// テナントは認証情報から解決するため、認証の後に設定する
+		router.Use(middleware.TenantMiddleware(r.Tenants, publicPaths...))
+	}
+	if r.RateLimiter != nil {
+		// テナントや呼び出し元ごとに制限するため、テナントの後に設定する
+		router.Use(middleware.RateLimitMiddleware(r.RateLimiter))
+	}
 
 	router.Handle("/api/transcriptions", middleware.HttpMethodMiddleware(http.HandlerFunc(r.TranscriptionHandler.HandleGetJobList), http.MethodGet))
 	router.Handle("/api/transcriptions/start", middleware.HttpMethodMiddleware(http.HandlerFunc(r.TranscriptionHandler.HandleStartJob), http.MethodPost))
+	router.Handle("/api/transcriptions/corrections", middleware.HttpMethodMiddleware(http.HandlerFunc(r.SuggestionHandler.HandleRecordCorrection), http.MethodPost))
 	router.Handle("/api/transcriptions/content", middleware.HttpMethodMiddleware(http.HandlerFunc(r.TranscriptionHandler.HandleGetTranscriptionContent), http.MethodGet))
 	router.Handle("/api/transcriptions/{jobName}", middleware.HttpMethodMiddleware(http.HandlerFunc(r.TranscriptionHandler.HandleGetJob), http.MethodGet))
 	router.Methods(http.MethodPost).Path("/api/custom/vocabulary").Handler(middleware.HttpMethodMiddleware(http.HandlerFunc(r.CustomVocabularyHandler.HandleCreateVocabulary), http.MethodPost))
 	router.Methods(http.MethodPut).Path("/api/custom/vocabulary").Handler(middleware.HttpMethodMiddleware(http.HandlerFunc(r.CustomVocabularyHandler.HandleUpdateVocabulary), http.MethodPut))
+	router.Methods(http.MethodPatch).Path("/api/custom/vocabulary").Handler(middleware.HttpMethodMiddleware(http.HandlerFunc(r.CustomVocabularyHandler.HandlePatchVocabulary), http.MethodPatch))
 	router.Methods(http.MethodGet).Path("/api/custom/vocabulary").Handler(middleware.HttpMethodMiddleware(http.HandlerFunc(r.CustomVocabularyHandler.HandleGetVocabularyByName), http.MethodGet))
+	router.Handle("/api/custom/vocabulary/versions", middleware.HttpMethodMiddleware(http.HandlerFunc(r.CustomVocabularyHandler.HandleGetVocabularyVersions), http.MethodGet))
+	router.Handle("/api/custom/vocabulary/versions/diff", middleware.HttpMethodMiddleware(http.HandlerFunc(r.CustomVocabularyHandler.HandleDiffVocabularyVersions), http.MethodGet))
+	router.Handle("/api/custom/vocabulary/rollback", middleware.HttpMethodMiddleware(http.HandlerFunc(r.CustomVocabularyHandler.HandleRollbackVocabulary), http.MethodPost))
+	router.Handle("/api/custom/vocabulary/readings", middleware.HttpMethodMiddleware(http.HandlerFunc(r.CustomVocabularyHandler.HandleSuggestReadings), http.MethodPost))
+	router.Handle("/api/custom/vocabulary/report", middleware.HttpMethodMiddleware(http.HandlerFunc(r.ReportHandler.HandleGetEffectivenessReport), http.MethodGet))
+	router.Handle("/api/custom/vocabulary/suggestions", middleware.HttpMethodMiddleware(http.HandlerFunc(r.SuggestionHandler.HandleGetSuggestions), http.MethodGet))
+	router.Handle("/api/custom/vocabulary/suggestions/accept", middleware.HttpMethodMiddleware(http.HandlerFunc(r.SuggestionHandler.HandleAcceptSuggestions), http.MethodPost))
+	router.Methods(http.MethodPost).Path("/api/custom/vocabulary-filter").Handler(middleware.HttpMethodMiddleware(http.HandlerFunc(r.VocabularyFilterHandler.HandleCreateVocabularyFilter), http.MethodPost))
+	router.Methods(http.MethodPut).Path("/api/custom/vocabulary-filter").Handler(middleware.HttpMethodMiddleware(http.HandlerFunc(r.VocabularyFilterHandler.HandleUpdateVocabularyFilter), http.MethodPut))
+	router.Methods(http.MethodGet).Path("/api/custom/vocabulary-filter").Handler(middleware.HttpMethodMiddleware(http.HandlerFunc(r.VocabularyFilterHandler.HandleGetVocabularyFilter), http.MethodGet))
+	router.Methods(http.MethodDelete).Path("/api/custom/vocabulary-filter").Handler(middleware.HttpMethodMiddleware(http.HandlerFunc(r.VocabularyFilterHandler.HandleDeleteVocabularyFilter), http.MethodDelete))
+	router.Handle("/api/custom/vocabulary-filters", middleware.HttpMethodMiddleware(http.HandlerFunc(r.VocabularyFilterHandler.HandleListVocabularyFilters), http.MethodGet))
 	//router.Handle("/api/custom/vocabulary", http.HandlerFunc(r.CustomVocabularyHandler.HandleVocabulary))
 	router.Handle("/api/s3/upload", middleware.HttpMethodMiddleware(http.HandlerFunc(r.S3UploadHandler.HandleUploadToS3), http.MethodPost))
+	router.Handle("/api/s3/upload-url", middleware.HttpMethodMiddleware(http.HandlerFunc(r.S3UploadHandler.HandleCreateUploadURL), http.MethodPost))
+	router.Handle("/api/s3/upload/confirm", middleware.HttpMethodMiddleware(http.HandlerFunc(r.S3UploadHandler.HandleConfirmUpload), http.MethodPost))
+	router.Handle("/api/uploads", middleware.HttpMethodMiddleware(http.HandlerFunc(r.ResumableUploadHandler.HandleCreateUpload), http.MethodPost))
+	router.Methods(http.MethodGet).Path("/api/uploads/{uploadId}").Handler(middleware.HttpMethodMiddleware(http.HandlerFunc(r.ResumableUploadHandler.HandleGetUpload), http.MethodGet))
+	router.Methods(http.MethodDelete).Path("/api/uploads/{uploadId}").Handler(middleware.HttpMethodMiddleware(http.HandlerFunc(r.ResumableUploadHandler.HandleAbortUpload), http.MethodDelete))
+	router.Handle("/api/uploads/{uploadId}/parts/{partNumber:[0-9]+}", middleware.HttpMethodMiddleware(http.HandlerFunc(r.ResumableUploadHandler.HandleUploadPart), http.MethodPut))
+	router.Handle("/api/uploads/{uploadId}/complete", middleware.HttpMethodMiddleware(http.HandlerFunc(r.ResumableUploadHandler.HandleCompleteUpload), http.MethodPost))
+	router.Methods(http.MethodGet).Path("/api/media").Handler(middleware.HttpMethodMiddleware(http.HandlerFunc(r.MediaLibraryHandler.HandleListMedia), http.MethodGet))
+	router.Methods(http.MethodDelete).Path("/api/media").Handler(middleware.HttpMethodMiddleware(http.HandlerFunc(r.MediaLibraryHandler.HandleDeleteMedia), http.MethodDelete))
+	router.Handle("/api/media/rename", middleware.HttpMethodMiddleware(http.HandlerFunc(r.MediaLibraryHandler.HandleRenameMedia), http.MethodPost))
+	if r.MetricsPath != "" {
+		router.Methods(http.MethodGet).Path(r.MetricsPath).Handler(metrics.Handler())
+	}
+	if r.StorageObjectHandler != nil {
+		router.Methods(http.MethodGet).Path("/api/storage/objects").Handler(middleware.HttpMethodMiddleware(http.HandlerFunc(r.StorageObjectHandler.HandleGetObject), http.MethodGet))
+		router.Methods(http.MethodPut).Path("/api/storage/objects").Handler(middleware.HttpMethodMiddleware(http.HandlerFunc(r.StorageObjectHandler.HandlePutObject), http.MethodPut))
+		router.Methods(http.MethodPost).Path("/api/storage/objects").Handler(middleware.HttpMethodMiddleware(http.HandlerFunc(r.StorageObjectHandler.HandlePostObject), http.MethodPost))
+	}
 	return router
 }
@@ -0,0 +1,104 @@
+package metrics
+
+import (
+	"net/http"
+	"runtime"
+	"time"
+)
+
+// Default アプリケーションのメトリクスを登録するRegistry
+var Default = NewRegistry()
+
+// Handler Default のメトリクスを返す http.Handler を返します
+func Handler() http.Handler {
+	return Default.Handler()
+}
+
+// ヒストグラムのバケット (秒)
+var (
+	LatencyBuckets     = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30}
+	JobDurationBuckets = []float64{10, 30, 60, 120, 300, 600, 1200, 1800, 3600, 7200}
+)
+
+// アップロードの方法
+const (
+	UploadMethodServer    = "server"    // サーバー経由のアップロード
+	UploadMethodPresigned = "presigned" // 署名付きURLでのアップロード (確認時に数える)
+	UploadMethodResumable = "resumable" // 再開可能なアップロードのパート
+)
+
+// カスタムボキャブラリの操作の対象
+const (
+	ResourceCustomVocabulary = "custom_vocabulary"
+	ResourceVocabularyFilter = "vocabulary_filter"
+)
+
+// 操作の結果
+const (
+	ResultSuccess = "success"
+	ResultError   = "error"
+)
+
+// HTTPリクエスト
+var (
+	HTTPRequests = Default.NewCounterVec("cmtranscribe_http_requests_total",
+		"Number of HTTP requests by method, route template and status code.", "method", "route", "status")
+	HTTPRequestDuration = Default.NewHistogramVec("cmtranscribe_http_request_duration_seconds",
+		"HTTP request latency by method and route template.", LatencyBuckets, "method", "route")
+)
+
+// AWS APIの呼び出し
+var (
+	AWSCalls = Default.NewCounterVec("cmtranscribe_aws_calls_total",
+		"Number of AWS API calls by service and operation, including failed calls.", "service", "operation")
+	AWSCallErrors = Default.NewCounterVec("cmtranscribe_aws_call_errors_total",
+		"Number of failed AWS API calls by service, operation and AWS error code.", "service", "operation", "code")
+	AWSCallDuration = Default.NewHistogramVec("cmtranscribe_aws_call_duration_seconds",
+		"AWS API call latency including retries by service and operation.", LatencyBuckets, "service", "operation")
+)
+
+// 文字起こしジョブ
+var (
+	TranscriptionJobsSubmitted = Default.NewCounterVec("cmtranscribe_transcription_jobs_submitted_total",
+		"Number of transcription jobs accepted by Amazon Transcribe by language code.", "language")
+	TranscriptionJobsCompleted = Default.NewCounterVec("cmtranscribe_transcription_jobs_completed_total",
+		"Number of transcription jobs observed as COMPLETED by language code.", "language")
+	TranscriptionJobsFailed = Default.NewCounterVec("cmtranscribe_transcription_jobs_failed_total",
+		"Number of transcription jobs observed as FAILED by language code.", "language")
+	TranscriptionJobDuration = Default.NewHistogramVec("cmtranscribe_transcription_job_duration_seconds",
+		"Time Amazon Transcribe took from job creation to completion by language code.", JobDurationBuckets, "language")
+	SubmissionQueueLength = Default.NewGaugeFunc("cmtranscribe_transcription_submission_queue_length",
+		"Number of transcription jobs waiting for a free concurrent-job slot.", nil)
+)
+
+// アップロードとカスタムボキャブラリ
+var (
+	UploadBytes = Default.NewCounterVec("cmtranscribe_upload_bytes_total",
+		"Bytes of media uploaded by upload method.", "method")
+	VocabularyOperations = Default.NewCounterVec("cmtranscribe_vocabulary_operations_total",
+		"Number of custom vocabulary and vocabulary filter operations by resource, operation and result.", "resource", "operation", "result")
+)
+
+// startTime プロセスの開始時刻
+var startTime = time.Now()
+
+// プロセス
+func init() {
+	Default.NewGaugeFunc("cmtranscribe_process_start_time_seconds",
+		"Start time of the process since the Unix epoch in seconds.", func() float64 { return float64(startTime.UnixNano()) / 1e9 })
+	Default.NewGaugeFunc("cmtranscribe_goroutines",
+		"Number of goroutines that currently exist.", func() float64 { return float64(runtime.NumGoroutine()) })
+}
+
+// Result エラーの有無から操作の結果のラベルを返します
+func Result(err error) string {
+	if err != nil {
+		return ResultError
+	}
+	return ResultSuccess
+}
+
+// ObserveSince 開始時刻からの経過秒数をヒストグラムに加えます
+func ObserveSince(h *HistogramVec, start time.Time, labelValues ...string) {
+	h.Observe(time.Since(start).Seconds(), labelValues...)
+}
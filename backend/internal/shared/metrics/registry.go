@@ -0,0 +1,295 @@
+package metrics
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// ContentType Prometheus のテキスト形式 (0.0.4) のContent-Type
+const ContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+// labelSeparator ラベルの値を連結して系列のキーにする際の区切り (ラベルの値に現れない文字)
+const labelSeparator = "\xff"
+
+// Registry メトリクスを保持し、Prometheus のテキスト形式で出力します
+type Registry struct {
+	mu         sync.Mutex
+	collectors []collector
+	names      map[string]bool
+}
+
+// collector 1つのメトリクス (同じ名前の系列の集まり)
+type collector interface {
+	write(w *bufio.Writer)
+}
+
+// NewRegistry 新しいRegistryを作成します
+func NewRegistry() *Registry {
+	return &Registry{names: map[string]bool{}}
+}
+
+// register メトリクスを登録します。同じ名前のメトリクスは登録できません
+func (r *Registry) register(name string, c collector) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.names[name] {
+		panic(fmt.Sprintf("metric %s is already registered", name))
+	}
+	r.names[name] = true
+	r.collectors = append(r.collectors, c)
+}
+
+// Write 登録したすべてのメトリクスを登録した順に出力します
+func (r *Registry) Write(w io.Writer) error {
+	r.mu.Lock()
+	collectors := append([]collector(nil), r.collectors...)
+	r.mu.Unlock()
+
+	buffered := bufio.NewWriter(w)
+	for _, c := range collectors {
+		c.write(buffered)
+	}
+	return buffered.Flush()
+}
+
+// Handler メトリクスを返す http.Handler を返します
+func (r *Registry) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("Content-Type", ContentType)
+		_ = r.Write(w)
+	})
+}
+
+// metricDesc メトリクスの名前、説明、ラベルの名前
+type metricDesc struct {
+	name       string
+	help       string
+	kind       string // counter, gauge, histogram
+	labelNames []string
+}
+
+// writeHeader HELP と TYPE の行を出力します
+func (d *metricDesc) writeHeader(w *bufio.Writer) {
+	fmt.Fprintf(w, "# HELP %s %s\n", d.name, escapeHelp(d.help))
+	fmt.Fprintf(w, "# TYPE %s %s\n", d.name, d.kind)
+}
+
+// key ラベルの値から系列のキーを返します。ラベルの数が合わない場合はプログラムの誤りのため panic します
+func (d *metricDesc) key(labelValues []string) string {
+	if len(labelValues) != len(d.labelNames) {
+		panic(fmt.Sprintf("metric %s has %d labels but %d values were given", d.name, len(d.labelNames), len(labelValues)))
+	}
+	return strings.Join(labelValues, labelSeparator)
+}
+
+// CounterVec ラベルの値ごとに増加し続ける値を保持するメトリクス
+type CounterVec struct {
+	metricDesc
+	mu     sync.Mutex
+	series map[string]*counterSeries
+}
+
+type counterSeries struct {
+	labelValues []string
+	value       float64
+}
+
+// NewCounterVec カウンターを作成して登録します
+func (r *Registry) NewCounterVec(name, help string, labelNames ...string) *CounterVec {
+	c := &CounterVec{
+		metricDesc: metricDesc{name: name, help: help, kind: "counter", labelNames: labelNames},
+		series:     map[string]*counterSeries{},
+	}
+	r.register(name, c)
+	return c
+}
+
+// Inc ラベルの値の系列に1を加えます
+func (c *CounterVec) Inc(labelValues ...string) {
+	c.Add(1, labelValues...)
+}
+
+// Add ラベルの値の系列に value を加えます。負の値は無視します
+func (c *CounterVec) Add(value float64, labelValues ...string) {
+	if value < 0 {
+		return
+	}
+	key := c.key(labelValues)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	series, ok := c.series[key]
+	if !ok {
+		series = &counterSeries{labelValues: append([]string(nil), labelValues...)}
+		c.series[key] = series
+	}
+	series.value += value
+}
+
+func (c *CounterVec) write(w *bufio.Writer) {
+	c.writeHeader(w)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	keys := make([]string, 0, len(c.series))
+	for key := range c.series {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys) // 出力の順序を安定させる
+	for _, key := range keys {
+		series := c.series[key]
+		fmt.Fprintf(w, "%s%s %s\n", c.name, formatLabels(c.labelNames, series.labelValues), formatValue(series.value))
+	}
+}
+
+// HistogramVec ラベルの値ごとに観測値の分布を保持するメトリクス
+type HistogramVec struct {
+	metricDesc
+	buckets []float64 // 昇順のバケットの上限 (+Inf を除く)
+	mu      sync.Mutex
+	series  map[string]*histogramSeries
+}
+
+type histogramSeries struct {
+	labelValues []string
+	counts      []uint64 // バケットごとの観測数 (累積ではない)。最後の要素は +Inf
+	sum         float64
+	count       uint64
+}
+
+// NewHistogramVec ヒストグラムを作成して登録します
+func (r *Registry) NewHistogramVec(name, help string, buckets []float64, labelNames ...string) *HistogramVec {
+	sorted := append([]float64(nil), buckets...)
+	sort.Float64s(sorted)
+	h := &HistogramVec{
+		metricDesc: metricDesc{name: name, help: help, kind: "histogram", labelNames: labelNames},
+		buckets:    sorted,
+		series:     map[string]*histogramSeries{},
+	}
+	r.register(name, h)
+	return h
+}
+
+// Observe ラベルの値の系列に観測値を加えます
+func (h *HistogramVec) Observe(value float64, labelValues ...string) {
+	key := h.key(labelValues)
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	series, ok := h.series[key]
+	if !ok {
+		series = &histogramSeries{
+			labelValues: append([]string(nil), labelValues...),
+			counts:      make([]uint64, len(h.buckets)+1),
+		}
+		h.series[key] = series
+	}
+	series.counts[sort.SearchFloat64s(h.buckets, value)]++
+	series.sum += value
+	series.count++
+}
+
+func (h *HistogramVec) write(w *bufio.Writer) {
+	h.writeHeader(w)
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	labelNames := append(append([]string(nil), h.labelNames...), "le")
+	keys := make([]string, 0, len(h.series))
+	for key := range h.series {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys) // 出力の順序を安定させる
+	for _, key := range keys {
+		series := h.series[key]
+		cumulative := uint64(0)
+		for i, count := range series.counts {
+			cumulative += count
+			upperBound := math.Inf(1)
+			if i < len(h.buckets) {
+				upperBound = h.buckets[i]
+			}
+			labelValues := append(append([]string(nil), series.labelValues...), formatValue(upperBound))
+			fmt.Fprintf(w, "%s_bucket%s %d\n", h.name, formatLabels(labelNames, labelValues), cumulative)
+		}
+		labels := formatLabels(h.labelNames, series.labelValues)
+		fmt.Fprintf(w, "%s_sum%s %s\n", h.name, labels, formatValue(series.sum))
+		fmt.Fprintf(w, "%s_count%s %d\n", h.name, labels, series.count)
+	}
+}
+
+// GaugeFunc 出力のたびに関数で値を取得するメトリクス (待ち行列の長さなど)
+type GaugeFunc struct {
+	metricDesc
+	mu    sync.Mutex
+	value func() float64
+}
+
+// NewGaugeFunc ゲージを作成して登録します。値の関数は後から SetFunc で設定できます
+func (r *Registry) NewGaugeFunc(name, help string, value func() float64) *GaugeFunc {
+	g := &GaugeFunc{
+		metricDesc: metricDesc{name: name, help: help, kind: "gauge"},
+		value:      value,
+	}
+	r.register(name, g)
+	return g
+}
+
+// SetFunc 値を取得する関数を置き換えます
+func (g *GaugeFunc) SetFunc(value func() float64) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.value = value
+}
+
+func (g *GaugeFunc) write(w *bufio.Writer) {
+	g.mu.Lock()
+	value := g.value
+	g.mu.Unlock()
+	if value == nil {
+		return
+	}
+	g.writeHeader(w)
+	fmt.Fprintf(w, "%s %s\n", g.name, formatValue(value()))
+}
+
+// formatLabels ラベルを {name="value",...} の形式にします。ラベルが無い場合は空です
+func formatLabels(names, values []string) string {
+	if len(names) == 0 {
+		return ""
+	}
+	pairs := make([]string, len(names))
+	for i, name := range names {
+		pairs[i] = name + `="` + escapeLabelValue(values[i]) + `"`
+	}
+	return "{" + strings.Join(pairs, ",") + "}"
+}
+
+// formatValue 値をテキスト形式の数値にします
+func formatValue(value float64) string {
+	switch {
+	case math.IsInf(value, 1):
+		return "+Inf"
+	case math.IsInf(value, -1):
+		return "-Inf"
+	case math.IsNaN(value):
+		return "NaN"
+	}
+	return strconv.FormatFloat(value, 'g', -1, 64)
+}
+
+var (
+	helpEscaper       = strings.NewReplacer(`\`, `\\`, "\n", `\n`)
+	labelValueEscaper = strings.NewReplacer(`\`, `\\`, "\n", `\n`, `"`, `\"`)
+)
+
+func escapeHelp(help string) string {
+	return helpEscaper.Replace(help)
+}
+
+func escapeLabelValue(value string) string {
+	return labelValueEscaper.Replace(value)
+}
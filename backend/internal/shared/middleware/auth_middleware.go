@@ -0,0 +1,58 @@
+package middleware
+
+import (
+	"cmTranscribe/internal/domain/domainerr"
+	"cmTranscribe/internal/domain/model"
+	"cmTranscribe/internal/domain/service"
+	"cmTranscribe/internal/shared/logger"
+	"cmTranscribe/internal/shared/utils"
+	"net/http"
+	"strings"
+)
+
+// APIKeyHeader Authorization ヘッダーの代わりにAPIキーを送るヘッダー
+const APIKeyHeader = "X-API-Key"
+
+// AuthMiddleware リクエストの認証情報を検証し、呼び出し元をコンテキストに設定するミドルウェアを返します。
+// 認証情報は "Authorization: Bearer <APIキーまたはJWT>" または X-API-Key ヘッダーで受け取ります。
+// publicPaths とその下のパス (署名付きURLで認可されるストレージのオブジェクトなど) は認証しません
+func AuthMiddleware(authenticator service.Authenticator, publicPaths ...string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if isPublicPath(r, publicPaths) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			credential := bearerToken(r)
+			if credential == "" {
+				credential = r.Header.Get(APIKeyHeader)
+			}
+			if credential == "" {
+				w.Header().Set("WWW-Authenticate", "Bearer")
+				utils.RespondWithDomainError(w, r, domainerr.New(domainerr.Unauthorized, "authentication_required", "Authorization header is required"), "Authentication failed")
+				return
+			}
+
+			principal, err := authenticator.Authenticate(r.Context(), credential)
+			if err != nil {
+				w.Header().Set("WWW-Authenticate", `Bearer error="invalid_token"`)
+				utils.RespondWithDomainError(w, r, err, "Authentication failed")
+				return
+			}
+
+			ctx := model.ContextWithPrincipal(r.Context(), principal)
+			ctx = logger.NewContext(ctx, logger.FromContext(ctx).With("subject", principal.Subject))
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// bearerToken Authorization ヘッダーの Bearer トークンを返します
+func bearerToken(r *http.Request) string {
+	scheme, token, ok := strings.Cut(r.Header.Get("Authorization"), " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") {
+		return ""
+	}
+	return strings.TrimSpace(token)
+}
@@ -0,0 +1,105 @@
+package middleware
+
+import (
+	"cmTranscribe/internal/domain/domainerr"
+	"cmTranscribe/internal/domain/model"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// stubAuthenticator キーと呼び出し元の対応で認証する Authenticator
+type stubAuthenticator map[string]*model.Principal
+
+func (a stubAuthenticator) Authenticate(_ context.Context, credential string) (*model.Principal, error) {
+	principal, ok := a[credential]
+	if !ok {
+		return nil, domainerr.New(domainerr.Unauthorized, "invalid_credentials", "credentials are invalid")
+	}
+	return principal, nil
+}
+
+// okHandler 呼び出されたことを記録して 200 を返すハンドラ
+func okHandler(called *bool) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*called = true
+		w.WriteHeader(http.StatusOK)
+	})
+}
+
+func TestAuthMiddlewarePublicPaths(t *testing.T) {
+	tests := []struct {
+		name        string
+		metricsPath string
+		path        string
+		wantPublic  bool
+	}{
+		{name: "api with metrics", metricsPath: "/metrics", path: "/api/transcriptions", wantPublic: false},
+		{name: "metrics", metricsPath: "/metrics", path: "/metrics", wantPublic: true},
+		{name: "under metrics", metricsPath: "/metrics", path: "/metrics/x", wantPublic: true},
+		{name: "metrics as a name prefix", metricsPath: "/metrics", path: "/metricsx", wantPublic: false},
+		{name: "metrics path /a", metricsPath: "/a", path: "/api/transcriptions", wantPublic: false},
+		{name: "metrics path /", metricsPath: "/", path: "/api/transcriptions", wantPublic: false},
+		{name: "storage objects", metricsPath: "", path: "/api/storage/objects", wantPublic: true},
+		{name: "storage objects as a name prefix", metricsPath: "", path: "/api/storage/objectsx", wantPublic: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			publicPaths := []string{"/api/storage/objects"}
+			if tt.metricsPath != "" {
+				publicPaths = append(publicPaths, tt.metricsPath)
+			}
+			called := false
+			handler := AuthMiddleware(stubAuthenticator{}, publicPaths...)(okHandler(&called))
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, tt.path, nil))
+
+			if called != tt.wantPublic {
+				t.Errorf("handler called = %v, want %v", called, tt.wantPublic)
+			}
+			if !tt.wantPublic && recorder.Code != http.StatusUnauthorized {
+				t.Errorf("status = %d, want 401", recorder.Code)
+			}
+		})
+	}
+}
+
+func TestAuthMiddlewareCredentials(t *testing.T) {
+	authenticator := stubAuthenticator{"alicekey": {Subject: "alice"}}
+	tests := []struct {
+		name        string
+		header      string
+		value       string
+		wantStatus  int
+		wantSubject string
+	}{
+		{name: "bearer", header: "Authorization", value: "Bearer alicekey", wantStatus: http.StatusOK, wantSubject: "alice"},
+		{name: "lowercase bearer", header: "Authorization", value: "bearer alicekey", wantStatus: http.StatusOK, wantSubject: "alice"},
+		{name: "api key header", header: APIKeyHeader, value: "alicekey", wantStatus: http.StatusOK, wantSubject: "alice"},
+		{name: "basic scheme", header: "Authorization", value: "Basic alicekey", wantStatus: http.StatusUnauthorized},
+		{name: "invalid key", header: APIKeyHeader, value: "bobkey", wantStatus: http.StatusUnauthorized},
+		{name: "missing", wantStatus: http.StatusUnauthorized},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			subject := ""
+			handler := AuthMiddleware(authenticator)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				subject = model.PrincipalFromContext(r.Context()).Subject
+			}))
+			request := httptest.NewRequest(http.MethodGet, "/api/transcriptions", nil)
+			if tt.header != "" {
+				request.Header.Set(tt.header, tt.value)
+			}
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, request)
+
+			if recorder.Code != tt.wantStatus || subject != tt.wantSubject {
+				t.Errorf("status = %d, subject = %q, want %d, %q", recorder.Code, subject, tt.wantStatus, tt.wantSubject)
+			}
+			if tt.wantStatus == http.StatusUnauthorized && recorder.Header().Get("WWW-Authenticate") == "" {
+				t.Error("WWW-Authenticate header is missing")
+			}
+		})
+	}
+}
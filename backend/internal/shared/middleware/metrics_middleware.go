@@ -0,0 +1,43 @@
+package middleware
+
+import (
+	"cmTranscribe/internal/shared/metrics"
+	"github.com/gorilla/mux"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// MetricsMiddleware リクエストの数とレイテンシをメソッドとルートごとに記録するミドルウェアを返します。
+// パスのパラメータで系列が増えないよう、パスではなくルートの定義 (例: /api/transcriptions/{jobName}) をラベルにするため、mux.Router の Use で登録します
+func MetricsMiddleware() func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			route := "unknown"
+			if current := mux.CurrentRoute(r); current != nil {
+				if template, err := current.GetPathTemplate(); err == nil {
+					route = template
+				}
+			}
+
+			recorder := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+			start := time.Now()
+			next.ServeHTTP(recorder, r)
+
+			method := metricsMethod(r.Method)
+			metrics.HTTPRequests.Inc(method, route, strconv.Itoa(recorder.status))
+			metrics.ObserveSince(metrics.HTTPRequestDuration, start, method, route)
+		})
+	}
+}
+
+// metricsMethod メソッドをラベルの値にします。認証の前に記録するため、任意のメソッドで系列が増えないよう、
+// 標準のメソッド以外は "other" にまとめます
+func metricsMethod(method string) string {
+	switch method {
+	case http.MethodGet, http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete, http.MethodHead, http.MethodOptions:
+		return method
+	default:
+		return "other"
+	}
+}
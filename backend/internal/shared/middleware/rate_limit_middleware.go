@@ -0,0 +1,239 @@
+package middleware
+
+import (
+	"cmTranscribe/internal/domain/domainerr"
+	"cmTranscribe/internal/domain/model"
+	"cmTranscribe/internal/shared/utils"
+	"fmt"
+	"github.com/gorilla/mux"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// レート制限のバケットを分ける単位
+const (
+	RateLimitScopeTenant = "tenant" // テナントごと (テナントが無い場合は呼び出し元ごと)
+	RateLimitScopeKey    = "key"    // 呼び出し元 (APIキーやJWTの subject) ごと
+)
+
+// rateLimitSweepInterval 使われなくなったバケットを削除する間隔
+const rateLimitSweepInterval = time.Minute
+
+// RateLimit トークンバケットの設定。PerSecond が0の場合は制限しません
+type RateLimit struct {
+	PerSecond float64 // 1秒あたりに補充するトークンの数
+	Burst     int     // バケットの容量 (連続して受け付けるリクエストの数)
+}
+
+// RateLimiter 呼び出し元とルートの組み合わせごとに、トークンバケットでリクエストの数を制限します
+type RateLimiter struct {
+	scope        string
+	defaultLimit RateLimit
+	routeLimits  map[string]RateLimit // "<METHOD> <ルートのパス>" または "<ルートのパス>" とレート制限の対応
+
+	mu        sync.Mutex
+	buckets   map[string]*tokenBucket
+	lastSweep time.Time
+}
+
+// tokenBucket 呼び出し元とルートの組み合わせ1つ分のバケット
+type tokenBucket struct {
+	tokens    float64
+	updatedAt time.Time
+	limit     RateLimit
+}
+
+// NewRateLimiter は、既定のレート制限と "[<METHOD> ]<ルートのパス>=<1秒あたりの数>[:<バースト>]" をカンマで区切ったルートごとの設定からRateLimiterを作成します。
+// 例: "POST /api/transcriptions/start=0.5:2,/api/s3/upload=0.2"
+func NewRateLimiter(scope string, defaultLimit RateLimit, routes string) (*RateLimiter, error) {
+	if scope != RateLimitScopeTenant && scope != RateLimitScopeKey {
+		return nil, fmt.Errorf("rate limit scope must be tenant or key: %s", scope)
+	}
+	defaultLimit, err := normalizeRateLimit(defaultLimit)
+	if err != nil {
+		return nil, err
+	}
+	limiter := &RateLimiter{
+		scope:        scope,
+		defaultLimit: defaultLimit,
+		routeLimits:  map[string]RateLimit{},
+		buckets:      map[string]*tokenBucket{},
+		lastSweep:    time.Now(),
+	}
+	for _, entry := range strings.Split(routes, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		route, value, ok := cutLast(entry, "=")
+		if !ok || strings.TrimSpace(route) == "" {
+			return nil, fmt.Errorf("rate limit entry must be [<METHOD> ]<path>=<per second>[:<burst>]: %s", entry)
+		}
+		limit, err := parseRateLimit(value)
+		if err != nil {
+			return nil, fmt.Errorf("rate limit of %s is invalid: %w", route, err)
+		}
+		method, path, hasMethod := strings.Cut(strings.TrimSpace(route), " ")
+		if hasMethod {
+			route = strings.ToUpper(method) + " " + strings.TrimSpace(path)
+		}
+		limiter.routeLimits[strings.TrimSpace(route)] = limit
+	}
+	return limiter, nil
+}
+
+// Enabled 制限するルートがあるかどうかを返します
+func (l *RateLimiter) Enabled() bool {
+	if l.defaultLimit.PerSecond > 0 {
+		return true
+	}
+	for _, limit := range l.routeLimits {
+		if limit.PerSecond > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// RateLimitMiddleware 呼び出し元とルートごとのレート制限を超えたリクエストを 429 で拒否するミドルウェアを返します。
+// ルートのパスを使うため、mux.Router の Use で登録します
+func RateLimitMiddleware(limiter *RateLimiter) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			route := r.URL.Path
+			if current := mux.CurrentRoute(r); current != nil {
+				if template, err := current.GetPathTemplate(); err == nil {
+					route = template
+				}
+			}
+			limit := limiter.limitFor(r.Method, route)
+			if limit.PerSecond <= 0 {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			allowed, remaining, retryAfter := limiter.take(limiter.clientKey(r)+" "+r.Method+" "+route, limit, time.Now())
+			w.Header().Set("X-RateLimit-Limit", strconv.Itoa(limit.Burst))
+			w.Header().Set("X-RateLimit-Remaining", strconv.Itoa(remaining))
+			if !allowed {
+				w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(retryAfter.Seconds()))))
+				utils.RespondWithDomainError(w, r, domainerr.New(domainerr.Throttled, "rate_limited", "rate limit of %g requests per second for %s %s exceeded", limit.PerSecond, r.Method, route), "Too many requests")
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// limitFor メソッドとルートのパスに適用するレート制限を返します。メソッド付きの設定を優先します
+func (l *RateLimiter) limitFor(method, route string) RateLimit {
+	if limit, ok := l.routeLimits[method+" "+route]; ok {
+		return limit
+	}
+	if limit, ok := l.routeLimits[route]; ok {
+		return limit
+	}
+	return l.defaultLimit
+}
+
+// clientKey バケットを分ける呼び出し元の識別子を返します。認証が無効な場合は接続元のIPアドレスを使います
+func (l *RateLimiter) clientKey(r *http.Request) string {
+	if l.scope == RateLimitScopeTenant {
+		if tenant := model.TenantFromContext(r.Context()); tenant != nil {
+			return "tenant:" + tenant.ID
+		}
+	}
+	if principal := model.PrincipalFromContext(r.Context()); principal != nil {
+		return "subject:" + principal.Subject
+	}
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	return "ip:" + host
+}
+
+// take バケットからトークンを1つ取り出します。
+// 取り出せなかった場合は、次のトークンが補充されるまでの時間を返します
+func (l *RateLimiter) take(key string, limit RateLimit, now time.Time) (bool, int, time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.sweep(now)
+	bucket, ok := l.buckets[key]
+	if !ok || bucket.limit != limit {
+		bucket = &tokenBucket{tokens: float64(limit.Burst), updatedAt: now, limit: limit}
+		l.buckets[key] = bucket
+	}
+	bucket.refill(now)
+	if bucket.tokens < 1 {
+		wait := time.Duration((1 - bucket.tokens) / limit.PerSecond * float64(time.Second))
+		return false, 0, wait
+	}
+	bucket.tokens--
+	return true, int(bucket.tokens), 0
+}
+
+// sweep 満杯まで補充されたバケットを削除します。削除しても次のリクエストで同じ状態のバケットが作られます
+func (l *RateLimiter) sweep(now time.Time) {
+	if now.Sub(l.lastSweep) < rateLimitSweepInterval {
+		return
+	}
+	l.lastSweep = now
+	for key, bucket := range l.buckets {
+		bucket.refill(now)
+		if bucket.tokens >= float64(bucket.limit.Burst) {
+			delete(l.buckets, key)
+		}
+	}
+}
+
+// refill 前回から経過した時間の分だけトークンを補充します
+func (b *tokenBucket) refill(now time.Time) {
+	elapsed := now.Sub(b.updatedAt).Seconds()
+	if elapsed <= 0 {
+		return
+	}
+	b.tokens = math.Min(float64(b.limit.Burst), b.tokens+elapsed*b.limit.PerSecond)
+	b.updatedAt = now
+}
+
+// parseRateLimit "<1秒あたりの数>[:<バースト>]" 形式のレート制限を読み取ります
+func parseRateLimit(value string) (RateLimit, error) {
+	rate, burst, hasBurst := strings.Cut(strings.TrimSpace(value), ":")
+	perSecond, err := strconv.ParseFloat(rate, 64)
+	if err != nil {
+		return RateLimit{}, fmt.Errorf("rate must be a number: %s", rate)
+	}
+	limit := RateLimit{PerSecond: perSecond}
+	if hasBurst {
+		if limit.Burst, err = strconv.Atoi(burst); err != nil {
+			return RateLimit{}, fmt.Errorf("burst must be an integer: %s", burst)
+		}
+	}
+	return normalizeRateLimit(limit)
+}
+
+// normalizeRateLimit レート制限を検証し、省略されたバーストを1秒あたりの数 (最低1) にします
+func normalizeRateLimit(limit RateLimit) (RateLimit, error) {
+	if limit.PerSecond < 0 || math.IsNaN(limit.PerSecond) || math.IsInf(limit.PerSecond, 0) || limit.Burst < 0 {
+		return RateLimit{}, fmt.Errorf("rate and burst must not be negative")
+	}
+	if limit.PerSecond > 0 && limit.Burst == 0 {
+		limit.Burst = int(math.Max(1, math.Ceil(limit.PerSecond)))
+	}
+	return limit, nil
+}
+
+// cutLast 最後の sep で文字列を分割します
+func cutLast(s, sep string) (before, after string, found bool) {
+	if i := strings.LastIndex(s, sep); i >= 0 {
+		return s[:i], s[i+len(sep):], true
+	}
+	return s, "", false
+}
@@ -0,0 +1,122 @@
+package middleware
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRateLimiterTake(t *testing.T) {
+	limiter, err := NewRateLimiter(RateLimitScopeKey, RateLimit{}, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	limit := RateLimit{PerSecond: 2, Burst: 3}
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	// バーストの分だけ連続して受け付けます
+	for want := 2; want >= 0; want-- {
+		allowed, remaining, _ := limiter.take("a", limit, now)
+		if !allowed || remaining != want {
+			t.Fatalf("take = %v, %d, want true, %d", allowed, remaining, want)
+		}
+	}
+
+	// 空になったら次のトークンが補充されるまでの時間を返します
+	allowed, remaining, retryAfter := limiter.take("a", limit, now)
+	if allowed || remaining != 0 || retryAfter != 500*time.Millisecond {
+		t.Fatalf("take = %v, %d, %v, want false, 0, 500ms", allowed, remaining, retryAfter)
+	}
+	allowed, _, retryAfter = limiter.take("a", limit, now.Add(200*time.Millisecond))
+	if allowed || retryAfter != 300*time.Millisecond {
+		t.Fatalf("take = %v, %v, want false, 300ms", allowed, retryAfter)
+	}
+
+	// 経過した時間の分だけ補充されます
+	if allowed, _, _ := limiter.take("a", limit, now.Add(500*time.Millisecond)); !allowed {
+		t.Fatal("expected a token after 500ms")
+	}
+
+	// 呼び出し元ごとに別のバケットを使います
+	if allowed, remaining, _ := limiter.take("b", limit, now); !allowed || remaining != 2 {
+		t.Fatalf("take = %v, %d, want true, 2", allowed, remaining)
+	}
+
+	// 補充してもバーストを超えません
+	if allowed, remaining, _ := limiter.take("a", limit, now.Add(time.Hour)); !allowed || remaining != 2 {
+		t.Fatalf("take = %v, %d, want true, 2", allowed, remaining)
+	}
+}
+
+func TestRateLimiterTakeLimitChange(t *testing.T) {
+	limiter, err := NewRateLimiter(RateLimitScopeKey, RateLimit{}, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	if allowed, _, _ := limiter.take("a", RateLimit{PerSecond: 1, Burst: 1}, now); !allowed {
+		t.Fatal("expected the first request to be allowed")
+	}
+	// 設定が変わったバケットは新しい設定で作り直します
+	if allowed, remaining, _ := limiter.take("a", RateLimit{PerSecond: 1, Burst: 5}, now); !allowed || remaining != 4 {
+		t.Fatalf("take = %v, %d, want true, 4", allowed, remaining)
+	}
+}
+
+func TestRateLimiterSweep(t *testing.T) {
+	limiter, err := NewRateLimiter(RateLimitScopeKey, RateLimit{}, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	limit := RateLimit{PerSecond: 1, Burst: 2}
+	now := limiter.lastSweep
+	limiter.take("a", limit, now)
+	limiter.take("a", limit, now)
+	limiter.take("b", RateLimit{PerSecond: 0.001, Burst: 1}, now)
+
+	limiter.take("c", limit, now.Add(rateLimitSweepInterval))
+	if _, ok := limiter.buckets["a"]; ok {
+		t.Error("a refilled bucket should be swept")
+	}
+	if _, ok := limiter.buckets["b"]; !ok {
+		t.Error("a bucket that is not full should be kept")
+	}
+}
+
+func TestNewRateLimiter(t *testing.T) {
+	tests := []struct {
+		name    string
+		scope   string
+		routes  string
+		method  string
+		route   string
+		want    RateLimit
+		wantErr bool
+	}{
+		{name: "default", scope: RateLimitScopeKey, method: "GET", route: "/api/x", want: RateLimit{PerSecond: 10, Burst: 20}},
+		{name: "route", scope: RateLimitScopeKey, routes: "/api/x=0.5:2", method: "GET", route: "/api/x", want: RateLimit{PerSecond: 0.5, Burst: 2}},
+		{name: "method takes precedence", scope: RateLimitScopeKey, routes: "/api/x=1, post /api/x=3", method: "POST", route: "/api/x", want: RateLimit{PerSecond: 3, Burst: 3}},
+		{name: "burst defaults to at least one", scope: RateLimitScopeKey, routes: "/api/x=0.2", method: "GET", route: "/api/x", want: RateLimit{PerSecond: 0.2, Burst: 1}},
+		{name: "unlimited route", scope: RateLimitScopeTenant, routes: "/api/x=0", method: "GET", route: "/api/x", want: RateLimit{}},
+		{name: "invalid scope", scope: "ip", wantErr: true},
+		{name: "missing rate", scope: RateLimitScopeKey, routes: "/api/x", wantErr: true},
+		{name: "negative rate", scope: RateLimitScopeKey, routes: "/api/x=-1", wantErr: true},
+		{name: "invalid burst", scope: RateLimitScopeKey, routes: "/api/x=1:a", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			limiter, err := NewRateLimiter(tt.scope, RateLimit{PerSecond: 10, Burst: 20}, tt.routes)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("expected an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := limiter.limitFor(tt.method, tt.route); got != tt.want {
+				t.Errorf("limitFor() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
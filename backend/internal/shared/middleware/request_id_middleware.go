@@ -0,0 +1,84 @@
+package middleware
+
+import (
+	"cmTranscribe/internal/shared/logger"
+	"github.com/google/uuid"
+	"net/http"
+	"time"
+)
+
+// RequestIDHeader リクエストIDを受け渡すヘッダー
+const RequestIDHeader = "X-Request-ID"
+
+// maxRequestIDLength クライアントから受け取るリクエストIDの最大長
+const maxRequestIDLength = 128
+
+// RequestIDMiddleware リクエストIDを引き継ぐか新しく割り当て、リクエストIDを含むLoggerをコンテキストに設定するミドルウェア。
+// レスポンスのヘッダーにもリクエストIDを返し、リクエストごとにアクセスログを出力します
+func RequestIDMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestID := r.Header.Get(RequestIDHeader)
+		if !isValidRequestID(requestID) {
+			requestID = uuid.NewString()
+		}
+		w.Header().Set(RequestIDHeader, requestID)
+
+		requestLogger := logger.Default().With("request_id", requestID)
+		ctx := logger.WithRequestID(r.Context(), requestID)
+		ctx = logger.NewContext(ctx, requestLogger)
+
+		recorder := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+		start := time.Now()
+		next.ServeHTTP(recorder, r.WithContext(ctx))
+
+		requestLogger.Info("request completed",
+			"method", r.Method,
+			"path", r.URL.Path,
+			"status", recorder.status,
+			"bytes", recorder.bytes,
+			"duration_ms", float64(time.Since(start).Microseconds())/1000,
+			"remote_addr", r.RemoteAddr,
+		)
+	})
+}
+
+// isValidRequestID ログやヘッダーにそのまま出力できるリクエストIDかどうかを返します
+func isValidRequestID(requestID string) bool {
+	if requestID == "" || len(requestID) > maxRequestIDLength {
+		return false
+	}
+	for _, c := range requestID {
+		if c < 0x21 || c > 0x7e {
+			return false
+		}
+	}
+	return true
+}
+
+// statusRecorder レスポンスのステータスコードとサイズを記録する http.ResponseWriter
+type statusRecorder struct {
+	http.ResponseWriter
+	status      int
+	bytes       int64
+	wroteHeader bool
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	if !r.wroteHeader {
+		r.status = status
+		r.wroteHeader = true
+	}
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func (r *statusRecorder) Write(p []byte) (int, error) {
+	r.wroteHeader = true
+	n, err := r.ResponseWriter.Write(p)
+	r.bytes += int64(n)
+	return n, err
+}
+
+// Unwrap http.ResponseController が元の http.ResponseWriter を使えるようにします
+func (r *statusRecorder) Unwrap() http.ResponseWriter {
+	return r.ResponseWriter
+}
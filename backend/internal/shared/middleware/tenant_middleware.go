@@ -0,0 +1,80 @@
+package middleware
+
+import (
+	"cmTranscribe/internal/domain/domainerr"
+	"cmTranscribe/internal/domain/model"
+	"cmTranscribe/internal/domain/repository"
+	"cmTranscribe/internal/shared/logger"
+	"cmTranscribe/internal/shared/utils"
+	"net/http"
+	"strings"
+)
+
+// TenantHeader 呼び出し元のテナントを指定するヘッダー
+const TenantHeader = "X-Tenant-ID"
+
+// TenantMiddleware リクエストのテナントを解決し、コンテキストに設定するミドルウェアを返します。
+// 認証が有効な場合、管理者以外は認証情報に紐付いたテナントだけを使います。
+// X-Tenant-ID ヘッダーでテナントを選べるのは、管理者と認証が無効な場合だけです。
+// テナントを指定しない管理者は、すべてのテナントのリソースを対象にします
+func TenantMiddleware(tenants repository.TenantRepository, publicPaths ...string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if isPublicPath(r, publicPaths) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			principal := model.PrincipalFromContext(r.Context())
+			tenantID := strings.TrimSpace(r.Header.Get(TenantHeader))
+			if principal != nil && !principal.Admin {
+				// 管理者以外は、認証情報に紐付いたテナント以外を指定できない
+				if principal.Tenant == "" {
+					utils.RespondWithDomainError(w, r, domainerr.New(domainerr.Forbidden, "tenant_forbidden", "credentials are not bound to a tenant"), "Failed to resolve tenant")
+					return
+				}
+				if tenantID != "" && tenantID != principal.Tenant {
+					utils.RespondWithDomainError(w, r, domainerr.New(domainerr.Forbidden, "tenant_forbidden", "credentials are not allowed to access tenant %s", tenantID), "Failed to resolve tenant")
+					return
+				}
+			}
+			if tenantID == "" && principal != nil {
+				tenantID = principal.Tenant
+			}
+			if tenantID == "" {
+				if principal != nil && principal.Admin {
+					next.ServeHTTP(w, r)
+					return
+				}
+				utils.RespondWithDomainError(w, r, domainerr.New(domainerr.Validation, "tenant_required", "%s header is required", TenantHeader), "Failed to resolve tenant")
+				return
+			}
+
+			tenant, err := tenants.FindByID(tenantID)
+			if err != nil {
+				// 存在しないテナントと参照できないテナントを区別しない
+				utils.RespondWithDomainError(w, r, domainerr.New(domainerr.Forbidden, "tenant_forbidden", "tenant %s is not available", tenantID), "Failed to resolve tenant")
+				return
+			}
+
+			ctx := model.ContextWithTenant(r.Context(), tenant)
+			ctx = logger.NewContext(ctx, logger.FromContext(ctx).With("tenant", tenant.ID))
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// isPublicPath パスが publicPaths のいずれかと一致するか、その下のパスかどうかを返します。
+// "/api" が "/api/transcriptions" に一致しないよう、パスの区切りの単位で比べます
+func isPublicPath(r *http.Request, publicPaths []string) bool {
+	for _, publicPath := range publicPaths {
+		publicPath = strings.TrimSuffix(publicPath, "/")
+		if publicPath == "" {
+			continue
+		}
+		if r.URL.Path == publicPath || strings.HasPrefix(r.URL.Path, publicPath+"/") {
+			return true
+		}
+	}
+	return false
+}
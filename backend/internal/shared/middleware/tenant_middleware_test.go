@@ -0,0 +1,86 @@
+package middleware
+
+import (
+	"cmTranscribe/internal/domain/domainerr"
+	"cmTranscribe/internal/domain/model"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// stubTenantRepository IDとテナントの対応で検索する TenantRepository
+type stubTenantRepository map[string]*model.Tenant
+
+func (r stubTenantRepository) FindByID(id string) (*model.Tenant, error) {
+	tenant, ok := r[id]
+	if !ok {
+		return nil, domainerr.New(domainerr.NotFound, "tenant_not_found", "tenant %s does not exist", id)
+	}
+	return tenant, nil
+}
+
+func (r stubTenantRepository) FindAll() ([]*model.Tenant, error) {
+	tenants := make([]*model.Tenant, 0, len(r))
+	for _, tenant := range r {
+		tenants = append(tenants, tenant)
+	}
+	return tenants, nil
+}
+
+func TestTenantMiddleware(t *testing.T) {
+	tenants := stubTenantRepository{
+		"team-a": model.NewTenant("team-a", "", "", "", "", model.TenantQuota{}),
+		"team-b": model.NewTenant("team-b", "", "", "", "", model.TenantQuota{}),
+	}
+	alice := &model.Principal{Subject: "alice", Tenant: "team-a"}
+	carol := &model.Principal{Subject: "carol"}
+	root := &model.Principal{Subject: "root", Admin: true}
+
+	tests := []struct {
+		name       string
+		principal  *model.Principal // nil の場合は認証が無効
+		header     string
+		wantStatus int
+		wantTenant string // 空の場合はテナントを設定しない
+	}{
+		{name: "bound tenant", principal: alice, wantStatus: http.StatusOK, wantTenant: "team-a"},
+		{name: "header matches the bound tenant", principal: alice, header: "team-a", wantStatus: http.StatusOK, wantTenant: "team-a"},
+		{name: "header selects another tenant", principal: alice, header: "team-b", wantStatus: http.StatusForbidden},
+		{name: "header selects an unknown tenant", principal: alice, header: "team-x", wantStatus: http.StatusForbidden},
+		{name: "unbound credentials", principal: carol, wantStatus: http.StatusForbidden},
+		{name: "unbound credentials with a header", principal: carol, header: "team-a", wantStatus: http.StatusForbidden},
+		{name: "admin selects a tenant", principal: root, header: "team-b", wantStatus: http.StatusOK, wantTenant: "team-b"},
+		{name: "admin without a tenant", principal: root, wantStatus: http.StatusOK},
+		{name: "admin selects an unknown tenant", principal: root, header: "team-x", wantStatus: http.StatusForbidden},
+		{name: "authentication disabled", header: "team-b", wantStatus: http.StatusOK, wantTenant: "team-b"},
+		{name: "authentication disabled without a header", wantStatus: http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			called := false
+			tenantID := ""
+			handler := TenantMiddleware(tenants)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				called = true
+				if tenant := model.TenantFromContext(r.Context()); tenant != nil {
+					tenantID = tenant.ID
+				}
+			}))
+			request := httptest.NewRequest(http.MethodGet, "/api/transcriptions", nil)
+			if tt.principal != nil {
+				request = request.WithContext(model.ContextWithPrincipal(request.Context(), tt.principal))
+			}
+			if tt.header != "" {
+				request.Header.Set(TenantHeader, tt.header)
+			}
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, request)
+
+			if recorder.Code != tt.wantStatus || tenantID != tt.wantTenant {
+				t.Errorf("status = %d, tenant = %q, want %d, %q", recorder.Code, tenantID, tt.wantStatus, tt.wantTenant)
+			}
+			if called != (tt.wantStatus == http.StatusOK) {
+				t.Errorf("handler called = %v, want %v", called, tt.wantStatus == http.StatusOK)
+			}
+		})
+	}
+}
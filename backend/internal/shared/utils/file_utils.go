@@ -0,0 +1,41 @@
+package utils
+
+import (
+	"path/filepath"
+	"strings"
+	"unicode"
+)
+
+// maxFileNameLength サニタイズ後のファイル名の最大文字数
+const maxFileNameLength = 100
+
+// SanitizeFileName クライアントから受け取ったファイル名からディレクトリ部分と安全でない文字を取り除きます。
+// 何も残らない場合は fallback を返します
+func SanitizeFileName(name, fallback string) string {
+	// Windowsのパス区切りも含めてディレクトリ部分を取り除く
+	name = filepath.Base(strings.ReplaceAll(name, "\\", "/"))
+
+	var builder strings.Builder
+	for _, r := range name {
+		switch {
+		case unicode.IsLetter(r) || unicode.IsNumber(r) || r == '-' || r == '_' || r == '.':
+			builder.WriteRune(r)
+		case unicode.IsSpace(r):
+			builder.WriteRune('_')
+		}
+	}
+
+	// 先頭のドットを取り除き、隠しファイルや "." ".." にならないようにする
+	sanitized := strings.TrimLeft(builder.String(), ".")
+	if runes := []rune(sanitized); len(runes) > maxFileNameLength {
+		ext := filepath.Ext(sanitized)
+		if len([]rune(ext)) > maxFileNameLength/2 {
+			ext = ""
+		}
+		sanitized = string(runes[:maxFileNameLength-len([]rune(ext))]) + ext
+	}
+	if sanitized == "" {
+		return fallback
+	}
+	return sanitized
+}
@@ -1,10 +1,41 @@
 package utils
 
 import (
+	"cmTranscribe/internal/domain/domainerr"
+	"cmTranscribe/internal/shared/logger"
 	"encoding/json"
 	"net/http"
+	"strings"
 )
 
+// problemTypePrefix エラーコードから problem+json の type を組み立てる際の接頭辞
+const problemTypePrefix = "urn:cmtranscribe:problem:"
+
+// Problem RFC 7807 の problem+json 形式のエラー応答
+type Problem struct {
+	Type      string `json:"type"`
+	Title     string `json:"title"`
+	Status    int    `json:"status"`
+	Detail    string `json:"detail,omitempty"`
+	Instance  string `json:"instance,omitempty"`
+	Code      string `json:"code"`                 // 安定したエラーコード
+	RequestID string `json:"request_id,omitempty"` // ログと突き合わせるためのリクエストID
+}
+
+// problemStatuses ドメインエラーの種類とHTTPステータスコードの対応
+var problemStatuses = map[domainerr.Kind]int{
+	domainerr.NotFound:             http.StatusNotFound,
+	domainerr.Conflict:             http.StatusConflict,
+	domainerr.Validation:           http.StatusBadRequest,
+	domainerr.Unprocessable:        http.StatusUnprocessableEntity,
+	domainerr.TooLarge:             http.StatusRequestEntityTooLarge,
+	domainerr.UnsupportedMediaType: http.StatusUnsupportedMediaType,
+	domainerr.Unauthorized:         http.StatusUnauthorized,
+	domainerr.Forbidden:            http.StatusForbidden,
+	domainerr.Throttled:            http.StatusTooManyRequests,
+	domainerr.Upstream:             http.StatusBadGateway,
+}
+
 // RespondWithJSON JSON応答を返します
 func RespondWithJSON(w http.ResponseWriter, code int, payload interface{}) {
 	w.Header().Set("Content-Type", "application/json")
@@ -14,7 +45,76 @@ func RespondWithJSON(w http.ResponseWriter, code int, payload interface{}) {
 	}
 }
 
-// RespondWithError JSON形式のエラー応答を返します
+// RespondWithError problem+json 形式のエラー応答を返します。エラーコードはステータスコードから決まります
 func RespondWithError(w http.ResponseWriter, code int, message string) {
-	RespondWithJSON(w, code, map[string]string{"message": message})
+	writeProblem(w, Problem{
+		Type:   problemTypePrefix + statusCode(code),
+		Title:  http.StatusText(code),
+		Status: code,
+		Detail: message,
+		Code:   statusCode(code),
+	})
+}
+
+// RespondWithDomainError エラーを problem+json 形式で返します。
+// ドメインエラーはその種類に応じたステータスコードとエラーコードで返し、
+// それ以外のエラーは内容を隠して 500 と message を返します
+func RespondWithDomainError(w http.ResponseWriter, r *http.Request, err error, message string) {
+	domainErr, ok := domainerr.As(err)
+	if !ok {
+		logger.FromContext(r.Context()).Error(message, "error", err)
+		problem := Problem{
+			Type:      problemTypePrefix + statusCode(http.StatusInternalServerError),
+			Title:     http.StatusText(http.StatusInternalServerError),
+			Status:    http.StatusInternalServerError,
+			Detail:    message,
+			Instance:  r.URL.Path,
+			Code:      statusCode(http.StatusInternalServerError),
+			RequestID: logger.RequestIDFromContext(r.Context()),
+		}
+		writeProblem(w, problem)
+		return
+	}
+
+	status, ok := problemStatuses[domainErr.Kind]
+	if !ok {
+		status = http.StatusInternalServerError
+	}
+	if status >= http.StatusInternalServerError {
+		logger.FromContext(r.Context()).Error(message, "error", err, "code", domainErr.Code)
+	}
+	code := domainErr.Code
+	if code == "" {
+		code = string(domainErr.Kind)
+	}
+	writeProblem(w, Problem{
+		Type:      problemTypePrefix + code,
+		Title:     http.StatusText(status),
+		Status:    status,
+		Detail:    domainErr.Message,
+		Instance:  r.URL.Path,
+		Code:      code,
+		RequestID: logger.RequestIDFromContext(r.Context()),
+	})
+}
+
+// writeProblem problem+json を書き込みます
+func writeProblem(w http.ResponseWriter, problem Problem) {
+	w.Header().Set("Content-Type", "application/problem+json")
+	w.WriteHeader(problem.Status)
+	if err := json.NewEncoder(w).Encode(problem); err != nil {
+		logger.Default().Error("Failed to encode problem response", "error", err)
+	}
+}
+
+// statusCode ステータスコードを snake_case のエラーコードに変換します (例: 404 → not_found)
+func statusCode(status int) string {
+	if status == http.StatusInternalServerError {
+		return "internal_error"
+	}
+	text := http.StatusText(status)
+	if text == "" {
+		return "error"
+	}
+	return strings.ToLower(strings.NewReplacer(" ", "_", "-", "_", "'", "").Replace(text))
 }
@@ -1,8 +1,8 @@
 package validator
 
 import (
-	"fmt"
-	"log"
+	"cmTranscribe/internal/domain/domainerr"
+	"cmTranscribe/internal/shared/logger"
 )
 
 type Validatable interface {
@@ -13,9 +13,12 @@ func Validate(v Validatable) error {
 	err := v.Validate()
 	if err != nil {
 		// ログ出力
-		log.Printf("Validation failed: %v", err)
-		// エラーメッセージのカスタマイズ
-		return fmt.Errorf("validation error: %v", err)
+		logger.Default().Info("Validation failed", "error", err)
+		// 入力が不正であることを示すドメインエラーに変換
+		if _, ok := domainerr.As(err); ok {
+			return err
+		}
+		return domainerr.New(domainerr.Validation, "validation_failed", "%v", err)
 	}
 	return nil
 }
@@ -0,0 +1,3 @@
+#!/bin/sh
+# LocalStack の起動後に、バックエンドが使うバケットを作成する
+awslocal s3 mb "s3://${S3_BUCKET_NAME:-transcribe-local}" || true
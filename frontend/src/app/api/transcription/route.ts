@@ -1,4 +1,5 @@
 import { NextResponse } from 'next/server';
+import { backendHeaders } from '@/lib/backend';
 
 const BACKEND_URL = process.env.NEXT_PUBLIC_BACKEND_URL; // GolangのバックエンドURLを環境変数から取得
 
@@ -17,6 +18,7 @@ export async function POST(request: Request) {
 
         const uploadResponse = await fetch(`${BACKEND_URL}/api/s3/upload`, {
             method: 'POST',
+            headers: backendHeaders(),
             body: uploadFormData,
         });
 
@@ -31,9 +33,9 @@ export async function POST(request: Request) {
         // 2. S3にアップロードされたファイルを使ってTranscribeジョブを開始
         const transcribeResponse = await fetch(`${BACKEND_URL}/api/transcriptions/start`, {  // Transcribeジョブ開始用のエンドポイント
             method: 'POST',
-            headers: {
+            headers: backendHeaders({
                 'Content-Type': 'application/json',
-            },
+            }),
             body: JSON.stringify({
                 jobName: jobName,
                 mediaUri: s3FileUrl,  // アップロードされたファイルのURLを送信
@@ -1,4 +1,5 @@
 import {NextResponse} from 'next/server';
+import { backendHeaders } from '@/lib/backend';
 
 const BACKEND_URL = process.env.NEXT_PUBLIC_BACKEND_URL;
 
@@ -17,7 +18,7 @@ async function sendToBackend(method: string, body: any) {
     try {
         const response = await fetch(`${BACKEND_URL}/api/custom/vocabulary`, {
             method,
-            headers: { 'Content-Type': 'application/json' },
+            headers: backendHeaders({ 'Content-Type': 'application/json' }),
             body: JSON.stringify(body),
         });
 
@@ -32,9 +33,10 @@ async function sendToBackend(method: string, body: any) {
             const contentType = response.headers.get('content-type');
             let errorMessage: string;
 
-            if (contentType && contentType.includes('application/json')) {
+            // エラーは application/problem+json で返される
+            if (contentType && contentType.includes('json')) {
                 const errorData = await response.json();
-                errorMessage = errorData.message;
+                errorMessage = errorData.detail ?? errorData.message;
                 // console.error('Error response from backend (JSON):', errorData);
             } else {
                 errorMessage = await response.text();
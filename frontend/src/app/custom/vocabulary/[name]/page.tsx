@@ -1,9 +1,11 @@
 import { Suspense } from 'react';
 import Client from './client';
+import { backendHeaders } from '@/lib/backend';
 
 // サーバーサイドでデータを取得する関数
 async function fetchVocabularyData(name: string) {
     const response = await fetch(`${process.env.NEXT_PUBLIC_BACKEND_URL}/api/custom/vocabulary?name=${name}`, {  // GolangバックエンドのURLを指定
+        headers: backendHeaders(),
         cache: 'no-store', // 最新のデータを取得するためキャッシュを無効化
     });
 
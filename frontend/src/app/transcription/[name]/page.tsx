@@ -1,12 +1,13 @@
 import React from 'react';
 import Client from './client';
+import { backendHeaders } from '@/lib/backend';
 
 const fetchTranscriptionData = async (jobName: string) => {
     try {
         const response = await fetch(`${process.env.NEXT_PUBLIC_BACKEND_URL}/api/transcriptions/content?jobName=${jobName}`, {
-            headers: {
+            headers: backendHeaders({
                 'Content-Type': 'application/json',
-            },
+            }),
             cache: 'no-store',
         });
 
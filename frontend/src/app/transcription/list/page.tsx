@@ -1,12 +1,13 @@
 import Client from './client';
+import { backendHeaders } from '@/lib/backend';
 
 // Transcription Jobsデータをサーバーサイドで取得する関数
 async function fetchTranscriptionJobs() {
     try {
-        const response = await fetch(`${process.env.NEXT_PUBLIC_BACKEND_URL}/api/transcriptions`, {
-            headers: {
+        const response = await fetch(`${process.env.NEXT_PUBLIC_BACKEND_URL}/api/transcriptions?limit=100`, {
+            headers: backendHeaders({
                 'Content-Type': 'application/json',
-            },
+            }),
             cache: 'no-store',
         });
 
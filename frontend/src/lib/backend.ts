@@ -0,0 +1,15 @@
+// バックエンドのAPIを呼び出す際に付けるヘッダーを返す (サーバーサイド専用)
+// バックエンドで認証が有効な場合は、BACKEND_API_KEY のAPIキーまたはトークンを送る
+// バックエンドでテナントを使う場合は、BACKEND_TENANT_ID のテナントを指定する
+export function backendHeaders(headers: Record<string, string> = {}): Record<string, string> {
+    const result = { ...headers };
+    const apiKey = process.env.BACKEND_API_KEY;
+    if (apiKey) {
+        result.Authorization = `Bearer ${apiKey}`;
+    }
+    const tenantId = process.env.BACKEND_TENANT_ID;
+    if (tenantId) {
+        result['X-Tenant-ID'] = tenantId;
+    }
+    return result;
+}